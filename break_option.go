@@ -13,6 +13,35 @@ const (
 	// BreakIndicator. If no indicator sensitive break can be performed a strict break will be performed,
 	// potentially working with the given separator as a suffix.
 	BreakModeIndicatorSensitive
+
+	// BreakModeHyphenation breaks the line after spaces and hyphens, at soft hyphens (U+00AD) and at the
+	// hyphenation points found by the Hyphenator. The Separator (default "-") is appended to hyphenated lines.
+	BreakModeHyphenation
+
+	// BreakModeUnicode breaks the line at the break opportunities of the Unicode Line Breaking Algorithm (UAX #14),
+	// so it keeps non-breaking spaces and word joiners together and does not start a line with closing punctuation.
+	// A line without any opportunity is broken mid-word like in strict mode. The Separator (default "-") is
+	// appended to lines broken at a soft hyphen (U+00AD).
+	BreakModeUnicode
+
+	// BreakModeWordSegmentation works like BreakModeUnicode, but also breaks the lines between the words
+	// of scripts written without spaces (Thai, Lao, Khmer, Myanmar), which are found with the Dictionary.
+	BreakModeWordSegmentation
+
+	// BreakModeCJK works like BreakModeUnicode with the Japanese line breaking rules (kinsoku shori):
+	// closing brackets, small kana and punctuation such as 。、 never start a line, opening brackets never end one.
+	// The forbidden characters are pushed to the next line, or hang past the line end with PunctuationHang.
+	BreakModeCJK
+)
+
+// PunctuationMode defines how BreakModeCJK handles the punctuation which does not fit at the end of a line.
+type PunctuationMode int
+
+const (
+	// PunctuationPush moves the punctuation and the character before it to the next line.
+	PunctuationPush PunctuationMode = iota
+	// PunctuationHang lets commas and full stops hang past the end of the line.
+	PunctuationHang
 )
 
 var (
@@ -30,10 +59,29 @@ type BreakOption struct {
 	Mode BreakMode
 	// BreakIndicator is taken into account when using indicator sensitive mode to avoid mid-word line breaks
 	BreakIndicator rune
-	// Separator will act as a suffix for mid-word breaks when using strict mode
+	// Separator will act as a suffix for mid-word breaks when using strict mode, and for hyphenated lines
+	// when using the other modes ("-" if empty)
 	Separator string
+	// Hyphenator finds the hyphenation points of words when using hyphenation mode, it may be nil
+	Hyphenator *Hyphenator
+	// Dictionary is used to find the words when using word segmentation mode, DefaultThaiDictionary if nil
+	Dictionary *WordDictionary
+	// Punctuation defines how the line-end punctuation is handled when using CJK mode
+	Punctuation PunctuationMode
+	// CJKLatinSpacing inserts a space between CJK characters and Latin letters or digits when using CJK mode.
+	// The spaces are part of the returned lines, so they are also in the text drawn by MultiCell and in the
+	// text copied or extracted from the pdf.
+	CJKLatinSpacing bool
 }
 
 func (bo BreakOption) HasSeparator() bool {
 	return bo.Separator != ""
 }
+
+// hyphenSeparator returns the separator appended to the lines broken at a hyphenation point.
+func (bo BreakOption) hyphenSeparator() string {
+	if bo.Separator == "" {
+		return "-"
+	}
+	return bo.Separator
+}
@@ -22,22 +22,26 @@ var ErrContentTypeNotFound = errors.New("contentType not found")
 
 type cacheContentText struct {
 	//---setup---
-	rectangle      *Rect
-	textColor      ICacheColorText
-	grayFill       float64
-	txtColorMode   string
-	fontCountIndex int //Curr.FontFontCount+1
-	fontSize       float64
-	fontStyle      int
-	charSpacing    float64
-	setXCount      int //จำนวนครั้งที่ใช้ setX
-	x, y           float64
-	fontSubset     *SubsetFontObj
-	pageheight     float64
-	contentType    int
-	cellOpt        CellOption
-	lineWidth      float64
-	text           string
+	rectangle         *Rect
+	textColor         ICacheColorText
+	grayFill          float64
+	txtColorMode      string
+	fontCountIndex    int //Curr.FontFontCount+1
+	fontSize          float64
+	fontStyle         int
+	charSpacing       float64
+	wordSpacing       float64
+	horizontalScaling float64 //percent, 0 means 100
+	decoration        *TextDecoration
+	rise              float64 //baseline shift set with SetTextRise
+	setXCount         int     //จำนวนครั้งที่ใช้ setX
+	x, y              float64
+	fontSubset        *SubsetFontObj
+	pageheight        float64
+	contentType       int
+	cellOpt           CellOption
+	lineWidth         float64
+	text              string
 	//---result---
 	cellWidthPdfUnit, textWidthPdfUnit float64
 	cellHeightPdfUnit                  float64
@@ -58,6 +62,10 @@ func (c *cacheContentText) isSame(cache cacheContentText) bool {
 		c.fontSize == cache.fontSize &&
 		c.fontStyle == cache.fontStyle &&
 		c.charSpacing == cache.charSpacing &&
+		c.wordSpacing == cache.wordSpacing &&
+		c.horizontalScaling == cache.horizontalScaling &&
+		c.decoration.equal(cache.decoration) &&
+		c.rise == cache.rise &&
 		c.setXCount == cache.setXCount &&
 		c.y == cache.y &&
 		c.isPlaceHolder == cache.isPlaceHolder {
@@ -150,18 +158,53 @@ func (c *cacheContentText) write(w io.Writer, protection *PDFProtection) error {
 		}
 	}
 
+	state := c.textState()
+	if state.boldStroke != 0 {
+		// the line width and stroke color of synthetic bold are restored after the text
+		io.WriteString(w, "q\n")
+	}
 	if _, err := io.WriteString(w, "BT\n"); err != nil {
 		return err
 	}
 
-	fmt.Fprintf(w, "%0.2f %0.2f TD\n", x, y)
-	fmt.Fprintf(w, "/F%d %s Tf %s Tc\n", c.fontCountIndex, FormatFloatTrim(c.fontSize), FormatFloatTrim(c.charSpacing))
+	fontSize := state.fontSize(c.fontSize)
+	if state.skew != 0 {
+		fmt.Fprintf(w, "1 0 %s 1 %0.2f %0.2f Tm\n", FormatFloatTrim(state.skew), x, y)
+	} else {
+		fmt.Fprintf(w, "%0.2f %0.2f TD\n", x, y)
+	}
+	fmt.Fprintf(w, "/F%d %s Tf %s Tc\n", c.fontCountIndex, FormatFloatTrim(fontSize), FormatFloatTrim(state.charSpacing))
+	if c.horizontalScaling != 0 && c.horizontalScaling != 100 {
+		fmt.Fprintf(w, "%s Tz\n", FormatFloatTrim(c.horizontalScaling))
+	}
+	if state.rise != 0 {
+		fmt.Fprintf(w, "%s Ts\n", FormatFloatTrim(state.rise))
+	}
 
 	if c.txtColorMode == "color" {
 		c.textColor.write(w, protection)
 	}
+	if state.boldStroke != 0 {
+		// fill and stroke with the color of the text
+		if c.txtColorMode == "color" {
+			c.textColor.writeStroke(w)
+		} else {
+			fmt.Fprintf(w, "%.2f G\n", c.grayFill)
+		}
+		fmt.Fprintf(w, "%s w 2 Tr\n", FormatFloatTrim(state.boldStroke))
+	}
 	io.WriteString(w, "[<")
 
+	hScale := 1.0
+	if c.horizontalScaling != 0 {
+		hScale = c.horizontalScaling / 100
+	}
+	renderingMode := 0
+	if state.boldStroke != 0 {
+		renderingMode = 2
+	}
+	var colorGlyphs []positionedGlyph
+	pos := 0.0 // position of the glyph from the start of the text
 	unitsPerEm := int(c.fontSubset.ttfp.UnitsPerEm())
 	var leftRune rune
 	var leftRuneIndex uint
@@ -180,21 +223,45 @@ func (c *cacheContentText) write(w io.Writer, protection *PDFProtection) error {
 			pairvalPdfUnit = convertTTFUnit2PDFUnit(int(pairval), unitsPerEm)
 			if pairvalPdfUnit != 0 {
 				fmt.Fprintf(w, ">%d<", (-1)*pairvalPdfUnit)
+				pos += float64(pairvalPdfUnit) * fontSize / 1000 * hScale
 			}
 		}
 
-		fmt.Fprintf(w, "%04X", glyphindex)
+		if c.fontSubset.color.isColorGlyph(glyphindex) {
+			// the outline of a color glyph is invisible, it keeps its advance and its character for text extraction
+			io.WriteString(w, ">] TJ\n3 Tr\n[<")
+			c.fontSubset.writeGlyph(w, glyphindex)
+			fmt.Fprintf(w, ">] TJ\n%d Tr\n[<", renderingMode)
+			colorGlyphs = append(colorGlyphs, positionedGlyph{glyph: glyphindex, x: pos})
+		} else {
+			c.fontSubset.writeGlyph(w, glyphindex)
+		}
+		pos += (float64(c.fontSubset.GlyphIndexToPdfWidth(glyphindex))*fontSize/1000 + state.charSpacing) * hScale
+		// Tw only applies to the single-byte code 32, so word spacing is done with TJ offsets
+		if r == ' ' && c.wordSpacing != 0 {
+			fmt.Fprintf(w, ">%s<", FormatFloatTrim(-c.wordSpacing*1000/fontSize))
+			pos += c.wordSpacing * hScale
+		}
 		leftRune = r
 		leftRuneIndex = glyphindex
 	}
 
 	io.WriteString(w, ">] TJ\n")
+	// text state parameters outlive the text object, so reset the ones other text does not set
+	if c.horizontalScaling != 0 && c.horizontalScaling != 100 {
+		io.WriteString(w, "100 Tz\n")
+	}
+	if state.rise != 0 {
+		io.WriteString(w, "0 Ts\n")
+	}
 	io.WriteString(w, "ET\n")
+	if state.boldStroke != 0 {
+		io.WriteString(w, "Q\n")
+	}
+	c.drawColorGlyphs(w, protection, x, y, state, colorGlyphs)
 
-	if c.fontStyle&Underline == Underline {
-		if err := c.underline(w); err != nil {
-			return err
-		}
+	if err := c.decorate(w, x, y); err != nil {
+		return err
 	}
 
 	c.drawBorder(w)
@@ -202,6 +269,67 @@ func (c *cacheContentText) write(w io.Writer, protection *PDFProtection) error {
 	return nil
 }
 
+// positionedGlyph is a glyph at x from the start of the text.
+type positionedGlyph struct {
+	glyph uint
+	x     float64
+}
+
+// drawColorGlyphs draws the layers or bitmaps of the color glyphs of the text over their invisible outlines,
+// marked as artifacts since the characters of the text come from the outlines.
+func (c *cacheContentText) drawColorGlyphs(w io.Writer, protection *PDFProtection, x, y float64, state textState, glyphs []positionedGlyph) {
+	if len(glyphs) == 0 {
+		return
+	}
+	fontSize := state.fontSize(c.fontSize)
+	hScale := 1.0
+	io.WriteString(w, "/Artifact BMC\nq\n")
+	fmt.Fprintf(w, "/F%d %s Tf\n", c.fontCountIndex, FormatFloatTrim(fontSize))
+	if c.horizontalScaling != 0 && c.horizontalScaling != 100 {
+		fmt.Fprintf(w, "%s Tz\n", FormatFloatTrim(c.horizontalScaling))
+		hScale = c.horizontalScaling / 100
+	}
+	if state.rise != 0 {
+		fmt.Fprintf(w, "%s Ts\n", FormatFloatTrim(state.rise))
+	}
+	for _, g := range glyphs {
+		gx := x + g.x
+		layers := c.fontSubset.color.layers[g.glyph]
+		for _, layer := range layers {
+			alpha, ok := c.fontSubset.colorAlphas[layer.a]
+			if ok {
+				fmt.Fprintf(w, "q\n/GS%d gs\n", alpha)
+			}
+			fmt.Fprintf(w, "BT\n1 0 %s 1 %0.2f %0.2f Tm\n", FormatFloatTrim(state.skew), gx, y)
+			if !layer.textColor {
+				fmt.Fprintf(w, "%.3f %.3f %.3f rg\n", float64(layer.r)/255, float64(layer.g)/255, float64(layer.b)/255)
+			} else if c.txtColorMode == "color" {
+				c.textColor.write(w, protection)
+			} else {
+				fmt.Fprintf(w, "%.2f g\n", c.grayFill)
+			}
+			io.WriteString(w, "<")
+			c.fontSubset.writeGlyph(w, layer.glyph)
+			io.WriteString(w, "> Tj\nET\n")
+			if ok {
+				io.WriteString(w, "Q\n")
+			}
+		}
+		index, ok := c.fontSubset.colorImages[g.glyph]
+		if len(layers) > 0 || !ok {
+			continue
+		}
+		// the unit square of the image is mapped to the box of the bitmap, skewed with the text
+		bitmap := c.fontSubset.color.bitmaps[g.glyph]
+		scale := fontSize / float64(bitmap.ppem)
+		bx, by := bitmap.x*scale*hScale, bitmap.y*scale+state.rise
+		bw, bh := bitmap.width*scale*hScale, bitmap.height*scale
+		fmt.Fprintf(w, "q\n%s 0 %s %s %0.2f %0.2f cm\n/I%d Do\nQ\n", FormatFloatTrim(bw), FormatFloatTrim(state.skew*bh), FormatFloatTrim(bh),
+			gx+bx+state.skew*by, y+by, index+1)
+	}
+	io.WriteString(w, "Q\nEMC\n")
+}
+
 func (c *cacheContentText) drawBorder(w io.Writer) error {
 
 	//stream.WriteString(fmt.Sprintf("%.2f w\n", 0.1))
@@ -297,7 +425,7 @@ func (c *cacheContentText) underline(w io.Writer) error {
 
 func (c *cacheContentText) createContent() (float64, float64, error) {
 
-	cellWidthPdfUnit, cellHeightPdfUnit, textWidthPdfUnit, err := createContent(c.fontSubset, c.text, c.fontSize, c.charSpacing, c.rectangle)
+	cellWidthPdfUnit, cellHeightPdfUnit, textWidthPdfUnit, err := createContentWithState(c.fontSubset, c.text, c.fontSize, c.textState(), c.rectangle)
 	if err != nil {
 		return 0, 0, err
 	}
@@ -307,8 +435,89 @@ func (c *cacheContentText) createContent() (float64, float64, error) {
 	return cellWidthPdfUnit, cellHeightPdfUnit, nil
 }
 
+// textState returns the text state of the cache, including the size and rise of its Superscript and Subscript styles.
+func (c *cacheContentText) textState() textState {
+	sizeScale, rise := scriptSizeAndRise(c.fontSubset, c.fontSize, c.fontStyle)
+	state := textState{
+		charSpacing:       c.charSpacing,
+		wordSpacing:       c.wordSpacing,
+		horizontalScaling: c.horizontalScaling,
+		sizeScale:         sizeScale,
+		rise:              c.rise + rise,
+	}
+	return state.withSyntheticStyles(c.fontSubset, c.fontSize, c.fontStyle)
+}
+
+// textState is the part of the text state that changes the size or position of text.
+type textState struct {
+	charSpacing       float64 //Tc
+	wordSpacing       float64 //Tw
+	horizontalScaling float64 //Tz percent, 0 means 100
+	sizeScale         float64 //font size of superscripts and subscripts relative to the font size, 0 means 1
+	rise              float64 //Ts
+	boldStroke        float64 //line width of the stroke of synthetic bold, also added to charSpacing
+	skew              float64 //horizontal skew of synthetic italic
+}
+
+// withSyntheticStyles returns the state with the stroke and skew of the styles f draws synthetically.
+// The stroke widens each glyph by its line width, which is added to the char spacing.
+func (t textState) withSyntheticStyles(f *SubsetFontObj, fontSize float64, style int) textState {
+	synthetic := syntheticStyles(f, style)
+	if synthetic&Bold != 0 {
+		t.boldStroke = t.fontSize(fontSize) * syntheticBoldStroke
+		t.charSpacing += t.boldStroke
+	}
+	if synthetic&Italic != 0 {
+		t.skew = syntheticItalicSkew
+	}
+	return t
+}
+
+func (t textState) fontSize(fontSize float64) float64 {
+	if t.sizeScale == 0 {
+		return fontSize
+	}
+	return fontSize * t.sizeScale
+}
+
+// scriptSizeAndRise returns the relative font size and the baseline shift of the Superscript and Subscript styles,
+// as defined in the OS/2 table of the font.
+func scriptSizeAndRise(f *SubsetFontObj, fontSize float64, style int) (float64, float64) {
+	if f == nil || style&(Superscript|Subscript) == 0 {
+		return 0, 0
+	}
+	unitsPerEm := float64(f.ttfp.UnitsPerEm())
+	if style&Superscript == Superscript {
+		size, offset := float64(f.ttfp.SuperscriptYSize()), float64(f.ttfp.SuperscriptYOffset())
+		if size <= 0 || offset <= 0 {
+			size, offset = 0.65*unitsPerEm, 0.45*unitsPerEm
+		}
+		return size / unitsPerEm, offset / unitsPerEm * fontSize
+	}
+	size, offset := float64(f.ttfp.SubscriptYSize()), float64(f.ttfp.SubscriptYOffset())
+	if size <= 0 || offset <= 0 {
+		size, offset = 0.65*unitsPerEm, 0.14*unitsPerEm
+	}
+	return size / unitsPerEm, -offset / unitsPerEm * fontSize
+}
+
+func (t textState) scale() float64 {
+	if t.horizontalScaling == 0 {
+		return 1
+	}
+	return t.horizontalScaling / 100
+}
+
 func createContent(f *SubsetFontObj, text string, fontSize float64, charSpacing float64, rectangle *Rect) (float64, float64, float64, error) {
+	return createContentWithState(f, text, fontSize, textState{charSpacing: charSpacing}, rectangle)
+}
+
+func createContentWithState(f *SubsetFontObj, text string, baseFontSize float64, spacing textState, rectangle *Rect) (float64, float64, float64, error) {
 
+	fontSize := spacing.fontSize(baseFontSize)
+
+	charSpacing := spacing.charSpacing
+	countOfSpace := 0
 	unitsPerEm := int(f.ttfp.UnitsPerEm())
 	var leftRune rune
 	var leftRuneIndex uint
@@ -339,22 +548,30 @@ func createContent(f *SubsetFontObj, text string, fontSize float64, charSpacing
 		spaceWidthPdfUnit := convertTTFUnit2PDFUnit(int(spaceWidthInPt), unitsPerEm)
 
 		sumWidth += int(width) + int(pairvalPdfUnit) + spaceWidthPdfUnit
+		if r == ' ' {
+			countOfSpace++
+		}
 		leftRune = r
 		leftRuneIndex = glyphindex
 	}
 
+	textWidthPdfUnit := (float64(sumWidth)*(float64(fontSize)/1000.0) + spacing.wordSpacing*float64(countOfSpace)) * spacing.scale()
 	cellWidthPdfUnit := float64(0)
 	cellHeightPdfUnit := float64(0)
 	if rectangle == nil {
-		cellWidthPdfUnit = float64(sumWidth) * (float64(fontSize) / 1000.0)
-		typoAscender := convertTypoUnit(float64(f.ttfp.TypoAscender()), f.ttfp.UnitsPerEm(), float64(fontSize))
-		typoDescender := convertTypoUnit(float64(f.ttfp.TypoDescender()), f.ttfp.UnitsPerEm(), float64(fontSize))
+		cellWidthPdfUnit = textWidthPdfUnit
+		typoAscender := convertTypoUnit(float64(f.ttfp.TypoAscender()), f.ttfp.UnitsPerEm(), float64(baseFontSize))
+		typoDescender := convertTypoUnit(float64(f.ttfp.TypoDescender()), f.ttfp.UnitsPerEm(), float64(baseFontSize))
+		if fontSize != baseFontSize || spacing.rise != 0 {
+			// the raised or lowered text may extend the line
+			typoAscender = math.Max(typoAscender, spacing.rise+convertTypoUnit(float64(f.ttfp.TypoAscender()), f.ttfp.UnitsPerEm(), fontSize))
+			typoDescender = math.Min(typoDescender, spacing.rise+convertTypoUnit(float64(f.ttfp.TypoDescender()), f.ttfp.UnitsPerEm(), fontSize))
+		}
 		cellHeightPdfUnit = typoAscender - typoDescender
 	} else {
 		cellWidthPdfUnit = rectangle.W
 		cellHeightPdfUnit = rectangle.H
 	}
-	textWidthPdfUnit := float64(sumWidth) * (float64(fontSize) / 1000.0)
 	return cellWidthPdfUnit, cellHeightPdfUnit, textWidthPdfUnit, nil
 }
 
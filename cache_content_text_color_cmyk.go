@@ -14,6 +14,11 @@ func (c cacheContentTextColorCMYK) write(w io.Writer, protection *PDFProtection)
 	return nil
 }
 
+func (c cacheContentTextColorCMYK) writeStroke(w io.Writer) error {
+	fmt.Fprintf(w, "%.2f %.2f %.2f %.2f %s\n", float64(c.c)/100, float64(c.m)/100, float64(c.y)/100, float64(c.k)/100, colorTypeStrokeCMYK)
+	return nil
+}
+
 func (c cacheContentTextColorCMYK) equal(obj ICacheColorText) bool {
 	cmyk, ok := obj.(cacheContentTextColorCMYK)
 	if !ok {
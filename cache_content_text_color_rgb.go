@@ -14,6 +14,11 @@ func (c cacheContentTextColorRGB) write(w io.Writer, protection *PDFProtection)
 	return nil
 }
 
+func (c cacheContentTextColorRGB) writeStroke(w io.Writer) error {
+	fmt.Fprintf(w, "%.3f %.3f %.3f %s\n", float64(c.r)/255, float64(c.g)/255, float64(c.b)/255, colorTypeStrokeRGB)
+	return nil
+}
+
 func (c cacheContentTextColorRGB) equal(obj ICacheColorText) bool {
 	rgb, ok := obj.(cacheContentTextColorRGB)
 	if !ok {
@@ -0,0 +1,43 @@
+package gopdf
+
+import (
+	"fmt"
+	"io"
+	"math"
+)
+
+// placedGlyph is a glyph drawn at x, y (top-left origin) and rotated clockwise by angle radians.
+type placedGlyph struct {
+	index uint
+	x, y  float64
+	angle float64
+}
+
+type cacheContentTextOnPath struct {
+	pageHeight     float64
+	fontCountIndex int
+	fontSubset     *SubsetFontObj
+	fontSize       float64
+	textColor      ICacheColorText
+	txtColorMode   string
+	glyphs         []placedGlyph
+}
+
+func (c *cacheContentTextOnPath) write(w io.Writer, protection *PDFProtection) error {
+	if _, err := io.WriteString(w, "BT\n"); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "/F%d %s Tf\n", c.fontCountIndex, FormatFloatTrim(c.fontSize))
+	if c.txtColorMode == "color" && c.textColor != nil {
+		c.textColor.write(w, protection)
+	}
+	for _, g := range c.glyphs {
+		// the y axis of the page points up, so the clockwise angle becomes counterclockwise
+		cos, sin := math.Cos(-g.angle), math.Sin(-g.angle)
+		fmt.Fprintf(w, "%.5f %.5f %.5f %.5f %0.2f %0.2f Tm <", cos, sin, -sin, cos, g.x, c.pageHeight-g.y)
+		c.fontSubset.writeGlyph(w, g.index)
+		io.WriteString(w, "> Tj\n")
+	}
+	_, err := io.WriteString(w, "ET\n")
+	return err
+}
@@ -12,12 +12,14 @@ const Bottom = 1 //000001
 const Center = 16 //010000
 // Middle middle
 const Middle = 32 //100000
+// Justify justify, distributes the free space of a line between its words (the last line of a paragraph stays left aligned)
+const Justify = 64 //1000000
 // AllBorders allborders
 const AllBorders = 15 //001111
 
 // CellOption cell option
 type CellOption struct {
-	Align                  int //Allows to align the text. Possible values are: Left,Center,Right,Top,Bottom,Middle
+	Align                  int //Allows to align the text. Possible values are: Left,Center,Right,Justify,Top,Bottom,Middle
 	Border                 int //Indicates if borders must be drawn around the cell. Possible values are: Left, Top, Right, Bottom, ALL
 	Float                  int //Indicates where the current position should go after the call. Possible values are: Right, Bottom
 	Transparency           *Transparency
@@ -25,6 +27,7 @@ type CellOption struct {
 	CoefLineHeight         float64
 	CoefUnderlineThickness float64
 	BreakOption            *BreakOption
+	Decoration             *TextDecoration //How the Underline, Strikethrough and Overline styles are drawn, see SetTextDecoration
 
 	extGStateIndexes []int
 }
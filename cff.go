@@ -0,0 +1,571 @@
+package gopdf
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidCFF invalid CFF or CFF2 font program
+var ErrInvalidCFF = errors.New("invalid CFF font data")
+
+// dict operators of CFF and CFF2, two-byte operators are 1200 + second byte
+const (
+	cffOpCharset        = 15
+	cffOpEncoding       = 16
+	cffOpCharStrings    = 17
+	cffOpPrivate        = 18
+	cffOpSubrs          = 19
+	cffOpVSIndex        = 22
+	cffOpBlend          = 23
+	cffOpVariationStore = 24
+	cffOpROS            = 1230
+	cffOpCIDCount       = 1234
+	cffOpFDArray        = 1236
+	cffOpFDSelect       = 1237
+)
+
+// cffDictEntry is an operator of a DICT with its operands.
+type cffDictEntry struct {
+	op       int
+	operands []float64
+	raw      []byte // operands as found in the font, to write them back unchanged
+}
+
+// cffDict is a DICT of a CFF font: Top DICT, Font DICT or Private DICT.
+type cffDict []cffDictEntry
+
+// parseCFFDict parses the DICT data.
+func parseCFFDict(data []byte) (cffDict, error) {
+	var dict cffDict
+	var operands []float64
+	start := 0
+	for i := 0; i < len(data); {
+		b := data[i]
+		switch {
+		case b <= 27:
+			op := int(b)
+			i++
+			if b == 12 {
+				if i >= len(data) {
+					return nil, ErrInvalidCFF
+				}
+				op = 1200 + int(data[i])
+				i++
+			}
+			dict = append(dict, cffDictEntry{op: op, operands: operands, raw: data[start : i-cffOpLen(op)]})
+			operands = nil
+			start = i
+		case b == 28:
+			if i+3 > len(data) {
+				return nil, ErrInvalidCFF
+			}
+			operands = append(operands, float64(int16(binary.BigEndian.Uint16(data[i+1:]))))
+			i += 3
+		case b == 29:
+			if i+5 > len(data) {
+				return nil, ErrInvalidCFF
+			}
+			operands = append(operands, float64(int32(binary.BigEndian.Uint32(data[i+1:]))))
+			i += 5
+		case b == 30:
+			v, n := parseCFFReal(data[i+1:])
+			operands = append(operands, v)
+			i += 1 + n
+		case b >= 32 && b <= 246:
+			operands = append(operands, float64(int(b)-139))
+			i++
+		case b >= 247 && b <= 254:
+			if i+2 > len(data) {
+				return nil, ErrInvalidCFF
+			}
+			v := (int(b)-247)*256 + int(data[i+1]) + 108
+			if b >= 251 {
+				v = -(int(b)-251)*256 - int(data[i+1]) - 108
+			}
+			operands = append(operands, float64(v))
+			i += 2
+		default:
+			return nil, ErrInvalidCFF
+		}
+	}
+	return dict, nil
+}
+
+// cffRealNibbles are the characters of the nibbles of a real number, 0xd being reserved and 0xf the end.
+const cffRealNibbles = "0123456789.EE?-"
+
+// parseCFFReal parses the nibbles of a real number and returns its value and the number of bytes read.
+func parseCFFReal(data []byte) (float64, int) {
+	var s []byte
+	for i, b := range data {
+		for _, nibble := range []byte{b >> 4, b & 0x0f} {
+			if nibble == 0x0f {
+				v, _ := strconv.ParseFloat(string(s), 64)
+				return v, i + 1
+			}
+			s = append(s, cffRealNibbles[nibble])
+			if nibble == 0x0c {
+				s = append(s, '-')
+			}
+		}
+	}
+	return 0, len(data)
+}
+
+// cffRealBytes encodes v as a real number.
+func cffRealBytes(v float64) []byte {
+	s := strings.Replace(strconv.FormatFloat(v, 'g', -1, 64), "e", "E", 1)
+	s = strings.Replace(strings.Replace(s, "E+", "E", 1), "E-", "c", 1)
+	var nibbles []byte
+	for _, c := range []byte(s) {
+		switch c {
+		case '.':
+			nibbles = append(nibbles, 0x0a)
+		case 'E':
+			nibbles = append(nibbles, 0x0b)
+		case 'c':
+			nibbles = append(nibbles, 0x0c)
+		case '-':
+			nibbles = append(nibbles, 0x0e)
+		default:
+			nibbles = append(nibbles, c-'0')
+		}
+	}
+	nibbles = append(nibbles, 0x0f)
+	if len(nibbles)%2 != 0 {
+		nibbles = append(nibbles, 0x0f)
+	}
+	b := []byte{30}
+	for i := 0; i < len(nibbles); i += 2 {
+		b = append(b, nibbles[i]<<4|nibbles[i+1])
+	}
+	return b
+}
+
+// cffNumberBytes encodes v as an integer on 5 bytes or as a real number.
+func cffNumberBytes(v float64) []byte {
+	if v == math.Trunc(v) && v >= math.MinInt32 && v <= math.MaxInt32 {
+		i := int32(v)
+		return []byte{29, byte(i >> 24), byte(i >> 16), byte(i >> 8), byte(i)}
+	}
+	return cffRealBytes(v)
+}
+
+func cffOpLen(op int) int {
+	if op >= 1200 {
+		return 2
+	}
+	return 1
+}
+
+// get returns the operands of op, nil if the dict has no such operator.
+func (d cffDict) get(op int) []float64 {
+	for _, e := range d {
+		if e.op == op {
+			return e.operands
+		}
+	}
+	return nil
+}
+
+// without returns a copy of the dict without the operators ops.
+func (d cffDict) without(ops ...int) cffDict {
+	var dict cffDict
+next:
+	for _, e := range d {
+		for _, op := range ops {
+			if e.op == op {
+				continue next
+			}
+		}
+		dict = append(dict, e)
+	}
+	return dict
+}
+
+// bytes encodes the dict.
+func (d cffDict) bytes() []byte {
+	var b []byte
+	for _, e := range d {
+		b = append(b, e.raw...)
+		if e.op >= 1200 {
+			b = append(b, 12, byte(e.op-1200))
+		} else {
+			b = append(b, byte(e.op))
+		}
+	}
+	return b
+}
+
+// cffIntEntry returns an entry with its operands encoded on 5 bytes,
+// so that its size does not depend on the offsets it holds.
+func cffIntEntry(op int, values ...int) cffDictEntry {
+	e := cffDictEntry{op: op}
+	for _, v := range values {
+		e.operands = append(e.operands, float64(v))
+		e.raw = append(e.raw, 29, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+	}
+	return e
+}
+
+// readCFFIndex reads the INDEX at pos and returns its items and the position following it.
+// The count of a CFF2 INDEX is on 4 bytes instead of 2.
+func readCFFIndex(data []byte, pos int, cff2 bool) ([][]byte, int, error) {
+	countSize := 2
+	if cff2 {
+		countSize = 4
+	}
+	if pos < 0 || pos+countSize > len(data) {
+		return nil, 0, ErrInvalidCFF
+	}
+	var count int
+	if cff2 {
+		count = int(binary.BigEndian.Uint32(data[pos:]))
+	} else {
+		count = int(binary.BigEndian.Uint16(data[pos:]))
+	}
+	pos += countSize
+	if count == 0 {
+		return nil, pos, nil
+	}
+	if pos >= len(data) {
+		return nil, 0, ErrInvalidCFF
+	}
+	offSize := int(data[pos])
+	pos++
+	if offSize < 1 || offSize > 4 || pos+(count+1)*offSize > len(data) {
+		return nil, 0, ErrInvalidCFF
+	}
+	offset := func(i int) int {
+		v := 0
+		for _, b := range data[pos+i*offSize : pos+(i+1)*offSize] {
+			v = v<<8 | int(b)
+		}
+		return v
+	}
+	base := pos + (count+1)*offSize - 1
+	items := make([][]byte, count)
+	for i := range items {
+		start, end := base+offset(i), base+offset(i+1)
+		if start > end || end > len(data) {
+			return nil, 0, ErrInvalidCFF
+		}
+		items[i] = data[start:end]
+	}
+	return items, base + offset(count), nil
+}
+
+// cffIndexBytes encodes items as an INDEX.
+func cffIndexBytes(items [][]byte, cff2 bool) []byte {
+	var b []byte
+	if cff2 {
+		b = append(b, byte(len(items)>>24), byte(len(items)>>16))
+	}
+	b = append(b, byte(len(items)>>8), byte(len(items)))
+	if len(items) == 0 {
+		return b
+	}
+	size := 1
+	for _, item := range items {
+		size += len(item)
+	}
+	offSize := 1
+	for ; offSize < 4 && size >= 1<<(8*uint(offSize)); offSize++ {
+	}
+	b = append(b, byte(offSize))
+	offset := 1
+	writeOffset := func() {
+		for i := offSize - 1; i >= 0; i-- {
+			b = append(b, byte(offset>>(8*uint(i))))
+		}
+	}
+	writeOffset()
+	for _, item := range items {
+		offset += len(item)
+		writeOffset()
+	}
+	for _, item := range items {
+		b = append(b, item...)
+	}
+	return b
+}
+
+// cffPrivateFont is a Private DICT with its local subroutines,
+// and for CID-keyed and CFF2 fonts the Font DICT of the FDArray pointing to it.
+type cffPrivateFont struct {
+	dict    cffDict
+	private cffDict
+	subrs   [][]byte
+}
+
+// cffFont is a parsed CFF or CFF2 font program.
+type cffFont struct {
+	cff2         bool
+	name         []byte
+	top          cffDict
+	strings      [][]byte
+	globalSubrs  [][]byte
+	charStrings  [][]byte
+	fonts        []cffPrivateFont
+	fdSelect     []int // index of the font of each glyph, nil when there is one font
+	varStore     []byte
+	regionCounts []int // number of regions of each item variation data of the variation store
+}
+
+// parseCFF parses the content of a "CFF " or "CFF2" table.
+func parseCFF(data []byte) (*cffFont, error) {
+	if len(data) < 4 {
+		return nil, ErrInvalidCFF
+	}
+	c := &cffFont{}
+	var pos int
+	var err error
+	switch data[0] {
+	case 1:
+		var names, tops [][]byte
+		if names, pos, err = readCFFIndex(data, int(data[2]), false); err != nil {
+			return nil, err
+		}
+		if tops, pos, err = readCFFIndex(data, pos, false); err != nil {
+			return nil, err
+		}
+		if len(names) == 0 || len(tops) == 0 {
+			return nil, ErrInvalidCFF
+		}
+		c.name = names[0]
+		if c.top, err = parseCFFDict(tops[0]); err != nil {
+			return nil, err
+		}
+		if c.strings, pos, err = readCFFIndex(data, pos, false); err != nil {
+			return nil, err
+		}
+	case 2:
+		if len(data) < 5 {
+			return nil, ErrInvalidCFF
+		}
+		c.cff2 = true
+		pos = int(data[2])
+		end := pos + int(binary.BigEndian.Uint16(data[3:]))
+		if end > len(data) {
+			return nil, ErrInvalidCFF
+		}
+		if c.top, err = parseCFFDict(data[pos:end]); err != nil {
+			return nil, err
+		}
+		pos = end
+	default:
+		return nil, ErrInvalidCFF
+	}
+	if c.globalSubrs, _, err = readCFFIndex(data, pos, c.cff2); err != nil {
+		return nil, err
+	}
+
+	charStrings := c.top.get(cffOpCharStrings)
+	if len(charStrings) != 1 {
+		return nil, ErrInvalidCFF
+	}
+	if c.charStrings, _, err = readCFFIndex(data, int(charStrings[0]), c.cff2); err != nil {
+		return nil, err
+	}
+
+	if fdArray := c.top.get(cffOpFDArray); len(fdArray) == 1 {
+		dicts, _, err := readCFFIndex(data, int(fdArray[0]), c.cff2)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range dicts {
+			dict, err := parseCFFDict(d)
+			if err != nil {
+				return nil, err
+			}
+			font, err := parseCFFPrivate(data, dict, c.cff2)
+			if err != nil {
+				return nil, err
+			}
+			font.dict = dict
+			c.fonts = append(c.fonts, font)
+		}
+		if fdSelect := c.top.get(cffOpFDSelect); len(fdSelect) == 1 {
+			if c.fdSelect, err = parseCFFFDSelect(data, int(fdSelect[0]), len(c.charStrings), len(c.fonts)); err != nil {
+				return nil, err
+			}
+		}
+	} else if c.cff2 {
+		return nil, ErrInvalidCFF
+	} else {
+		font, err := parseCFFPrivate(data, c.top, false)
+		if err != nil {
+			return nil, err
+		}
+		c.fonts = append(c.fonts, font)
+	}
+
+	if varStore := c.top.get(cffOpVariationStore); c.cff2 && len(varStore) == 1 {
+		if err := c.parseVariationStore(data, int(varStore[0])); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// parseCFFPrivate reads the Private DICT and local subroutines pointed to by dict.
+func parseCFFPrivate(data []byte, dict cffDict, cff2 bool) (cffPrivateFont, error) {
+	var font cffPrivateFont
+	private := dict.get(cffOpPrivate)
+	if len(private) != 2 {
+		return font, nil
+	}
+	size, offset := int(private[0]), int(private[1])
+	if size < 0 || offset < 0 || offset+size > len(data) {
+		return font, ErrInvalidCFF
+	}
+	var err error
+	if font.private, err = parseCFFDict(data[offset : offset+size]); err != nil {
+		return font, err
+	}
+	if subrs := font.private.get(cffOpSubrs); len(subrs) == 1 {
+		if font.subrs, _, err = readCFFIndex(data, offset+int(subrs[0]), cff2); err != nil {
+			return font, err
+		}
+	}
+	return font, nil
+}
+
+// parseCFFFDSelect reads the font index of each glyph.
+func parseCFFFDSelect(data []byte, pos int, numGlyphs int, numFonts int) ([]int, error) {
+	if pos < 0 || pos >= len(data) {
+		return nil, ErrInvalidCFF
+	}
+	fds := make([]int, numGlyphs)
+	format := data[pos]
+	pos++
+	switch format {
+	case 0:
+		if pos+numGlyphs > len(data) {
+			return nil, ErrInvalidCFF
+		}
+		for i := range fds {
+			fds[i] = int(data[pos+i])
+		}
+	case 3, 4:
+		// ranges of glyphs sharing a font, first glyph and font on 2 and 1 bytes for format 3, 4 and 2 for format 4
+		glyphSize, fdSize := 2, 1
+		if format == 4 {
+			glyphSize, fdSize = 4, 2
+		}
+		read := func(p, size int) int {
+			v := 0
+			for _, b := range data[p : p+size] {
+				v = v<<8 | int(b)
+			}
+			return v
+		}
+		if pos+glyphSize > len(data) {
+			return nil, ErrInvalidCFF
+		}
+		count := read(pos, glyphSize)
+		pos += glyphSize
+		rangeSize := glyphSize + fdSize
+		if pos+count*rangeSize+glyphSize > len(data) {
+			return nil, ErrInvalidCFF
+		}
+		for i := 0; i < count; i++ {
+			first, fd := read(pos, glyphSize), read(pos+glyphSize, fdSize)
+			end := read(pos+rangeSize, glyphSize)
+			for g := first; g < end && g < numGlyphs; g++ {
+				fds[g] = fd
+			}
+			pos += rangeSize
+		}
+	default:
+		return nil, ErrInvalidCFF
+	}
+	for _, fd := range fds {
+		if fd >= numFonts {
+			return nil, ErrInvalidCFF
+		}
+	}
+	return fds, nil
+}
+
+// cffFDSelectBytes encodes the font index of each glyph with FDSelect format 3, or 4 if there are more than 256 fonts.
+func cffFDSelectBytes(fds []int, cff2 bool) []byte {
+	wide := false
+	for _, fd := range fds {
+		if fd > 0xff {
+			wide = true
+		}
+	}
+	put := func(b []byte, v, size int) []byte {
+		for i := size - 1; i >= 0; i-- {
+			b = append(b, byte(v>>(8*uint(i))))
+		}
+		return b
+	}
+	glyphSize, fdSize, format := 2, 1, byte(3)
+	if wide && cff2 {
+		glyphSize, fdSize, format = 4, 2, 4
+	}
+	var ranges []byte
+	count := 0
+	for g, fd := range fds {
+		if g == 0 || fd != fds[g-1] {
+			ranges = put(ranges, g, glyphSize)
+			ranges = put(ranges, fd, fdSize)
+			count++
+		}
+	}
+	b := []byte{format}
+	b = put(b, count, glyphSize)
+	b = append(b, ranges...)
+	return put(b, len(fds), glyphSize)
+}
+
+// parseVariationStore reads the number of regions of each item variation data, needed to interpret blend operators.
+func (c *cffFont) parseVariationStore(data []byte, pos int) error {
+	if pos < 0 || pos+2 > len(data) {
+		return ErrInvalidCFF
+	}
+	end := pos + 2 + int(binary.BigEndian.Uint16(data[pos:]))
+	if end > len(data) {
+		return ErrInvalidCFF
+	}
+	c.varStore = data[pos:end]
+	store := c.varStore[2:]
+	if len(store) < 8 {
+		return ErrInvalidCFF
+	}
+	count := int(binary.BigEndian.Uint16(store[6:]))
+	if len(store) < 8+count*4 {
+		return ErrInvalidCFF
+	}
+	for i := 0; i < count; i++ {
+		offset := int(binary.BigEndian.Uint32(store[8+i*4:]))
+		if offset+6 > len(store) {
+			return ErrInvalidCFF
+		}
+		c.regionCounts = append(c.regionCounts, int(binary.BigEndian.Uint16(store[offset+4:])))
+	}
+	return nil
+}
+
+// fontOf returns the font of glyph.
+func (c *cffFont) fontOf(glyph int) int {
+	if glyph < len(c.fdSelect) {
+		return c.fdSelect[glyph]
+	}
+	return 0
+}
+
+// cffSubrBias returns the bias added to the operand of callsubr and callgsubr.
+func cffSubrBias(count int) int {
+	if count < 1240 {
+		return 107
+	} else if count < 33900 {
+		return 1131
+	}
+	return 32768
+}
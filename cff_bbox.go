@@ -0,0 +1,309 @@
+package gopdf
+
+import (
+	"math"
+)
+
+// charStringBounds draws a Type 2 (or CFF2) charstring to find the bounding box of its outline.
+// The blended values of CFF2 charstrings are the ones of the default instance.
+type charStringBounds struct {
+	font    *cffFont
+	fd      int
+	stack   []float64
+	stems   int
+	vsindex int
+	depth   int
+
+	x, y                   float64
+	xMin, yMin, xMax, yMax float64
+	hasOutline             bool
+}
+
+// glyphBBox returns the bounding box of the outline of glyph, in font units.
+// A glyph without outline, like a space, has an empty bounding box.
+func (c *cffFont) glyphBBox(glyph int) (xMin, yMin, xMax, yMax float64, err error) {
+	if glyph < 0 || glyph >= len(c.charStrings) {
+		return 0, 0, 0, 0, ErrGlyphNotFound
+	}
+	fd := c.fontOf(glyph)
+	b := charStringBounds{font: c, fd: fd}
+	if vsindex := c.fonts[fd].private.get(22); len(vsindex) == 1 {
+		b.vsindex = int(vsindex[0])
+	}
+	if _, err := b.run(c.charStrings[glyph]); err != nil {
+		return 0, 0, 0, 0, err
+	}
+	if !b.hasOutline {
+		return 0, 0, 0, 0, nil
+	}
+	return b.xMin, b.yMin, b.xMax, b.yMax, nil
+}
+
+// run draws code and returns true when the end of the charstring is reached.
+func (b *charStringBounds) run(code []byte) (bool, error) {
+	font := b.font
+	for i := 0; i < len(code); {
+		op := code[i]
+		if v, size, err := readCharStringNumber(code, i); err != nil {
+			return false, err
+		} else if size > 0 {
+			b.stack = append(b.stack, v)
+			i += size
+			continue
+		}
+
+		i++
+		args := b.stack
+		switch op {
+		case 1, 3, 18, 23: // hstem, vstem, hstemhm, vstemhm
+			b.stems += len(args) / 2
+		case 19, 20: // hintmask, cntrmask, preceded by the operands of an implicit vstemhm
+			b.stems += len(args) / 2
+			i += (b.stems + 7) / 8
+		case 21: // rmoveto, after the width if there is one
+			if len(args) < 2 {
+				return false, ErrInvalidCFF
+			}
+			b.moveTo(args[len(args)-2], args[len(args)-1])
+		case 22: // hmoveto
+			if len(args) < 1 {
+				return false, ErrInvalidCFF
+			}
+			b.moveTo(args[len(args)-1], 0)
+		case 4: // vmoveto
+			if len(args) < 1 {
+				return false, ErrInvalidCFF
+			}
+			b.moveTo(0, args[len(args)-1])
+		case 5: // rlineto
+			for ; len(args) >= 2; args = args[2:] {
+				b.lineTo(args[0], args[1])
+			}
+		case 6, 7: // hlineto, vlineto, alternating horizontal and vertical lines
+			horizontal := op == 6
+			for ; len(args) >= 1; args = args[1:] {
+				if horizontal {
+					b.lineTo(args[0], 0)
+				} else {
+					b.lineTo(0, args[0])
+				}
+				horizontal = !horizontal
+			}
+		case 8: // rrcurveto
+			for ; len(args) >= 6; args = args[6:] {
+				b.curveTo(args[0], args[1], args[2], args[3], args[4], args[5])
+			}
+		case 24: // rcurveline
+			for ; len(args) >= 8; args = args[6:] {
+				b.curveTo(args[0], args[1], args[2], args[3], args[4], args[5])
+			}
+			if len(args) >= 2 {
+				b.lineTo(args[0], args[1])
+			}
+		case 25: // rlinecurve
+			for ; len(args) >= 8; args = args[2:] {
+				b.lineTo(args[0], args[1])
+			}
+			if len(args) >= 6 {
+				b.curveTo(args[0], args[1], args[2], args[3], args[4], args[5])
+			}
+		case 26: // vvcurveto
+			dx1 := 0.0
+			if len(args)%4 == 1 {
+				dx1, args = args[0], args[1:]
+			}
+			for ; len(args) >= 4; args = args[4:] {
+				b.curveTo(dx1, args[0], args[1], args[2], 0, args[3])
+				dx1 = 0
+			}
+		case 27: // hhcurveto
+			dy1 := 0.0
+			if len(args)%4 == 1 {
+				dy1, args = args[0], args[1:]
+			}
+			for ; len(args) >= 4; args = args[4:] {
+				b.curveTo(args[0], dy1, args[1], args[2], args[3], 0)
+				dy1 = 0
+			}
+		case 30, 31: // vhcurveto, hvcurveto, alternating curves starting vertically and horizontally
+			horizontal := op == 31
+			for ; len(args) >= 4; args = args[4:] {
+				last := 0.0
+				if len(args) == 5 {
+					last = args[4]
+				}
+				if horizontal {
+					b.curveTo(args[0], 0, args[1], args[2], last, args[3])
+				} else {
+					b.curveTo(0, args[0], args[1], args[2], args[3], last)
+				}
+				horizontal = !horizontal
+			}
+		case 10, 29: // callsubr, callgsubr
+			if len(args) == 0 || b.depth >= cffMaxSubrDepth {
+				return false, errCFFUnsupportedCharString
+			}
+			subrs := font.globalSubrs
+			if op == 10 {
+				subrs = font.fonts[b.fd].subrs
+			}
+			index := int(args[len(args)-1]) + cffSubrBias(len(subrs))
+			b.stack = args[:len(args)-1]
+			if index < 0 || index >= len(subrs) {
+				return false, errCFFUnsupportedCharString
+			}
+			b.depth++
+			end, err := b.run(subrs[index])
+			b.depth--
+			if err != nil || end {
+				return end, err
+			}
+			continue
+		case 11: // return
+			return false, nil
+		case 14: // endchar
+			if !font.cff2 && len(args) >= 4 {
+				// seac: the accented glyph is made of two glyphs of the standard encoding
+				return false, errCFFUnsupportedCharString
+			}
+			return true, nil
+		case 15: // vsindex
+			if len(args) > 0 {
+				b.vsindex = int(args[len(args)-1])
+			}
+		case 16: // blend, n default values followed by their n*k deltas and n
+			if len(args) == 0 || b.vsindex >= len(font.regionCounts) {
+				return false, errCFFUnsupportedCharString
+			}
+			n := int(args[len(args)-1])
+			drop := n*font.regionCounts[b.vsindex] + 1
+			if n < 0 || drop > len(args) {
+				return false, ErrInvalidCFF
+			}
+			b.stack = args[:len(args)-drop]
+			continue
+		case 12:
+			if i >= len(code) {
+				return false, ErrInvalidCFF
+			}
+			if err := b.flex(code[i], args); err != nil {
+				return false, err
+			}
+			i++
+		}
+		b.stack = b.stack[:0]
+	}
+	return false, nil
+}
+
+// flex draws the two curves of the flex operator 12 op.
+func (b *charStringBounds) flex(op byte, args []float64) error {
+	switch op {
+	case 35: // flex
+		if len(args) < 12 {
+			return ErrInvalidCFF
+		}
+		b.curveTo(args[0], args[1], args[2], args[3], args[4], args[5])
+		b.curveTo(args[6], args[7], args[8], args[9], args[10], args[11])
+	case 34: // hflex
+		if len(args) < 7 {
+			return ErrInvalidCFF
+		}
+		b.curveTo(args[0], 0, args[1], args[2], args[3], 0)
+		b.curveTo(args[4], 0, args[5], -args[2], args[6], 0)
+	case 36: // hflex1
+		if len(args) < 9 {
+			return ErrInvalidCFF
+		}
+		b.curveTo(args[0], args[1], args[2], args[3], args[4], 0)
+		b.curveTo(args[5], 0, args[6], args[7], args[8], -(args[1] + args[3] + args[7]))
+	case 37: // flex1, the last point is on the line of the first one
+		if len(args) < 11 {
+			return ErrInvalidCFF
+		}
+		dx, dy := 0.0, 0.0
+		for i := 0; i < 10; i += 2 {
+			dx += args[i]
+			dy += args[i+1]
+		}
+		dx6, dy6 := args[10], -dy
+		if math.Abs(dx) <= math.Abs(dy) {
+			dx6, dy6 = -dx, args[10]
+		}
+		b.curveTo(args[0], args[1], args[2], args[3], args[4], args[5])
+		b.curveTo(args[6], args[7], args[8], args[9], dx6, dy6)
+	default:
+		// the arithmetic operators of Type 2 charstrings are not used by fonts in practice
+		return errCFFUnsupportedCharString
+	}
+	return nil
+}
+
+func (b *charStringBounds) moveTo(dx, dy float64) {
+	b.x += dx
+	b.y += dy
+}
+
+func (b *charStringBounds) lineTo(dx, dy float64) {
+	b.addPoint(b.x, b.y)
+	b.x += dx
+	b.y += dy
+	b.addPoint(b.x, b.y)
+}
+
+// curveTo adds the points of a cubic Bézier curve where it is the farthest in x or y.
+func (b *charStringBounds) curveTo(dx1, dy1, dx2, dy2, dx3, dy3 float64) {
+	x0, y0 := b.x, b.y
+	x1, y1 := x0+dx1, y0+dy1
+	x2, y2 := x1+dx2, y1+dy2
+	x3, y3 := x2+dx3, y2+dy3
+	b.addPoint(x0, y0)
+	b.addPoint(x3, y3)
+	for _, t := range cubicExtrema(x0, x1, x2, x3) {
+		b.addPoint(cubicAt(x0, x1, x2, x3, t), cubicAt(y0, y1, y2, y3, t))
+	}
+	for _, t := range cubicExtrema(y0, y1, y2, y3) {
+		b.addPoint(cubicAt(x0, x1, x2, x3, t), cubicAt(y0, y1, y2, y3, t))
+	}
+	b.x, b.y = x3, y3
+}
+
+func (b *charStringBounds) addPoint(x, y float64) {
+	if !b.hasOutline {
+		b.xMin, b.yMin, b.xMax, b.yMax = x, y, x, y
+		b.hasOutline = true
+		return
+	}
+	b.xMin, b.yMin = math.Min(b.xMin, x), math.Min(b.yMin, y)
+	b.xMax, b.yMax = math.Max(b.xMax, x), math.Max(b.yMax, y)
+}
+
+// cubicAt returns the value at t of the cubic Bézier curve of p0, p1, p2 and p3.
+func cubicAt(p0, p1, p2, p3, t float64) float64 {
+	u := 1 - t
+	return u*u*u*p0 + 3*u*u*t*p1 + 3*u*t*t*p2 + t*t*t*p3
+}
+
+// cubicExtrema returns the values of t in ]0, 1[ where the cubic Bézier curve of p0, p1, p2 and p3 turns.
+func cubicExtrema(p0, p1, p2, p3 float64) []float64 {
+	// the derivative is a*t² + b*t + c
+	a := 3 * (-p0 + 3*p1 - 3*p2 + p3)
+	b := 6 * (p0 - 2*p1 + p2)
+	c := 3 * (p1 - p0)
+	var roots []float64
+	if math.Abs(a) < 1e-12 {
+		if math.Abs(b) > 1e-12 {
+			roots = append(roots, -c/b)
+		}
+	} else if d := b*b - 4*a*c; d >= 0 {
+		sqrt := math.Sqrt(d)
+		roots = append(roots, (-b+sqrt)/(2*a), (-b-sqrt)/(2*a))
+	}
+	var ts []float64
+	for _, t := range roots {
+		if t > 0 && t < 1 {
+			ts = append(ts, t)
+		}
+	}
+	return ts
+}
@@ -0,0 +1,369 @@
+package gopdf
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// errCFFUnsupportedCharString is returned by the charstring scanner when it cannot tell which subroutines
+// and glyphs a charstring uses, in which case they are all kept.
+var errCFFUnsupportedCharString = errors.New("unsupported CFF charstring")
+
+const cffMaxSubrDepth = 10
+
+// cffSubset records the glyphs and subroutines used by the charstrings of a subset.
+type cffSubset struct {
+	font        *cffFont
+	glyphs      []bool
+	globalSubrs []bool
+	localSubrs  [][]bool
+}
+
+// charStringScanner walks through a Type 2 (or CFF2) charstring to find the subroutines it calls.
+// Only the operands are evaluated, the outline is not drawn.
+type charStringScanner struct {
+	subset  *cffSubset
+	fd      int
+	stack   []float64
+	stems   int
+	vsindex int
+	depth   int
+}
+
+// scan walks through code and returns true when the end of the charstring is reached.
+func (s *charStringScanner) scan(code []byte) (bool, error) {
+	font := s.subset.font
+	for i := 0; i < len(code); {
+		b := code[i]
+		if v, size, err := readCharStringNumber(code, i); err != nil {
+			return false, err
+		} else if size > 0 {
+			s.stack = append(s.stack, v)
+			i += size
+			continue
+		}
+
+		i++
+		switch b {
+		case 1, 3, 18, 23: // hstem, vstem, hstemhm, vstemhm
+			s.stems += len(s.stack) / 2
+		case 19, 20: // hintmask, cntrmask, preceded by the operands of an implicit vstemhm
+			s.stems += len(s.stack) / 2
+			i += (s.stems + 7) / 8
+		case 10, 29: // callsubr, callgsubr
+			if len(s.stack) == 0 || s.depth >= cffMaxSubrDepth {
+				return false, errCFFUnsupportedCharString
+			}
+			subrs, used := font.globalSubrs, s.subset.globalSubrs
+			if b == 10 {
+				subrs, used = font.fonts[s.fd].subrs, s.subset.localSubrs[s.fd]
+			}
+			index := int(s.stack[len(s.stack)-1]) + cffSubrBias(len(subrs))
+			s.stack = s.stack[:len(s.stack)-1]
+			if index < 0 || index >= len(subrs) {
+				return false, errCFFUnsupportedCharString
+			}
+			used[index] = true
+			s.depth++
+			end, err := s.scan(subrs[index])
+			s.depth--
+			if err != nil || end {
+				return end, err
+			}
+			continue
+		case 11: // return
+			return false, nil
+		case 14: // endchar
+			if !font.cff2 && len(s.stack) >= 4 {
+				// seac: the accented glyph is made of two glyphs of the standard encoding
+				return false, errCFFUnsupportedCharString
+			}
+			return true, nil
+		case 15: // vsindex
+			if len(s.stack) > 0 {
+				s.vsindex = int(s.stack[len(s.stack)-1])
+			}
+		case 16: // blend, n default values followed by their n*k deltas and n
+			if len(s.stack) == 0 || s.vsindex >= len(font.regionCounts) {
+				return false, errCFFUnsupportedCharString
+			}
+			n := int(s.stack[len(s.stack)-1])
+			drop := n*font.regionCounts[s.vsindex] + 1
+			if n < 0 || drop > len(s.stack) {
+				return false, ErrInvalidCFF
+			}
+			s.stack = s.stack[:len(s.stack)-drop]
+			continue
+		case 12:
+			if i >= len(code) {
+				return false, ErrInvalidCFF
+			}
+			i++
+		}
+		s.stack = s.stack[:0]
+	}
+	return false, nil
+}
+
+// readCharStringNumber reads the number at i of a charstring and returns its value and size,
+// 0 if there is an operator at i.
+func readCharStringNumber(code []byte, i int) (float64, int, error) {
+	b := code[i]
+	size := 0
+	switch {
+	case b >= 32 && b <= 246:
+		return float64(int(b) - 139), 1, nil
+	case b >= 247 && b <= 254:
+		size = 2
+	case b == 28:
+		size = 3
+	case b == 255:
+		size = 5
+	default:
+		return 0, 0, nil
+	}
+	if i+size > len(code) {
+		return 0, 0, ErrInvalidCFF
+	}
+	switch {
+	case b >= 247 && b <= 250:
+		return float64((int(b)-247)*256 + int(code[i+1]) + 108), size, nil
+	case b >= 251 && b <= 254:
+		return float64(-(int(b)-251)*256 - int(code[i+1]) - 108), size, nil
+	case b == 28:
+		return float64(int16(binary.BigEndian.Uint16(code[i+1:]))), size, nil
+	}
+	return float64(int32(binary.BigEndian.Uint32(code[i+1:]))) / 65536, size, nil
+}
+
+// charStringNumberBytes encodes v in a charstring.
+func charStringNumberBytes(v float64) []byte {
+	switch {
+	case v != math.Trunc(v) || v < -32768 || v > 32767:
+		f := int32(math.Round(v * 65536))
+		return []byte{255, byte(f >> 24), byte(f >> 16), byte(f >> 8), byte(f)}
+	case v >= -107 && v <= 107:
+		return []byte{byte(int(v) + 139)}
+	}
+	return []byte{28, byte(int(v) >> 8), byte(int(v))}
+}
+
+// newCFFSubset finds the subroutines used by glyphs, glyph 0 being always kept.
+// When a charstring cannot be followed, every glyph and subroutine is kept.
+func (c *cffFont) newCFFSubset(glyphs []int) (*cffSubset, error) {
+	s := &cffSubset{
+		font:        c,
+		glyphs:      make([]bool, len(c.charStrings)),
+		globalSubrs: make([]bool, len(c.globalSubrs)),
+		localSubrs:  make([][]bool, len(c.fonts)),
+	}
+	for i, font := range c.fonts {
+		s.localSubrs[i] = make([]bool, len(font.subrs))
+	}
+	if len(s.glyphs) > 0 {
+		s.glyphs[0] = true
+	}
+	for _, g := range glyphs {
+		if g >= 0 && g < len(s.glyphs) {
+			s.glyphs[g] = true
+		}
+	}
+
+	for g, used := range s.glyphs {
+		if !used {
+			continue
+		}
+		fd := c.fontOf(g)
+		scanner := charStringScanner{subset: s, fd: fd}
+		if vsindex := c.fonts[fd].private.get(22); len(vsindex) == 1 {
+			scanner.vsindex = int(vsindex[0])
+		}
+		if _, err := scanner.scan(c.charStrings[g]); err == errCFFUnsupportedCharString {
+			s.keepAll()
+			break
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func (s *cffSubset) keepAll() {
+	for _, used := range append([][]bool{s.glyphs, s.globalSubrs}, s.localSubrs...) {
+		for i := range used {
+			used[i] = true
+		}
+	}
+}
+
+// cffFilter returns items with the unused ones replaced by empty, so that the indexes of the items do not change.
+func cffFilter(items [][]byte, used []bool, empty []byte) [][]byte {
+	filtered := make([][]byte, len(items))
+	for i, item := range items {
+		if used[i] {
+			filtered[i] = item
+		} else {
+			filtered[i] = empty
+		}
+	}
+	return filtered
+}
+
+// subset returns the font program with the outlines of glyphs only.
+// The other glyphs are left empty to keep the glyph indexes, that are used as CIDs.
+// A CFF font is written CID-keyed with an identity charset, ready to be embedded as CIDFontType0C.
+func (c *cffFont) subset(glyphs []int) ([]byte, error) {
+	s, err := c.newCFFSubset(glyphs)
+	if err != nil {
+		return nil, err
+	}
+	// an empty CFF charstring ends with endchar and an empty subroutine returns, CFF2 needs neither
+	emptyGlyph, emptySubr := []byte{14}, []byte{11}
+	if c.cff2 {
+		emptyGlyph, emptySubr = []byte{}, []byte{}
+	}
+	charStrings := cffFilter(c.charStrings, s.glyphs, emptyGlyph)
+	globalSubrs := cffFilter(c.globalSubrs, s.globalSubrs, emptySubr)
+	fonts := make([]cffPrivateFont, len(c.fonts))
+	for i, font := range c.fonts {
+		fonts[i] = font
+		fonts[i].subrs = cffFilter(font.subrs, s.localSubrs[i], emptySubr)
+	}
+	if c.cff2 {
+		return c.writeCFF2(charStrings, globalSubrs, fonts), nil
+	}
+	return c.writeCFF(charStrings, globalSubrs, fonts), nil
+}
+
+// cffPrivateBytes encodes the Private DICTs each followed by its local subroutines, starting at offset.
+// It returns their encoding and the position and size of each Private DICT.
+func cffPrivateBytes(fonts []cffPrivateFont, offset int, cff2 bool) ([]byte, [][2]int) {
+	var b []byte
+	var positions [][2]int
+	for _, font := range fonts {
+		private := font.private.without(cffOpSubrs)
+		size := len(private.bytes())
+		if len(font.subrs) > 0 {
+			size += len(cffIntEntry(cffOpSubrs, 0).raw) + 1
+			private = append(private, cffIntEntry(cffOpSubrs, size))
+		}
+		positions = append(positions, [2]int{size, offset + len(b)})
+		b = append(b, private.bytes()...)
+		if len(font.subrs) > 0 {
+			b = append(b, cffIndexBytes(font.subrs, cff2)...)
+		}
+	}
+	return b, positions
+}
+
+// cffFDArrayBytes encodes the Font DICTs pointing to the Private DICTs.
+func cffFDArrayBytes(fonts []cffPrivateFont, positions [][2]int, cff2 bool) []byte {
+	dicts := make([][]byte, len(fonts))
+	for i, font := range fonts {
+		dict := font.dict.without(cffOpPrivate)
+		if positions != nil {
+			dict = append(dict, cffIntEntry(cffOpPrivate, positions[i][0], positions[i][1]))
+		} else {
+			dict = append(dict, cffIntEntry(cffOpPrivate, 0, 0))
+		}
+		dicts[i] = dict.bytes()
+	}
+	return cffIndexBytes(dicts, cff2)
+}
+
+// writeCFF writes a CID-keyed CFF font.
+func (c *cffFont) writeCFF(charStrings, globalSubrs [][]byte, fonts []cffPrivateFont) []byte {
+	numGlyphs := len(charStrings)
+	strings := append(append([][]byte(nil), c.strings...), []byte("Adobe"), []byte("Identity"))
+	registry := 391 + len(c.strings) // the first custom string follows the 391 standard strings
+
+	fds := c.fdSelect
+	if fds == nil {
+		fds = make([]int, numGlyphs)
+	}
+	if c.top.get(cffOpFDArray) == nil {
+		// a name-keyed font becomes a CID-keyed font with a single Font DICT
+		fonts[0].dict = nil
+	}
+	// identity charset, the CID of each glyph is its index
+	charset := []byte{0}
+	if numGlyphs > 1 {
+		charset = []byte{2, 0, 1, byte((numGlyphs - 2) >> 8), byte(numGlyphs - 2)}
+	}
+	fdSelect := cffFDSelectBytes(fds, false)
+	charStringsIndex := cffIndexBytes(charStrings, false)
+
+	top := func(charsetOffset, fdSelectOffset, charStringsOffset, fdArrayOffset int) []byte {
+		dict := cffDict{cffIntEntry(cffOpROS, registry, registry+1, 0)}
+		dict = append(dict, c.top.without(cffOpROS, cffOpCIDCount, cffOpCharset, cffOpEncoding,
+			cffOpCharStrings, cffOpPrivate, cffOpFDArray, cffOpFDSelect)...)
+		dict = append(dict,
+			cffIntEntry(cffOpCIDCount, numGlyphs),
+			cffIntEntry(cffOpCharset, charsetOffset),
+			cffIntEntry(cffOpFDSelect, fdSelectOffset),
+			cffIntEntry(cffOpCharStrings, charStringsOffset),
+			cffIntEntry(cffOpFDArray, fdArrayOffset),
+		)
+		return cffIndexBytes([][]byte{dict.bytes()}, false)
+	}
+
+	header := []byte{1, 0, 4, 4}
+	head := append(header, cffIndexBytes([][]byte{c.name}, false)...)
+	tail := append(cffIndexBytes(strings, false), cffIndexBytes(globalSubrs, false)...)
+	charsetOffset := len(head) + len(top(0, 0, 0, 0)) + len(tail)
+	fdSelectOffset := charsetOffset + len(charset)
+	charStringsOffset := fdSelectOffset + len(fdSelect)
+	fdArrayOffset := charStringsOffset + len(charStringsIndex)
+	privateOffset := fdArrayOffset + len(cffFDArrayBytes(fonts, nil, false))
+	private, positions := cffPrivateBytes(fonts, privateOffset, false)
+
+	b := append(head, top(charsetOffset, fdSelectOffset, charStringsOffset, fdArrayOffset)...)
+	b = append(b, tail...)
+	b = append(b, charset...)
+	b = append(b, fdSelect...)
+	b = append(b, charStringsIndex...)
+	b = append(b, cffFDArrayBytes(fonts, positions, false)...)
+	return append(b, private...)
+}
+
+// writeCFF2 writes a CFF2 font.
+func (c *cffFont) writeCFF2(charStrings, globalSubrs [][]byte, fonts []cffPrivateFont) []byte {
+	var fdSelect []byte
+	if c.fdSelect != nil {
+		fdSelect = cffFDSelectBytes(c.fdSelect, true)
+	}
+	charStringsIndex := cffIndexBytes(charStrings, true)
+
+	top := func(charStringsOffset, varStoreOffset, fdSelectOffset, fdArrayOffset int) []byte {
+		dict := c.top.without(cffOpCharStrings, cffOpVariationStore, cffOpFDArray, cffOpFDSelect)
+		dict = append(dict, cffIntEntry(cffOpCharStrings, charStringsOffset))
+		if c.varStore != nil {
+			dict = append(dict, cffIntEntry(cffOpVariationStore, varStoreOffset))
+		}
+		if fdSelect != nil {
+			dict = append(dict, cffIntEntry(cffOpFDSelect, fdSelectOffset))
+		}
+		dict = append(dict, cffIntEntry(cffOpFDArray, fdArrayOffset))
+		return dict.bytes()
+	}
+
+	const headerSize = 5
+	globalSubrsIndex := cffIndexBytes(globalSubrs, true)
+	topSize := len(top(0, 0, 0, 0))
+	charStringsOffset := headerSize + topSize + len(globalSubrsIndex)
+	varStoreOffset := charStringsOffset + len(charStringsIndex)
+	fdSelectOffset := varStoreOffset + len(c.varStore)
+	fdArrayOffset := fdSelectOffset + len(fdSelect)
+	privateOffset := fdArrayOffset + len(cffFDArrayBytes(fonts, nil, true))
+	private, positions := cffPrivateBytes(fonts, privateOffset, true)
+
+	b := []byte{2, 0, headerSize, byte(topSize >> 8), byte(topSize)}
+	b = append(b, top(charStringsOffset, varStoreOffset, fdSelectOffset, fdArrayOffset)...)
+	b = append(b, globalSubrsIndex...)
+	b = append(b, charStringsIndex...)
+	b = append(b, c.varStore...)
+	b = append(b, fdSelect...)
+	b = append(b, cffFDArrayBytes(fonts, positions, true)...)
+	return append(b, private...)
+}
@@ -0,0 +1,340 @@
+package gopdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/signintech/gopdf/fontmaker/core"
+)
+
+func TestCFFSubset(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		cff2, cid bool
+	}{
+		{"name-keyed", false, false},
+		{"cid-keyed", false, true},
+		{"cff2", true, false},
+	} {
+		data := buildTestCFF(40, test.cff2, test.cid)
+		font, err := parseCFF(data)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if len(font.charStrings) != 40 || len(font.globalSubrs) != 2 {
+			t.Fatalf("%s: unexpected font %d glyphs %d global subroutines", test.name, len(font.charStrings), len(font.globalSubrs))
+		}
+		if (test.cid || test.cff2) && (len(font.fonts) != 2 || font.fontOf(12) != 1) {
+			t.Fatalf("%s: expected 2 fonts selected by glyph", test.name)
+		}
+
+		// glyph g draws the square from 0,0 to 100 + g%4,100
+		for _, g := range []int{5, 12} {
+			xMin, yMin, xMax, yMax, err := font.glyphBBox(g)
+			if err != nil || xMin != 0 || yMin != 0 || xMax != float64(100+g%4) || yMax != 100 {
+				t.Fatalf("%s: unexpected bounding box of glyph %d: %f %f %f %f %v", test.name, g, xMin, yMin, xMax, yMax, err)
+			}
+		}
+
+		b, err := font.subset([]int{5, 12})
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		subset, err := parseCFF(b)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if len(subset.charStrings) != 40 {
+			t.Fatalf("%s: expected the glyph indexes to be kept, got %d glyphs", test.name, len(subset.charStrings))
+		}
+		for g, charString := range subset.charStrings {
+			used := g == 0 || g == 5 || g == 12
+			if used != bytes.Equal(charString, font.charStrings[g]) {
+				t.Fatalf("%s: unexpected charstring of glyph %d: %v", test.name, g, charString)
+			}
+		}
+		if !bytes.Equal(subset.globalSubrs[0], font.globalSubrs[0]) || bytes.Equal(subset.globalSubrs[1], font.globalSubrs[1]) {
+			t.Fatalf("%s: expected only the first global subroutine to be kept", test.name)
+		}
+		// glyph g calls the local subroutine g % 4 of its font
+		for fd, f := range subset.fonts {
+			for i, subr := range f.subrs {
+				used := false
+				for _, g := range []int{0, 5, 12} {
+					used = used || (font.fontOf(g) == fd && g%4 == i)
+				}
+				if used != bytes.Equal(subr, font.fonts[fd].subrs[i]) {
+					t.Fatalf("%s: unexpected local subroutine %d of font %d: %v", test.name, i, fd, subr)
+				}
+			}
+		}
+		if !test.cff2 {
+			ros := subset.top.get(cffOpROS)
+			if len(ros) != 3 || string(subset.strings[int(ros[0])-391]) != "Adobe" || string(subset.strings[int(ros[1])-391]) != "Identity" {
+				t.Fatalf("%s: expected an Adobe-Identity CID-keyed font, got %v", test.name, ros)
+			}
+		}
+	}
+}
+
+func TestCFFFont(t *testing.T) {
+	err := initTesting()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name    string
+		cff2    bool
+		subtype string
+	}{
+		{"cff", false, "/CIDFontType0C"},
+		{"cff2", true, "/OpenType"},
+	} {
+		pdf := GoPdf{}
+		pdf.Start(Config{PageSize: *PageSizeA4})
+		pdf.SetCompressLevel(0)
+		pdf.AddPage()
+		err = pdf.AddTTFFontData(test.name, buildTestOTF(t, test.cff2))
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		err = pdf.SetFont(test.name, "", 14)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		err = pdf.Cell(nil, "Hello")
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if w, err := pdf.MeasureTextWidth("Hello"); err != nil || w <= 0 {
+			t.Fatalf("%s: unexpected width %f %v", test.name, w, err)
+		}
+		glyphs, err := pdf.MeasureGlyphs("H")
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if bbox := glyphs[0].BBox; bbox.Right <= bbox.Left || bbox.Bottom <= bbox.Top {
+			t.Fatalf("%s: expected the bounding box of the outline, got %+v", test.name, bbox)
+		}
+
+		for _, obj := range pdf.pdfObjs {
+			dict, ok := obj.(*PdfDictionaryObj)
+			if !ok {
+				continue
+			}
+			b, err := dict.makeFont()
+			if err != nil {
+				t.Fatalf("%s: %v", test.name, err)
+			}
+			if test.cff2 {
+				var ttfp core.TTFParser
+				if err := ttfp.ParseFontData(b); err != nil {
+					t.Fatalf("%s: %v", test.name, err)
+				}
+				b = ttfp.TableData("CFF2")
+			}
+			if _, err := parseCFF(b); err != nil {
+				t.Fatalf("%s: %v", test.name, err)
+			}
+		}
+
+		var buff bytes.Buffer
+		_, err = pdf.WriteTo(&buff)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		for _, want := range []string{"/FontFile3", test.subtype, "/Subtype /CIDFontType0\n"} {
+			if !bytes.Contains(buff.Bytes(), []byte(want)) {
+				t.Fatalf("%s: expected %s in the pdf", test.name, want)
+			}
+		}
+		err = ioutil.WriteFile("./test/out/"+test.name+"_font.pdf", buff.Bytes(), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCFFGlyphBBox(t *testing.T) {
+	code := func(ops ...interface{}) []byte {
+		var b []byte
+		for _, op := range ops {
+			switch op := op.(type) {
+			case int:
+				b = append(b, charStringNumberBytes(float64(op))...)
+			case []byte:
+				b = append(b, op...)
+			}
+		}
+		return b
+	}
+	font := &cffFont{
+		fonts: []cffPrivateFont{{}},
+		charStrings: [][]byte{
+			// a width, then an arch from 0,0 to 100,0 reaching y 75 at its middle
+			code(500, 10, 20, []byte{21}, 0, 100, 100, 0, 0, -100, []byte{8, 14}),
+			// hvcurveto with the final dy, then a horizontal flex
+			code(0, 0, []byte{21}, 50, 50, 50, 0, []byte{31}, 10, 10, 5, 10, 10, 10, 10, []byte{12, 34}, []byte{14}),
+			// only a width
+			code(250, []byte{14}),
+			// seac
+			code(0, 0, 0, 65, 194, []byte{14}),
+		},
+	}
+	for g, want := range [][4]float64{{10, 20, 110, 95}, {0, 0, 160, 55}, {0, 0, 0, 0}} {
+		xMin, yMin, xMax, yMax, err := font.glyphBBox(g)
+		if err != nil {
+			t.Fatalf("glyph %d: %v", g, err)
+		}
+		for i, v := range []float64{xMin, yMin, xMax, yMax} {
+			if math.Abs(v-want[i]) > 1e-9 {
+				t.Fatalf("glyph %d: expected %v, got %v", g, want, []float64{xMin, yMin, xMax, yMax})
+			}
+		}
+	}
+	if _, _, _, _, err := font.glyphBBox(3); err != errCFFUnsupportedCharString {
+		t.Fatalf("expected errCFFUnsupportedCharString for seac, got %v", err)
+	}
+	if _, _, _, _, err := font.glyphBBox(4); err != ErrGlyphNotFound {
+		t.Fatalf("expected ErrGlyphNotFound, got %v", err)
+	}
+}
+
+// buildTestOTF replaces the outlines of LiberationSerif-Regular by the CFF or CFF2 outlines of buildTestCFF.
+func buildTestOTF(t *testing.T, cff2 bool) []byte {
+	var ttfp core.TTFParser
+	if err := ttfp.Parse("./test/res/LiberationSerif-Regular.ttf"); err != nil {
+		t.Fatal(err)
+	}
+	tables := map[string][]byte{}
+	for tag := range ttfp.GetTables() {
+		switch tag {
+		case "glyf", "loca", "cvt ", "fpgm", "prep", "gasp", "hdmx", "VDMX", "LTSH":
+		default:
+			tables[tag] = ttfp.TableData(tag)
+		}
+	}
+	maxp := make([]byte, 6)
+	binary.BigEndian.PutUint32(maxp, 0x00005000)
+	binary.BigEndian.PutUint16(maxp[4:], uint16(ttfp.NumGlyphs()))
+	tables["maxp"] = maxp
+	if cff2 {
+		tables["CFF2"] = buildTestCFF(int(ttfp.NumGlyphs()), true, false)
+	} else {
+		tables["CFF "] = buildTestCFF(int(ttfp.NumGlyphs()), false, true)
+	}
+
+	var tags []string
+	for tag := range tables {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	var directory, data bytes.Buffer
+	directory.WriteString("OTTO")
+	binary.Write(&directory, binary.BigEndian, []uint16{uint16(len(tags)), 0, 0, 0})
+	for _, tag := range tags {
+		directory.WriteString(tag)
+		binary.Write(&directory, binary.BigEndian, []uint32{0, uint32(12 + 16*len(tags) + data.Len()), uint32(len(tables[tag]))})
+		data.Write(tables[tag])
+		data.Write(make([]byte, (4-len(tables[tag])%4)%4))
+	}
+	return append(directory.Bytes(), data.Bytes()...)
+}
+
+// buildTestCFF builds a font whose glyph g draws a square with a hint mask, the global subroutine 0 and
+// the local subroutine g % 4 of its font. Glyphs from 10 use the second font of CID-keyed and CFF2 fonts.
+// In CFF2 the index of the local subroutine is blended.
+func buildTestCFF(numGlyphs int, cff2, cid bool) []byte {
+	num := func(v int) []byte { return []byte{28, byte(v >> 8), byte(v)} }
+	code := func(parts ...[]byte) []byte { return bytes.Join(parts, nil) }
+	ret := []byte{11}
+	if cff2 {
+		ret = nil
+	}
+
+	globalSubrs := [][]byte{
+		code(num(0), num(0), []byte{21}, ret),
+		code(num(0), num(50), []byte{21}, ret),
+	}
+	var subrs [][]byte
+	for i := 0; i < 4; i++ {
+		subrs = append(subrs, code(num(100+i), num(0), []byte{5}, ret))
+	}
+	charStrings := make([][]byte, numGlyphs)
+	for g := range charStrings {
+		callSubr := code(num(g%4-107), []byte{10})
+		end := []byte{14}
+		if cff2 {
+			callSubr = code(num(g%4-107), num(7), num(1), []byte{16, 10})
+			end = nil
+		}
+		charStrings[g] = code(num(0), num(10), num(20), num(10), []byte{18, 19, 0xc0},
+			num(-107), []byte{29}, callSubr, num(0), num(100), []byte{5}, end)
+	}
+
+	fonts := 1
+	if cid || cff2 {
+		fonts = 2
+	}
+	fds := make([]int, numGlyphs)
+	for g := 10; g < numGlyphs && fonts == 2; g++ {
+		fds[g] = 1
+	}
+	// a variation store with a single region
+	varStore := code([]byte{0, 30}, []byte{0, 1, 0, 0, 0, 12, 0, 1, 0, 0, 0, 22},
+		[]byte{0, 1, 0, 1, 0, 0, 0x40, 0, 0x40, 0}, []byte{0, 0, 0, 0, 0, 1, 0, 0})
+
+	top := func(charStrings, varStore, fdSelect, fdArray, private int) cffDict {
+		switch {
+		case cff2:
+			return cffDict{cffIntEntry(cffOpCharStrings, charStrings), cffIntEntry(cffOpVariationStore, varStore),
+				cffIntEntry(cffOpFDSelect, fdSelect), cffIntEntry(cffOpFDArray, fdArray)}
+		case cid:
+			return cffDict{cffIntEntry(cffOpROS, 391, 392, 0), cffIntEntry(cffOpCIDCount, numGlyphs),
+				cffIntEntry(cffOpCharStrings, charStrings), cffIntEntry(cffOpFDSelect, fdSelect), cffIntEntry(cffOpFDArray, fdArray)}
+		}
+		return cffDict{cffIntEntry(cffOpCharStrings, charStrings), cffIntEntry(cffOpPrivate, 6, private)}
+	}
+	// the Top DICT is followed by the String INDEX in CFF
+	head := code([]byte{1, 0, 4, 4}, cffIndexBytes([][]byte{[]byte("Test")}, false))
+	encodeTop := func(dict cffDict) []byte {
+		return code(cffIndexBytes([][]byte{dict.bytes()}, false),
+			cffIndexBytes([][]byte{[]byte("Adobe"), []byte("Identity")}, false))
+	}
+	if cff2 {
+		size := len(top(0, 0, 0, 0, 0).bytes())
+		head = []byte{2, 0, 5, byte(size >> 8), byte(size)}
+		encodeTop = cffDict.bytes
+	}
+	private := code(cffDict{cffIntEntry(cffOpSubrs, 6)}.bytes(), cffIndexBytes(subrs, cff2))
+
+	globalSubrsIndex := cffIndexBytes(globalSubrs, cff2)
+	charStringsIndex := cffIndexBytes(charStrings, cff2)
+	fdSelect := cffFDSelectBytes(fds, cff2)
+	charStringsOffset := len(head) + len(encodeTop(top(0, 0, 0, 0, 0))) + len(globalSubrsIndex)
+	varStoreOffset := charStringsOffset + len(charStringsIndex)
+	fdSelectOffset := varStoreOffset + len(varStore)
+	fdArrayOffset := fdSelectOffset + len(fdSelect)
+	privateOffset := fdArrayOffset + len(fdArrayBytes(fonts, 0, len(private), cff2))
+
+	b := code(head, encodeTop(top(charStringsOffset, varStoreOffset, fdSelectOffset, fdArrayOffset, privateOffset)),
+		globalSubrsIndex, charStringsIndex, varStore, fdSelect, fdArrayBytes(fonts, privateOffset, len(private), cff2))
+	for i := 0; i < fonts; i++ {
+		b = append(b, private...)
+	}
+	return b
+}
+
+// fdArrayBytes encodes fonts Font DICTs pointing to consecutive Private DICTs of 6 bytes followed by their subroutines.
+func fdArrayBytes(fonts int, private int, privateSize int, cff2 bool) []byte {
+	var dicts [][]byte
+	for i := 0; i < fonts; i++ {
+		dicts = append(dicts, cffDict{cffIntEntry(cffOpPrivate, 6, private+i*privateSize)}.bytes())
+	}
+	return cffIndexBytes(dicts, cff2)
+}
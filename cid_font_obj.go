@@ -34,13 +34,28 @@ func (ci *CIDFontObj) write(w io.Writer, objID int) error {
 	io.WriteString(w, "  /Supplement 0\n")
 	io.WriteString(w, ">>\n")
 	fmt.Fprintf(w, "/FontDescriptor %d 0 R\n", ci.indexObjSubfontDescriptor+1) //TODO fix
-	io.WriteString(w, "/Subtype /CIDFontType2\n")
+	if ci.PtrToSubsetFontObj.GetTTFParser().IsCFF() {
+		io.WriteString(w, "/Subtype /CIDFontType0\n")
+	} else {
+		io.WriteString(w, "/Subtype /CIDFontType2\n")
+	}
 	io.WriteString(w, "/Type /Font\n")
-	glyphIndexs := ci.PtrToSubsetFontObj.CharacterToGlyphIndex.AllVals()
 	io.WriteString(w, "/W [")
-	for _, v := range glyphIndexs {
-		width := ci.PtrToSubsetFontObj.GlyphIndexToPdfWidth(v)
-		fmt.Fprintf(w, "%d[%d]", v, width)
+	if ci.PtrToSubsetFontObj.ttfFontOption.EmbedFullFont {
+		// the widths of all the glyphs from glyph 0
+		io.WriteString(w, "0[")
+		for v := uint(0); v < ci.PtrToSubsetFontObj.ttfp.NumGlyphs(); v++ {
+			if v > 0 {
+				io.WriteString(w, " ")
+			}
+			fmt.Fprintf(w, "%d", ci.PtrToSubsetFontObj.GlyphIndexToPdfWidth(v))
+		}
+		io.WriteString(w, "]")
+	} else {
+		for _, v := range ci.PtrToSubsetFontObj.glyphs() {
+			width := ci.PtrToSubsetFontObj.GlyphIndexToPdfWidth(v)
+			fmt.Fprintf(w, "%d[%d]", v, width)
+		}
 	}
 	io.WriteString(w, "]\n")
 	io.WriteString(w, ">>\n")
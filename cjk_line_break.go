@@ -0,0 +1,75 @@
+package gopdf
+
+import (
+	"strings"
+	"unicode"
+)
+
+// kinsokuNotStart holds the characters that must not start a line (gyoto kinsoku).
+const kinsokuNotStart = ")]}»›’”〉》」』】〕〗〙〛〞〟｠）］｝〕〉》」』】〙〗" +
+	"、。，．,.:;?!‼⁇⁈⁉：；？！・･‐゠–〜ー々〻ゝゞヽヾ" +
+	"ぁぃぅぇぉっゃゅょゎゕゖァィゥェォッャュョヮヵヶㇰㇱㇲㇳㇴㇵㇶㇷㇸㇹㇺㇻㇼㇽㇾㇿ"
+
+// kinsokuNotEnd holds the characters that must not end a line (gyomatsu kinsoku).
+const kinsokuNotEnd = "([{«‹‘“〈《「『【〔〖〘〚〝｟（［｛"
+
+// kinsokuHanging holds the punctuation that may hang past the end of the line (burasage).
+const kinsokuHanging = "、。，．,."
+
+// cjkBreaks returns the breaks of the Unicode Line Breaking Algorithm without the breaks forbidden by kinsoku shori.
+func cjkBreaks(text []rune) []lineBreak {
+	breaks := unicodeLineBreaks(text)
+	allowed := breaks[:0]
+	for _, b := range breaks {
+		if !b.mandatory && b.index < len(text) {
+			if strings.ContainsRune(kinsokuNotStart, text[b.index]) || strings.ContainsRune(kinsokuNotEnd, text[b.index-1]) {
+				continue
+			}
+		}
+		allowed = append(allowed, b)
+	}
+	return allowed
+}
+
+// isCJK reports whether r is a Chinese, Japanese or Korean character.
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) ||
+		unicode.Is(unicode.Hangul, r) || r == 0x30FC
+}
+
+// isLatinOrDigit reports whether r is a letter or digit which is separated from CJK characters by CJKLatinSpacing.
+func isLatinOrDigit(r rune) bool {
+	return r < 0x2E80 && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+// addCJKLatinSpacing inserts a space between CJK characters and Latin letters or digits which touch each other.
+// The spaces are real U+0020 characters, they are not only a gap when the text is drawn.
+func addCJKLatinSpacing(text []rune) []rune {
+	spaced := make([]rune, 0, len(text))
+	for i, r := range text {
+		if i > 0 {
+			prev := text[i-1]
+			if (isCJK(prev) && isLatinOrDigit(r)) || (isLatinOrDigit(prev) && isCJK(r)) {
+				spaced = append(spaced, ' ')
+			}
+		}
+		spaced = append(spaced, r)
+	}
+	return spaced
+}
+
+// splitTextCJK splits text with kinsoku shori, hanging the line-end punctuation if requested.
+// With CJKLatinSpacing the returned lines hold the spaces added between CJK and Latin characters.
+func (gp *GoPdf) splitTextCJK(text string, width float64, opt *BreakOption) ([]string, error) {
+	utf8Texts := []rune(text)
+	if opt.CJKLatinSpacing {
+		utf8Texts = addCJKLatinSpacing(utf8Texts)
+	}
+	var hanging func(rune) bool
+	if opt.Punctuation == PunctuationHang {
+		hanging = func(r rune) bool {
+			return strings.ContainsRune(kinsokuHanging, r)
+		}
+	}
+	return gp.splitTextAtBreaksHanging(utf8Texts, cjkBreaks(utf8Texts), width, opt.hyphenSeparator(), hanging)
+}
@@ -0,0 +1,68 @@
+package gopdf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitTextCJK(t *testing.T) {
+	err := initTesting()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf := setupDefaultA4PDF(t)
+
+	// every glyph is missing in the test font, so all characters have the same width
+	width, err := pdf.MeasureTextWidth("日本語の文")
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := "日本語の文。「括弧」です"
+
+	lines, err := pdf.SplitTextWithOption(text, width, &BreakOption{Mode: BreakModeCJK})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(lines, "") != text {
+		t.Fatalf("text changed: %q", lines)
+	}
+	for _, line := range lines {
+		r := []rune(line)
+		if strings.ContainsRune(kinsokuNotStart, r[0]) || strings.ContainsRune(kinsokuNotEnd, r[len(r)-1]) {
+			t.Fatalf("kinsoku violated in %q", lines)
+		}
+	}
+	if lines[0] != "日本語の" || !strings.HasPrefix(lines[1], "文。") {
+		t.Fatalf("expected the full stop to push the character before it, got %q", lines)
+	}
+
+	hanging, err := pdf.SplitTextWithOption(text, width, &BreakOption{Mode: BreakModeCJK, Punctuation: PunctuationHang})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hanging[0] != "日本語の文。" {
+		t.Fatalf("expected the full stop to hang, got %q", hanging)
+	}
+
+	spaced, err := pdf.SplitTextWithOption("使用Go语言2023年", 1000, &BreakOption{Mode: BreakModeCJK, CJKLatinSpacing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spaced[0] != "使用 Go 语言 2023 年" {
+		t.Fatalf("unexpected spacing %q", spaced)
+	}
+
+	ok, height, err := pdf.IsFitMultiCellWithOption(&Rect{W: width, H: 1000}, text, &BreakOption{Mode: BreakModeCJK})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || height <= 0 {
+		t.Fatalf("expected the text to fit, got %v %f", ok, height)
+	}
+
+	pdf.AddPage()
+	err = pdf.MultiCellWithOption(&Rect{W: width, H: 1000}, text, CellOption{BreakOption: &BreakOption{Mode: BreakModeCJK}})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,352 @@
+package gopdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	"github.com/signintech/gopdf/fontmaker/core"
+)
+
+// ErrInvalidColorFont invalid color glyph tables
+var ErrInvalidColorFont = errors.New("invalid color font data")
+
+// pngSignature starts the data of PNG images.
+var pngSignature = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n'}
+
+// colorFont holds the color glyphs of a font: the layers of its COLR and CPAL tables,
+// and the PNG bitmaps of its CBDT and CBLC or sbix tables.
+type colorFont struct {
+	layers  map[uint][]colorLayer
+	bitmaps map[uint]colorBitmap
+}
+
+// colorLayer is a layer of a color glyph, a glyph filled with a color of the first palette or with the text color.
+type colorLayer struct {
+	glyph      uint
+	r, g, b, a uint8
+	textColor  bool
+}
+
+// colorBitmap is the PNG bitmap of a glyph in the strike of ppem pixels per em.
+type colorBitmap struct {
+	png                 []byte
+	ppem                int
+	x, y, width, height float64 // bottom-left corner relative to the origin of the glyph and size, in pixels
+}
+
+// isColorGlyph returns true if the glyph is drawn with layers or a bitmap.
+func (c *colorFont) isColorGlyph(glyph uint) bool {
+	if c == nil {
+		return false
+	}
+	_, layers := c.layers[glyph]
+	_, bitmap := c.bitmaps[glyph]
+	return layers || bitmap
+}
+
+// parseColorFont parses the color glyphs of a font, nil if it has none.
+// Only the version 0 layers of COLR tables and the PNG bitmaps of the largest strike are used.
+func parseColorFont(ttfp *core.TTFParser) (*colorFont, error) {
+	c := &colorFont{}
+	var err error
+	if colr := ttfp.TableData("COLR"); colr != nil {
+		if c.layers, err = parseCOLR(colr, ttfp.TableData("CPAL")); err != nil {
+			return nil, err
+		}
+	}
+	if cbdt := ttfp.TableData("CBDT"); cbdt != nil {
+		c.bitmaps, err = parseCBDT(ttfp.TableData("CBLC"), cbdt)
+	} else if sbix := ttfp.TableData("sbix"); sbix != nil {
+		c.bitmaps, err = parseSbix(sbix, int(ttfp.NumGlyphs()))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(c.layers) == 0 && len(c.bitmaps) == 0 {
+		return nil, nil
+	}
+	return c, nil
+}
+
+// parseCOLR returns the layers of each base glyph of a COLR table, colored with the first palette of cpal.
+func parseCOLR(colr, cpal []byte) (map[uint][]colorLayer, error) {
+	palette, err := parseCPAL(cpal)
+	if err != nil {
+		return nil, err
+	}
+	r := &fontReader{b: colr}
+	r.u16() // version
+	baseCount, baseOffset, layerOffset, layerCount := r.u16(), r.u32(), r.u32(), r.u16()
+	if r.err != nil || baseOffset+6*baseCount > len(colr) || layerOffset+4*layerCount > len(colr) {
+		return nil, ErrInvalidColorFont
+	}
+	layers := make(map[uint][]colorLayer, baseCount)
+	base := &fontReader{b: colr[baseOffset:]}
+	for i := 0; i < baseCount; i++ {
+		glyph, first, count := base.u16(), base.u16(), base.u16()
+		if first+count > layerCount {
+			return nil, ErrInvalidColorFont
+		}
+		records := &fontReader{b: colr[layerOffset+4*first:]}
+		for j := 0; j < count; j++ {
+			layer := colorLayer{glyph: uint(records.u16())}
+			if index := records.u16(); index == 0xffff {
+				layer.textColor = true
+			} else if index < len(palette) {
+				c := palette[index]
+				layer.r, layer.g, layer.b, layer.a = c[0], c[1], c[2], c[3]
+			} else {
+				return nil, ErrInvalidColorFont
+			}
+			layers[uint(glyph)] = append(layers[uint(glyph)], layer)
+		}
+	}
+	return layers, nil
+}
+
+// parseCPAL returns the red, green, blue and alpha of the colors of the first palette of a CPAL table.
+func parseCPAL(cpal []byte) ([][4]uint8, error) {
+	if cpal == nil {
+		return nil, nil
+	}
+	r := &fontReader{b: cpal}
+	r.u16() // version
+	entries := r.u16()
+	r.u16() // numPalettes
+	records, offset, first := r.u16(), r.u32(), r.u16()
+	if r.err != nil || first+entries > records || offset+4*records > len(cpal) {
+		return nil, ErrInvalidColorFont
+	}
+	palette := make([][4]uint8, entries)
+	for i := range palette {
+		b := cpal[offset+4*(first+i):]
+		palette[i] = [4]uint8{b[2], b[1], b[0], b[3]}
+	}
+	return palette, nil
+}
+
+// parseCBDT returns the PNG bitmaps of the largest strike of CBLC and CBDT tables.
+func parseCBDT(cblc, cbdt []byte) (map[uint]colorBitmap, error) {
+	r := &fontReader{b: cblc}
+	r.u32() // version
+	numSizes := r.u32()
+	arrayOffset, count, ppem := 0, 0, 0
+	for i := 0; i < numSizes; i++ {
+		size := r.sub(48)
+		offset := size.u32()
+		size.u32() // indexTablesSize
+		tables := size.u32()
+		size.read(32) // colorRef, line metrics and glyph range
+		size.u8()     // ppemX
+		if ppemY := size.u8(); ppemY > ppem {
+			arrayOffset, count, ppem = offset, tables, ppemY
+		}
+	}
+	if r.err != nil || arrayOffset+8*count > len(cblc) {
+		return nil, ErrInvalidColorFont
+	}
+
+	bitmaps := make(map[uint]colorBitmap)
+	array := &fontReader{b: cblc[arrayOffset:]}
+	for i := 0; i < count; i++ {
+		first, last, offset := array.u16(), array.u16(), arrayOffset+array.u32()
+		if offset > len(cblc) || last < first {
+			return nil, ErrInvalidColorFont
+		}
+		st := &fontReader{b: cblc[offset:]}
+		indexFormat, imageFormat, dataOffset := st.u16(), st.u16(), st.u32()
+		// the glyphs of the subtable with the start and end of their data
+		var glyphs, starts, ends []int
+		var metrics []byte
+		switch indexFormat {
+		case 1, 3:
+			offsets := make([]int, last-first+2)
+			for j := range offsets {
+				if indexFormat == 1 {
+					offsets[j] = st.u32()
+				} else {
+					offsets[j] = st.u16()
+				}
+			}
+			for j := 0; j+1 < len(offsets); j++ {
+				glyphs = append(glyphs, first+j)
+				starts, ends = append(starts, dataOffset+offsets[j]), append(ends, dataOffset+offsets[j+1])
+			}
+		case 2, 5:
+			imageSize := st.u32()
+			metrics = st.read(8)
+			n := last - first + 1
+			if indexFormat == 5 {
+				n = st.u32()
+			}
+			for j := 0; j < n; j++ {
+				glyph := first + j
+				if indexFormat == 5 {
+					glyph = st.u16()
+				}
+				glyphs = append(glyphs, glyph)
+				starts, ends = append(starts, dataOffset+j*imageSize), append(ends, dataOffset+(j+1)*imageSize)
+			}
+		case 4:
+			n := st.u32()
+			glyph, start := st.u16(), st.u16()
+			for j := 0; j < n; j++ {
+				nextGlyph, next := st.u16(), st.u16()
+				glyphs = append(glyphs, glyph)
+				starts, ends = append(starts, dataOffset+start), append(ends, dataOffset+next)
+				glyph, start = nextGlyph, next
+			}
+		default:
+			continue
+		}
+		if st.err != nil {
+			return nil, ErrInvalidColorFont
+		}
+		for j, glyph := range glyphs {
+			if starts[j] > ends[j] || ends[j] > len(cbdt) {
+				return nil, ErrInvalidColorFont
+			} else if starts[j] == ends[j] {
+				continue
+			}
+			bitmap, ok, err := cbdtBitmap(imageFormat, cbdt[starts[j]:ends[j]], metrics)
+			if err != nil {
+				return nil, err
+			} else if ok {
+				bitmap.ppem = ppem
+				bitmaps[uint(glyph)] = bitmap
+			}
+		}
+	}
+	return bitmaps, nil
+}
+
+// cbdtBitmap returns the bitmap of the glyph data of a CBDT table, false if it is not a PNG image.
+// The metrics are the ones of the index subtable, used by the images without metrics.
+func cbdtBitmap(imageFormat int, data, metrics []byte) (colorBitmap, bool, error) {
+	r := &fontReader{b: data}
+	m := r
+	switch imageFormat {
+	case 17, 18:
+	case 19:
+		if metrics == nil {
+			return colorBitmap{}, false, ErrInvalidColorFont
+		}
+		m = &fontReader{b: metrics}
+	default:
+		return colorBitmap{}, false, nil
+	}
+	height, width := m.u8(), m.u8()
+	bearingX, bearingY := int(int8(m.u8())), int(int8(m.u8()))
+	if imageFormat == 17 {
+		r.u8() // advance
+	} else if imageFormat == 18 {
+		r.read(4) // advance and vertical metrics
+	}
+	png := r.read(r.u32())
+	if r.err != nil || m.err != nil {
+		return colorBitmap{}, false, ErrInvalidColorFont
+	}
+	return colorBitmap{
+		png:    png,
+		x:      float64(bearingX),
+		y:      float64(bearingY - height),
+		width:  float64(width),
+		height: float64(height),
+	}, true, nil
+}
+
+// parseSbix returns the PNG bitmaps of the largest strike of a sbix table.
+func parseSbix(sbix []byte, numGlyphs int) (map[uint]colorBitmap, error) {
+	r := &fontReader{b: sbix}
+	r.u16() // version
+	r.u16() // flags
+	numStrikes := r.u32()
+	strike, ppem := -1, 0
+	for i := 0; i < numStrikes; i++ {
+		offset := r.u32()
+		if offset+2 > len(sbix) {
+			return nil, ErrInvalidColorFont
+		}
+		if p := int(binary.BigEndian.Uint16(sbix[offset:])); p > ppem {
+			strike, ppem = offset, p
+		}
+	}
+	if r.err != nil {
+		return nil, ErrInvalidColorFont
+	} else if strike < 0 {
+		return nil, nil
+	}
+
+	s := &fontReader{b: sbix[strike:]}
+	s.u16() // ppem
+	s.u16() // ppi
+	offsets := make([]int, numGlyphs+1)
+	for i := range offsets {
+		offsets[i] = strike + s.u32()
+	}
+	if s.err != nil {
+		return nil, ErrInvalidColorFont
+	}
+	bitmaps := make(map[uint]colorBitmap)
+	dupes := make(map[uint]uint)
+	for g := 0; g < numGlyphs; g++ {
+		start, end := offsets[g], offsets[g+1]
+		if start > end || end > len(sbix) {
+			return nil, ErrInvalidColorFont
+		} else if end-start < 8 {
+			continue
+		}
+		data := &fontReader{b: sbix[start:end]}
+		x, y := data.i16(), data.i16()
+		switch string(data.read(4)) {
+		case "png ":
+			width, height, err := pngSize(data.b)
+			if err != nil {
+				return nil, err
+			}
+			bitmaps[uint(g)] = colorBitmap{png: data.b, ppem: ppem, x: float64(x), y: float64(y), width: width, height: height}
+		case "dupe":
+			dupes[uint(g)] = uint(data.u16())
+		}
+	}
+	for g, dupe := range dupes {
+		if bitmap, ok := bitmaps[dupe]; ok {
+			bitmaps[g] = bitmap
+		}
+	}
+	return bitmaps, nil
+}
+
+// pngSize returns the width and height of a PNG image.
+func pngSize(png []byte) (float64, float64, error) {
+	if len(png) < 24 || !bytes.Equal(png[:8], pngSignature) {
+		return 0, 0, ErrInvalidColorFont
+	}
+	return float64(binary.BigEndian.Uint32(png[16:])), float64(binary.BigEndian.Uint32(png[20:])), nil
+}
+
+// addEmptyOutlines adds empty glyf and loca tables to a font of bitmaps only, such as Noto Color Emoji,
+// which has no outlines to embed. Other font data is returned as is.
+func addEmptyOutlines(fontData []byte) []byte {
+	flavor, tables, err := sfntTables(fontData)
+	if err != nil {
+		return fontData
+	}
+	_, glyf := tables["glyf"]
+	_, cff := tables["CFF "]
+	_, cff2 := tables["CFF2"]
+	_, cbdt := tables["CBDT"]
+	_, sbix := tables["sbix"]
+	head, maxp := tables["head"], tables["maxp"]
+	if glyf || cff || cff2 || !(cbdt || sbix) || len(head) < 54 || len(maxp) < 6 {
+		return fontData
+	}
+	numGlyphs := int(binary.BigEndian.Uint16(maxp[4:]))
+	tables["glyf"] = []byte{}
+	tables["loca"] = make([]byte, 4*(numGlyphs+1))
+	head = append([]byte(nil), head...)
+	binary.BigEndian.PutUint16(head[50:], 1) // long loca
+	tables["head"] = head
+	return sfntBytes(flavor, tables)
+}
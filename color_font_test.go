@@ -0,0 +1,216 @@
+package gopdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/signintech/gopdf/fontmaker/core"
+)
+
+func TestColorFontLayers(t *testing.T) {
+	err := initTesting()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ttfp := parseTestFont(t, "./test/res/LiberationSerif-Regular.ttf")
+	h, o, x, i := ttfp.Chars()['H'], ttfp.Chars()['o'], ttfp.Chars()['x'], ttfp.Chars()['I']
+
+	// H is drawn with a red o, an x of the text color and a half transparent blue I
+	var colr bytes.Buffer
+	binary.Write(&colr, binary.BigEndian, []uint16{0, 1, 0, 14, 0, 20, 3})
+	binary.Write(&colr, binary.BigEndian, []uint16{uint16(h), 0, 3})
+	binary.Write(&colr, binary.BigEndian, []uint16{uint16(o), 0, uint16(x), 0xffff, uint16(i), 1})
+	var cpal bytes.Buffer
+	binary.Write(&cpal, binary.BigEndian, []uint16{0, 2, 1, 2, 0, 14, 0})
+	cpal.Write([]byte{0, 0, 255, 255, 255, 0, 0, 128})
+	font := buildTestColorFont(ttfp, map[string][]byte{"COLR": colr.Bytes(), "CPAL": cpal.Bytes()})
+
+	pdf := GoPdf{}
+	pdf.Start(Config{PageSize: *PageSizeA4})
+	pdf.SetCompressLevel(0)
+	pdf.AddPage()
+	err = pdf.AddTTFFontData("color", font)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pdf.SetFont("color", "", 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf.SetTextColor(0, 128, 0)
+	err = pdf.Cell(nil, "AHA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buff bytes.Buffer
+	_, err = pdf.WriteTo(&buff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := buff.String()
+
+	a := ttfp.Chars()['A']
+	width := pdf.curr.FontISubset.GlyphIndexToPdfWidth
+	advance := float64(width(a)) * 20 / 1000
+	layer := fmt.Sprintf("BT\n1 0 0 1 %0.2f ", pdf.MarginLeft()+advance)
+	for _, want := range []string{
+		fmt.Sprintf("<%04X>] TJ\n3 Tr\n[<%04X>] TJ\n0 Tr\n[<%04X>] TJ\n", a, h, a),
+		"/Artifact BMC\nq\n/F1 20 Tf\n",
+		layer, fmt.Sprintf("1.000 0.000 0.000 rg\n<%04X> Tj\nET\n", o),
+		fmt.Sprintf("0.000 0.502 0.000 rg\n<%04X> Tj\nET\n", x),
+		"0.000 0.000 1.000 rg\n", fmt.Sprintf("<%04X> Tj\nET\nQ\nQ\nEMC\n", i),
+		fmt.Sprintf("/W [%d[%d]%d[%d]%d[%d]%d[%d]%d[%d]]", a, width(a), h, width(h), o, width(o), x, width(x), i, width(i)),
+		fmt.Sprintf("2 beginbfrange\n<%04X><%04X><0041>\n<%04X><%04X><0048>\nendbfrange", a, a, h, h),
+	} {
+		if !strings.Contains(content, want) {
+			t.Fatalf("expected %q in the pdf", want)
+		}
+	}
+	if n := strings.Count(content, layer); n != 3 {
+		t.Fatalf("expected 3 layers, got %d", n)
+	}
+	if !strings.Contains(content, "/ca 0.502") {
+		t.Fatal("expected the alpha of the blue layer")
+	}
+	err = pdf.WritePdf("./test/out/color_font_layers.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// emoji are mapped to surrogate pairs in ToUnicode
+	if got := utf16Hex('\U0001F600'); got != "D83DDE00" {
+		t.Fatalf("unexpected UTF-16 %s", got)
+	}
+}
+
+func TestColorFontBitmaps(t *testing.T) {
+	err := initTesting()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ttfp := parseTestFont(t, "./test/res/LiberationSerif-Regular.ttf")
+	numGlyphs := int(ttfp.NumGlyphs())
+	a, b, c := ttfp.Chars()['A'], ttfp.Chars()['B'], ttfp.Chars()['C']
+	img := testPNG(t, 10, 20)
+
+	// a strike of 100 pixels per em with a 10x20 bitmap of A, 1 pixel right of the origin and 5 pixels below the baseline
+	var cblc bytes.Buffer
+	binary.Write(&cblc, binary.BigEndian, []uint32{0x00030000, 1, 56, 20, 1, 0})
+	cblc.Write(make([]byte, 24))
+	binary.Write(&cblc, binary.BigEndian, []uint16{uint16(a), uint16(a)})
+	cblc.Write([]byte{100, 100, 32, 1})
+	binary.Write(&cblc, binary.BigEndian, []uint16{uint16(a), uint16(a), 0, 8})
+	binary.Write(&cblc, binary.BigEndian, []uint16{1, 17, 0, 4})
+	binary.Write(&cblc, binary.BigEndian, []uint32{0, uint32(9 + len(img))})
+	var cbdt bytes.Buffer
+	binary.Write(&cbdt, binary.BigEndian, uint32(0x00030000))
+	cbdt.Write([]byte{20, 10, 1, 15, 12})
+	binary.Write(&cbdt, binary.BigEndian, uint32(len(img)))
+	cbdt.Write(img)
+
+	// a strike of 50 pixels per em with the same bitmap for B and C, 5 pixels below the baseline
+	var sbix bytes.Buffer
+	binary.Write(&sbix, binary.BigEndian, []uint16{1, 1})
+	binary.Write(&sbix, binary.BigEndian, []uint32{1, 12})
+	binary.Write(&sbix, binary.BigEndian, []uint16{50, 72})
+	glyphs := make([][]byte, numGlyphs)
+	glyphs[b] = append([]byte{0, 0, 0xff, 0xfb, 'p', 'n', 'g', ' '}, img...)
+	glyphs[c] = []byte{0, 0, 0, 0, 'd', 'u', 'p', 'e', byte(b >> 8), byte(b)}
+	offset := 4 + 4*(numGlyphs+1)
+	for _, g := range glyphs {
+		binary.Write(&sbix, binary.BigEndian, uint32(offset))
+		offset += len(g)
+	}
+	binary.Write(&sbix, binary.BigEndian, uint32(offset))
+	sbix.Write(bytes.Join(glyphs, nil))
+
+	pdf := GoPdf{}
+	pdf.Start(Config{PageSize: *PageSizeA4})
+	pdf.SetCompressLevel(0)
+	pdf.AddPage()
+	// a font of bitmaps only, without outlines
+	err = pdf.AddTTFFontData("cbdt", buildTestColorFont(ttfp, map[string][]byte{"CBLC": cblc.Bytes(), "CBDT": cbdt.Bytes()}, "glyf", "loca"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pdf.AddTTFFontData("sbix", buildTestColorFont(ttfp, map[string][]byte{"sbix": sbix.Bytes()}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var images []int
+	for _, text := range []struct{ family, text string }{{"cbdt", "AB"}, {"sbix", "BC"}} {
+		err = pdf.SetFont(text.family, "", 20)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pdf.SetXY(50, 100)
+		err = pdf.Text(text.text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, g := range []uint{a, b, c} {
+			if index, ok := pdf.curr.FontISubset.colorImages[g]; ok {
+				images = append(images, index)
+			}
+		}
+	}
+	if len(images) != 3 || images[0] != images[1] || images[1] != images[2] {
+		t.Fatalf("expected one image for the bitmaps of A, B and C, got %v", images)
+	}
+	widthB := float64(pdf.curr.FontISubset.GlyphIndexToPdfWidth(b)) * 20 / 1000
+
+	var buff bytes.Buffer
+	_, err = pdf.WriteTo(&buff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := buff.String()
+	for _, want := range []string{
+		fmt.Sprintf("q\n2 0 0 4 50.20 %0.2f cm\n/I%d Do\nQ\n", pdf.curr.pageSize.H-101, images[0]+1),
+		fmt.Sprintf("q\n4 0 0 8 50.00 %0.2f cm\n/I%d Do\nQ\n", pdf.curr.pageSize.H-102, images[0]+1),
+		fmt.Sprintf("q\n4 0 0 8 %0.2f %0.2f cm\n/I%d Do\nQ\n", 50+widthB, pdf.curr.pageSize.H-102, images[0]+1),
+	} {
+		if !strings.Contains(content, want) {
+			t.Fatalf("expected %q in the pdf", want)
+		}
+	}
+	if n := strings.Count(content, " Do\n"); n != 3 {
+		t.Fatalf("expected 3 bitmaps, got %d", n)
+	}
+	err = pdf.WritePdf("./test/out/color_font_bitmaps.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// buildTestColorFont adds the tables to a font, without the tables of drop.
+func buildTestColorFont(ttfp *core.TTFParser, tables map[string][]byte, drop ...string) []byte {
+	for tag := range ttfp.GetTables() {
+		if _, ok := tables[tag]; !ok {
+			tables[tag] = ttfp.TableData(tag)
+		}
+	}
+	for _, tag := range drop {
+		delete(tables, tag)
+	}
+	return sfntBytes(0x00010000, tables)
+}
+
+func testPNG(t *testing.T, width, height int) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.NRGBA{R: 255, G: uint8(10 * y), A: 255})
+		}
+	}
+	var b bytes.Buffer
+	if err := png.Encode(&b, img); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
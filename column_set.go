@@ -0,0 +1,161 @@
+package gopdf
+
+import "math"
+
+// ColumnSetOption configures a ColumnSet.
+type ColumnSetOption struct {
+	Count       int          // number of columns, 1 if 0
+	Gutter      float64      // space between the columns
+	Width       float64      // width of all the columns with their gutters, 0 uses the space up to the right margin
+	Height      float64      // height of the columns, 0 uses the space up to the bottom margin
+	LineHeight  float64      // height of a line, 0 uses the height of the current font
+	Balance     bool         // share the lines of the last page of each Write call evenly between the columns
+	Align       int          // alignment of the lines, Left, Center, Right or Justify
+	BreakOption *BreakOption // how lines are broken, DefaultBreakOption if nil
+}
+
+// ColumnSet flows text from a column to the next one, and to a new page added by AddPage after the last column.
+type ColumnSet struct {
+	gp     *GoPdf
+	opt    ColumnSetOption
+	x      float64
+	top    float64
+	bottom float64
+	column int
+	y      float64
+}
+
+// NewColumnSet : create columns starting at the current position.
+//
+//	Usage:
+//	columns := pdf.NewColumnSet(gopdf.ColumnSetOption{Count: 2, Gutter: 20, Align: gopdf.Justify})
+//	columns.Write(article)
+func (gp *GoPdf) NewColumnSet(opt ColumnSetOption) *ColumnSet {
+	if opt.Count <= 0 {
+		opt.Count = 1
+	}
+	if opt.BreakOption == nil {
+		opt.BreakOption = &DefaultBreakOption
+	}
+	c := &ColumnSet{gp: gp, opt: opt, x: gp.GetX()}
+	if c.opt.Width == 0 {
+		c.opt.Width = gp.PointsToUnits(gp.curr.pageSize.W) - gp.MarginRight() - c.x
+	}
+	c.startRegion(gp.GetY())
+	return c
+}
+
+// ColumnWidth returns the width of a column.
+func (c *ColumnSet) ColumnWidth() float64 {
+	return (c.opt.Width - c.opt.Gutter*float64(c.opt.Count-1)) / float64(c.opt.Count)
+}
+
+// Column returns the index of the current column.
+func (c *ColumnSet) Column() int {
+	return c.column
+}
+
+// startRegion starts the columns at y on the current page.
+func (c *ColumnSet) startRegion(y float64) {
+	c.top, c.y, c.column = y, y, 0
+	if c.opt.Height != 0 {
+		c.bottom = y + c.opt.Height
+	} else {
+		c.bottom = c.gp.PointsToUnits(c.gp.curr.pageSize.H) - c.gp.MarginBottom()
+	}
+}
+
+// columnX returns the left of a column.
+func (c *ColumnSet) columnX(column int) float64 {
+	return c.x + float64(column)*(c.ColumnWidth()+c.opt.Gutter)
+}
+
+// NextColumn : continue in the next column, or on a new page after the last column.
+func (c *ColumnSet) NextColumn() {
+	if c.column < c.opt.Count-1 {
+		c.column++
+		c.y = c.top
+	} else {
+		c.gp.AddPage()
+		c.startRegion(c.gp.GetY())
+	}
+	c.gp.SetXY(c.columnX(c.column), c.y)
+}
+
+// lineHeight returns the height of a line.
+func (c *ColumnSet) lineHeight() (float64, error) {
+	if c.opt.LineHeight != 0 {
+		return c.opt.LineHeight, nil
+	}
+	return c.gp.fontLineHeight()
+}
+
+// fontLineHeight returns the height of a line of the current font.
+func (gp *GoPdf) fontLineHeight() (float64, error) {
+	_, height, _, err := createContentWithState(gp.curr.FontISubset, "", gp.curr.FontSize, gp.currTextState(), nil)
+	if err != nil {
+		return 0, err
+	}
+	return gp.PointsToUnits(height), nil
+}
+
+// alignedLine draws a line of a paragraph at the current position, justifying it unless it ends the paragraph.
+func (gp *GoPdf) alignedLine(rect *Rect, line string, paragraphEnd bool, align int) error {
+	opt := CellOption{Align: align&^Justify | Middle}
+	if align&Justify == Justify && !paragraphEnd {
+		return gp.justifiedCellWithOption(rect, line, opt)
+	}
+	return gp.CellWithOption(rect, line, opt)
+}
+
+// Write : write text in the columns, continuing after the text written before.
+// With Balance the lines of the last page are shared evenly between the remaining columns
+// and the next Write starts below them.
+func (c *ColumnSet) Write(text string) error {
+	gp := c.gp
+	lineHeight, err := c.lineHeight()
+	if err != nil {
+		return err
+	}
+	lines, paragraphEnds, err := gp.splitParagraphs(text, c.ColumnWidth(), c.opt.BreakOption)
+	if err != nil {
+		return err
+	}
+	linesPerColumn := int(math.Floor((c.bottom-c.top)/lineHeight + 1e-9))
+	if linesPerColumn <= 0 {
+		linesPerColumn = 1
+	}
+
+	balanced := 0
+	for i := 0; i < len(lines); i++ {
+		if c.opt.Balance && balanced == 0 && c.y == c.top {
+			// the remaining lines fit on this page, share them between the remaining columns
+			remaining := len(lines) - i
+			columns := c.opt.Count - c.column
+			if remaining <= linesPerColumn*columns {
+				balanced = int(math.Ceil(float64(remaining) / float64(columns)))
+			}
+		}
+		full := c.y+lineHeight > c.bottom+1e-9 && c.y > c.top
+		if balanced > 0 && c.y >= c.top+float64(balanced)*lineHeight-1e-9 {
+			full = true
+		}
+		if full {
+			c.NextColumn()
+		}
+
+		gp.SetXY(c.columnX(c.column), c.y)
+		if err := gp.alignedLine(&Rect{W: c.ColumnWidth(), H: lineHeight}, lines[i], paragraphEnds[i], c.opt.Align); err != nil {
+			return err
+		}
+		c.y += lineHeight
+	}
+
+	if balanced > 0 {
+		c.startRegion(c.top + float64(balanced)*lineHeight)
+		gp.SetXY(c.x, c.top)
+		return nil
+	}
+	gp.SetXY(c.columnX(c.column), c.y)
+	return nil
+}
@@ -0,0 +1,83 @@
+package gopdf
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestColumnSet(t *testing.T) {
+	err := initTesting()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pdf := setupDefaultA4PDF(t)
+	headers := 0
+	pdf.AddHeader(func() {
+		headers++
+	})
+	pdf.AddPage()
+	err = pdf.SetFont("LiberationSerif-Regular", "", 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pdf.SetXY(50, 50)
+	columns := pdf.NewColumnSet(ColumnSetOption{Count: 3, Gutter: 15, Width: 495, Height: 100, LineHeight: 20, Align: Justify})
+	if w := columns.ColumnWidth(); math.Abs(w-155) > 0.001 {
+		t.Fatalf("unexpected column width %f", w)
+	}
+	article := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 40)
+	if err := columns.Write(article); err != nil {
+		t.Fatal(err)
+	}
+	if pdf.GetNumberOfPages() < 2 || headers != pdf.GetNumberOfPages() {
+		t.Fatalf("expected the text to flow onto new pages with their headers, got %d pages and %d headers", pdf.GetNumberOfPages(), headers)
+	}
+
+	// the columns continue at the top margin of the new pages
+	top := pdf.MarginTop()
+	xs := map[float64]bool{}
+	for _, c := range pdf.getContent().listCache.caches {
+		if text, ok := c.(*cacheContentText); ok {
+			xs[text.x] = true
+			if text.y < top-0.001 || text.y > top+100-20+0.001 {
+				t.Fatalf("line outside of the columns at %f", text.y)
+			}
+		}
+	}
+	for x := range xs {
+		if x != 50 && x != 220 && x != 390 {
+			t.Fatalf("line outside of the columns at %f", x)
+		}
+	}
+
+	pdf.AddPage()
+	pdf.SetXY(50, 50)
+	columns = pdf.NewColumnSet(ColumnSetOption{Count: 2, Gutter: 10, Width: 400, LineHeight: 20, Balance: true})
+	pages := pdf.GetNumberOfPages()
+	if err := columns.Write(strings.Repeat("line\n", 9) + "line"); err != nil {
+		t.Fatal(err)
+	}
+	if pdf.GetNumberOfPages() != pages {
+		t.Fatal("expected the balanced text to stay on the page")
+	}
+	counts := map[float64]int{}
+	for _, c := range pdf.getContent().listCache.caches {
+		if text, ok := c.(*cacheContentText); ok {
+			counts[text.x]++
+		}
+	}
+	if counts[50] != 5 || counts[255] != 5 {
+		t.Fatalf("expected the lines to be balanced, got %v", counts)
+	}
+	if y := pdf.GetY(); math.Abs(y-150) > 0.001 || pdf.GetX() != 50 {
+		t.Fatalf("expected to continue below the columns, got %f,%f", pdf.GetX(), y)
+	}
+
+	err = pdf.WritePdf("./test/out/column_set.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -43,6 +43,8 @@ type Config struct {
 	PageSize          Rect                // The default page size for all pages in the document
 	K                 float64             // Not sure
 	Protection        PDFProtectionConfig // Protection settings
+	StrictGlyphs      bool                // Return a *MissingGlyphsError when drawing characters without a glyph in the font, instead of substituting them
+	FontCache         *FontCache          // Cache of the parsed TTF fonts shared with other documents, such as DefaultFontCache, nil to parse the fonts of each document
 }
 
 func (c Config) getUnit() int {
@@ -11,7 +11,8 @@ import (
 type ContentObj struct { //impl IObj
 	listCache listCacheContent
 	//text bytes.Buffer
-	getRoot func() *GoPdf
+	getRoot        func() *GoPdf
+	indexOfPageObj int // page of the content, -1 for the page before it
 }
 
 func (c *ContentObj) protection() *PDFProtection {
@@ -108,31 +109,38 @@ func (c *ContentObj) appendStreamPlaceHolderText(placeHolderWidth float64) error
 	fontSize := c.getRoot().curr.FontSize
 	fontStyle := c.getRoot().curr.FontStyle
 	charSpacing := c.getRoot().curr.CharSpacing
+	wordSpacing := c.getRoot().curr.wordSpacing
+	horizontalScaling := c.getRoot().curr.horizontalScaling
 	x := c.getRoot().curr.X
 	y := c.getRoot().curr.Y
 	setXCount := c.getRoot().curr.setXCount
 	fontSubset := c.getRoot().curr.FontISubset
 
 	cellOption := CellOption{Transparency: c.getRoot().curr.transparency}
+	decoration := c.getRoot().textDecorationInPoints(c.getRoot().curr.textDecoration)
 
 	cache := cacheContentText{
-		fontSubset:     fontSubset,
-		rectangle:      nil,
-		textColor:      textColor,
-		grayFill:       grayFill,
-		fontCountIndex: fontCountIndex,
-		fontSize:       fontSize,
-		fontStyle:      fontStyle,
-		charSpacing:    charSpacing,
-		setXCount:      setXCount,
-		x:              x,
-		y:              y,
-		cellOpt:        cellOption,
-		pageheight:     c.getRoot().curr.pageSize.H,
-		contentType:    ContentTypeText,
-		lineWidth:      c.getRoot().curr.lineWidth,
-		txtColorMode:   c.getRoot().curr.txtColorMode,
-		isPlaceHolder:  true,
+		fontSubset:        fontSubset,
+		rectangle:         nil,
+		textColor:         textColor,
+		grayFill:          grayFill,
+		fontCountIndex:    fontCountIndex,
+		fontSize:          fontSize,
+		fontStyle:         fontStyle,
+		charSpacing:       charSpacing,
+		wordSpacing:       wordSpacing,
+		horizontalScaling: horizontalScaling,
+		decoration:        decoration,
+		rise:              c.getRoot().curr.textRise,
+		setXCount:         setXCount,
+		x:                 x,
+		y:                 y,
+		cellOpt:           cellOption,
+		pageheight:        c.getRoot().curr.pageSize.H,
+		contentType:       ContentTypeText,
+		lineWidth:         c.getRoot().curr.lineWidth,
+		txtColorMode:      c.getRoot().curr.txtColorMode,
+		isPlaceHolder:     true,
 	}
 
 	//var err error
@@ -156,30 +164,37 @@ func (c *ContentObj) AppendStreamText(text string) error {
 	fontSize := c.getRoot().curr.FontSize
 	fontStyle := c.getRoot().curr.FontStyle
 	charSpacing := c.getRoot().curr.CharSpacing
+	wordSpacing := c.getRoot().curr.wordSpacing
+	horizontalScaling := c.getRoot().curr.horizontalScaling
 	x := c.getRoot().curr.X
 	y := c.getRoot().curr.Y
 	setXCount := c.getRoot().curr.setXCount
 	fontSubset := c.getRoot().curr.FontISubset
 
 	cellOption := CellOption{Transparency: c.getRoot().curr.transparency}
+	decoration := c.getRoot().textDecorationInPoints(c.getRoot().curr.textDecoration)
 
 	cache := cacheContentText{
-		fontSubset:     fontSubset,
-		rectangle:      nil,
-		textColor:      textColor,
-		grayFill:       grayFill,
-		fontCountIndex: fontCountIndex,
-		fontSize:       fontSize,
-		fontStyle:      fontStyle,
-		charSpacing:    charSpacing,
-		setXCount:      setXCount,
-		x:              x,
-		y:              y,
-		cellOpt:        cellOption,
-		pageheight:     c.getRoot().curr.pageSize.H,
-		contentType:    ContentTypeText,
-		lineWidth:      c.getRoot().curr.lineWidth,
-		txtColorMode:   c.getRoot().curr.txtColorMode,
+		fontSubset:        fontSubset,
+		rectangle:         nil,
+		textColor:         textColor,
+		grayFill:          grayFill,
+		fontCountIndex:    fontCountIndex,
+		fontSize:          fontSize,
+		fontStyle:         fontStyle,
+		charSpacing:       charSpacing,
+		wordSpacing:       wordSpacing,
+		horizontalScaling: horizontalScaling,
+		decoration:        decoration,
+		rise:              c.getRoot().curr.textRise,
+		setXCount:         setXCount,
+		x:                 x,
+		y:                 y,
+		cellOpt:           cellOption,
+		pageheight:        c.getRoot().curr.pageSize.H,
+		contentType:       ContentTypeText,
+		lineWidth:         c.getRoot().curr.lineWidth,
+		txtColorMode:      c.getRoot().curr.txtColorMode,
 	}
 
 	var err error
@@ -200,28 +215,39 @@ func (c *ContentObj) AppendStreamSubsetFont(rectangle *Rect, text string, cellOp
 	fontSize := c.getRoot().curr.FontSize
 	fontStyle := c.getRoot().curr.FontStyle
 	charSpacing := c.getRoot().curr.CharSpacing
+	wordSpacing := c.getRoot().curr.wordSpacing
+	horizontalScaling := c.getRoot().curr.horizontalScaling
 	x := c.getRoot().curr.X
 	y := c.getRoot().curr.Y
 	setXCount := c.getRoot().curr.setXCount
 	fontSubset := c.getRoot().curr.FontISubset
+	decoration := cellOpt.Decoration
+	if decoration == nil {
+		decoration = c.getRoot().curr.textDecoration
+	}
+	decoration = c.getRoot().textDecorationInPoints(decoration)
 
 	cache := cacheContentText{
-		fontSubset:     fontSubset,
-		rectangle:      rectangle,
-		textColor:      textColor,
-		grayFill:       grayFill,
-		fontCountIndex: fontCountIndex,
-		fontSize:       fontSize,
-		fontStyle:      fontStyle,
-		charSpacing:    charSpacing,
-		setXCount:      setXCount,
-		x:              x,
-		y:              y,
-		pageheight:     c.getRoot().curr.pageSize.H,
-		contentType:    ContentTypeCell,
-		cellOpt:        cellOpt,
-		lineWidth:      c.getRoot().curr.lineWidth,
-		txtColorMode:   c.getRoot().curr.txtColorMode,
+		fontSubset:        fontSubset,
+		rectangle:         rectangle,
+		textColor:         textColor,
+		grayFill:          grayFill,
+		fontCountIndex:    fontCountIndex,
+		fontSize:          fontSize,
+		fontStyle:         fontStyle,
+		charSpacing:       charSpacing,
+		wordSpacing:       wordSpacing,
+		horizontalScaling: horizontalScaling,
+		decoration:        decoration,
+		rise:              c.getRoot().curr.textRise,
+		setXCount:         setXCount,
+		x:                 x,
+		y:                 y,
+		pageheight:        c.getRoot().curr.pageSize.H,
+		contentType:       ContentTypeCell,
+		cellOpt:           cellOpt,
+		lineWidth:         c.getRoot().curr.lineWidth,
+		txtColorMode:      c.getRoot().curr.txtColorMode,
 	}
 	var err error
 	c.getRoot().curr.X, c.getRoot().curr.Y, err = c.listCache.appendContentText(cache, text)
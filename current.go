@@ -12,7 +12,7 @@ type Current struct {
 	CountOfL       int
 
 	FontSize      float64
-	FontStyle     int // Regular|Bold|Italic|Underline
+	FontStyle     int // Regular|Bold|Italic|Underline|Strikethrough|Overline
 	FontFontCount int
 	FontType      int // CURRENT_FONT_TYPE_IFONT or  CURRENT_FONT_TYPE_SUBSET
 
@@ -21,6 +21,14 @@ type Current struct {
 
 	CharSpacing float64
 
+	wordSpacing       float64
+	horizontalScaling float64 //percent, 0 means 100
+	textRise          float64
+
+	textDecoration *TextDecoration
+
+	tabStops []TabStop
+
 	FontISubset *SubsetFontObj // FontType == CURRENT_FONT_TYPE_SUBSET
 
 	//page
@@ -0,0 +1,121 @@
+package gopdf
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/signintech/gopdf/fontmaker/core"
+)
+
+// FontCache is a goroutine-safe cache of parsed fonts, shared by the documents started with it as Config.FontCache.
+// Each font is parsed once for its data and the options that change its parsing, its tables are then only read,
+// and every document only tracks the glyphs it uses. The zero value is an empty cache ready to use.
+type FontCache struct {
+	mu    sync.Mutex
+	fonts map[string]*cachedFont
+}
+
+// DefaultFontCache is the process-wide font cache.
+//
+//	pdf.Start(gopdf.Config{PageSize: *gopdf.PageSizeA4, FontCache: gopdf.DefaultFontCache})
+var DefaultFontCache = &FontCache{}
+
+// cachedFont is a font parsed once, read-only afterwards.
+type cachedFont struct {
+	once  sync.Once
+	ttfp  *core.TTFParser
+	color *colorFont
+	err   error
+}
+
+// Len returns the number of fonts in the cache.
+func (fc *FontCache) Len() int {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	return len(fc.fonts)
+}
+
+// Clear removes all the fonts from the cache, the documents using them keep them.
+func (fc *FontCache) Clear() {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	fc.fonts = nil
+}
+
+func (fc *FontCache) font(key string) *cachedFont {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	if fc.fonts == nil {
+		fc.fonts = make(map[string]*cachedFont)
+	}
+	font, ok := fc.fonts[key]
+	if !ok {
+		font = &cachedFont{}
+		fc.fonts[key] = font
+	}
+	return font
+}
+
+func (fc *FontCache) remove(key string, font *cachedFont) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	if fc.fonts[key] == font {
+		delete(fc.fonts, key)
+	}
+}
+
+// setTTF sets the font of key to s, parsed with the options of s from the data of load at the first use of key.
+// A font that fails to parse is not kept, so that it is parsed again the next time.
+func (fc *FontCache) setTTF(s *SubsetFontObj, key string, load func() ([]byte, error)) error {
+	option := s.ttfFontOption
+	key = fmt.Sprintf("%s|%t|%d|%s|%v", key, option.UseKerning, option.FaceIndex, option.FaceName, option.Axes)
+	font := fc.font(key)
+	font.once.Do(func() {
+		data, err := load()
+		if err != nil {
+			font.err = err
+			return
+		}
+		parsed := SubsetFontObj{ttfFontOption: option}
+		font.err = parsed.SetTTFData(data)
+		font.ttfp, font.color = parsed.ttfp, parsed.color
+	})
+	if font.err != nil {
+		fc.remove(key, font)
+		return font.err
+	}
+	s.ttfp, s.color = font.ttfp, font.color
+	return nil
+}
+
+// fontDataKey returns the cache key of the font data.
+func fontDataKey(data []byte) string {
+	return fmt.Sprintf("data:%x", sha256.Sum256(data))
+}
+
+// fontFileKey returns the cache key of the font file, which changes when the file is modified.
+func fontFileKey(ttfpath string, info os.FileInfo) string {
+	if abs, err := filepath.Abs(ttfpath); err == nil {
+		ttfpath = abs
+	}
+	return fmt.Sprintf("file:%s:%d:%d", ttfpath, info.Size(), info.ModTime().UnixNano())
+}
+
+// addCachedTTFFont adds the font of key from the FontCache of the config, loading its data with load at the first use of key.
+func (gp *GoPdf) addCachedTTFFont(family string, key string, load func() ([]byte, error), option TtfOption) error {
+	subsetFont := new(SubsetFontObj)
+	subsetFont.init(func() *GoPdf {
+		return gp
+	})
+	subsetFont.SetTtfFontOption(option)
+	subsetFont.SetFamily(family)
+	err := gp.config.FontCache.setTTF(subsetFont, key, load)
+	if err != nil {
+		return err
+	}
+
+	return gp.setSubsetFontObject(subsetFont, family, option)
+}
@@ -0,0 +1,182 @@
+package gopdf
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sync"
+	"testing"
+)
+
+func TestFontCache(t *testing.T) {
+	err := initTesting()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile("./test/res/LiberationSerif-Regular.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cache FontCache
+	generate := func(cache *FontCache, text string, add func(pdf *GoPdf) error) (*GoPdf, []byte) {
+		pdf := &GoPdf{}
+		pdf.Start(Config{PageSize: *PageSizeA4, FontCache: cache})
+		pdf.AddPage()
+		if err := add(pdf); err != nil {
+			t.Fatal(err)
+		}
+		if err := pdf.SetFont("LiberationSerif-Regular", "", 14); err != nil {
+			t.Fatal(err)
+		}
+		if err := pdf.Cell(nil, text); err != nil {
+			t.Fatal(err)
+		}
+		var buff bytes.Buffer
+		if _, err := pdf.WriteTo(&buff); err != nil {
+			t.Fatal(err)
+		}
+		return pdf, buff.Bytes()
+	}
+	fromData := func(pdf *GoPdf) error {
+		return pdf.AddTTFFontData("LiberationSerif-Regular", data)
+	}
+	fromPath := func(pdf *GoPdf) error {
+		return pdf.AddTTFFont("LiberationSerif-Regular", "./test/res/LiberationSerif-Regular.ttf")
+	}
+	fromReader := func(pdf *GoPdf) error {
+		return pdf.AddTTFFontByReader("LiberationSerif-Regular", bytes.NewReader(data))
+	}
+
+	_, want := generate(nil, "Hello", fromData)
+	first, got := generate(&cache, "Hello", fromData)
+	if !bytes.Equal(got, want) {
+		t.Fatal("expected the same pdf with the font cache")
+	}
+	second, _ := generate(&cache, "World", fromReader)
+	third, _ := generate(&cache, "Hello", fromPath)
+	if n := cache.Len(); n != 2 {
+		t.Fatalf("expected the fonts of the data and of the file, got %d", n)
+	}
+	f1, f2, f3 := first.curr.FontISubset, second.curr.FontISubset, third.curr.FontISubset
+	if f1.ttfp != f2.ttfp || f1.ttfp == f3.ttfp {
+		t.Fatal("expected the parsed font to be shared by the documents of the same data")
+	}
+	// the glyphs used are tracked per document
+	if f1.CharacterToGlyphIndex.KeyExists('W') || !f2.CharacterToGlyphIndex.KeyExists('W') || f2.CharacterToGlyphIndex.KeyExists('H') {
+		t.Fatal("expected the glyphs of each document only")
+	}
+
+	// options changing the parsing are parsed again
+	pdf := &GoPdf{}
+	pdf.Start(Config{PageSize: *PageSizeA4, FontCache: &cache})
+	err = pdf.AddTTFFontDataWithOption("kerning", data, TtfOption{UseKerning: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := cache.Len(); n != 3 {
+		t.Fatalf("expected the font with kerning, got %d", n)
+	}
+
+	// errors are not cached
+	err = pdf.AddTTFFontData("broken", data[:100])
+	if err == nil {
+		t.Fatal("expected an error for a broken font")
+	}
+	if n := cache.Len(); n != 3 {
+		t.Fatalf("expected the broken font not to be cached, got %d", n)
+	}
+	if err := pdf.AddTTFFont("missing", "./test/res/missing.ttf"); !os.IsNotExist(err) {
+		t.Fatalf("expected a missing file, got %v", err)
+	}
+	cache.Clear()
+	if n := cache.Len(); n != 0 {
+		t.Fatalf("expected an empty cache, got %d", n)
+	}
+
+	// documents generated concurrently share the fonts of the cache and the container
+	var container FontContainer
+	err = container.AddTTFFontData("LiberationSerif-Regular", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromContainer := func(pdf *GoPdf) error {
+		return pdf.AddTTFFontFromFontContainer("LiberationSerif-Regular", &container)
+	}
+	var wg sync.WaitGroup
+	outputs := make([][]byte, 16)
+	for i := range outputs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			add := fromData
+			if i%2 == 1 {
+				add = fromContainer
+			}
+			_, outputs[i] = generate(&cache, "Hello", add)
+		}(i)
+	}
+	wg.Wait()
+	for _, output := range outputs {
+		if !bytes.Equal(output, want) {
+			t.Fatal("expected the same pdf from every goroutine")
+		}
+	}
+	if n := cache.Len(); n != 1 {
+		t.Fatalf("expected the font to be parsed once, got %d", n)
+	}
+}
+
+func benchmarkAddTTFFont(b *testing.B, cache *FontCache) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		pdf := GoPdf{}
+		pdf.Start(Config{PageSize: *PageSizeA4, FontCache: cache})
+		if err := pdf.AddTTFFont("LiberationSerif-Regular", "./test/res/LiberationSerif-Regular.ttf"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkAddTTFFont(b *testing.B) {
+	benchmarkAddTTFFont(b, nil)
+}
+
+func BenchmarkAddTTFFontWithFontCache(b *testing.B) {
+	benchmarkAddTTFFont(b, &FontCache{})
+}
+
+func benchmarkGenerateParallel(b *testing.B, cache *FontCache) {
+	data, err := os.ReadFile("./test/res/LiberationSerif-Regular.ttf")
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			pdf := GoPdf{}
+			pdf.Start(Config{PageSize: *PageSizeA4, FontCache: cache})
+			pdf.AddPage()
+			if err := pdf.AddTTFFontData("LiberationSerif-Regular", data); err != nil {
+				b.Fatal(err)
+			}
+			if err := pdf.SetFont("LiberationSerif-Regular", "", 14); err != nil {
+				b.Fatal(err)
+			}
+			if err := pdf.Cell(nil, "Hello World"); err != nil {
+				b.Fatal(err)
+			}
+			if _, err := pdf.WriteTo(io.Discard); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkGenerateParallel(b *testing.B) {
+	benchmarkGenerateParallel(b, nil)
+}
+
+func BenchmarkGenerateParallelWithFontCache(b *testing.B) {
+	benchmarkGenerateParallel(b, &FontCache{})
+}
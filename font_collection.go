@@ -0,0 +1,101 @@
+package gopdf
+
+import (
+	"errors"
+	"os"
+
+	"github.com/signintech/gopdf/fontmaker/core"
+)
+
+// ErrFontFaceNotFound is returned when the face selected by TtfOption is not in the font collection.
+var ErrFontFaceNotFound = errors.New("font face not found in the collection")
+
+// FontFace is a font of a font file, one of the fonts of a collection (.ttc/.otc) or the font of a single font file.
+type FontFace struct {
+	Index          int
+	Family         string
+	Style          string
+	FullName       string
+	PostScriptName string
+}
+
+// ListFontFaces : list the fonts of a collection, with their index to select them with TtfOption.FaceIndex.
+//
+//	Usage:
+//	faces, _ := gopdf.ListFontFaces(data)
+//	for _, face := range faces {
+//		fmt.Println(face.Index, face.Family, face.Style, face.PostScriptName)
+//	}
+func ListFontFaces(fontData []byte) ([]FontFace, error) {
+	if !core.IsFontCollection(fontData) {
+		names, err := core.ParseFontNames(fontData)
+		if err != nil {
+			return nil, err
+		}
+		return []FontFace{newFontFace(0, names)}, nil
+	}
+	count, err := core.CollectionFaceCount(fontData)
+	if err != nil {
+		return nil, err
+	}
+	var faces []FontFace
+	for i := 0; i < count; i++ {
+		face, err := core.ExtractCollectionFace(fontData, i)
+		if err != nil {
+			return nil, err
+		}
+		names, err := core.ParseFontNames(face)
+		if err != nil {
+			return nil, err
+		}
+		faces = append(faces, newFontFace(i, names))
+	}
+	return faces, nil
+}
+
+// ListFontFacesFromFile : list the fonts of a collection file.
+func ListFontFacesFromFile(path string) ([]FontFace, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ListFontFaces(data)
+}
+
+func newFontFace(index int, names core.FontNames) FontFace {
+	return FontFace{
+		Index:          index,
+		Family:         names.Family,
+		Style:          names.Style,
+		FullName:       names.FullName,
+		PostScriptName: names.PostScriptName,
+	}
+}
+
+// selectFontFace returns the data of the font selected by option when fontData is a collection.
+func selectFontFace(fontData []byte, option TtfOption) ([]byte, error) {
+	if !core.IsFontCollection(fontData) {
+		return fontData, nil
+	}
+	if option.FaceName == "" {
+		face, err := core.ExtractCollectionFace(fontData, option.FaceIndex)
+		if err == core.ERROR_FACE_INDEX_OUT_OF_RANGE {
+			return nil, ErrFontFaceNotFound
+		}
+		return face, err
+	}
+	count, err := core.CollectionFaceCount(fontData)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < count; i++ {
+		face, err := core.ExtractCollectionFace(fontData, i)
+		if err != nil {
+			return nil, err
+		}
+		if names, err := core.ParseFontNames(face); err == nil && names.PostScriptName == option.FaceName {
+			return face, nil
+		}
+	}
+	return nil, ErrFontFaceNotFound
+}
@@ -0,0 +1,132 @@
+package gopdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/signintech/gopdf/fontmaker/core"
+)
+
+func TestFontCollection(t *testing.T) {
+	err := initTesting()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := buildTestTTC(t)
+
+	faces, err := ListFontFaces(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(faces) != 2 {
+		t.Fatalf("expected 2 faces, got %v", faces)
+	}
+	if faces[0].PostScriptName != "LiberationSerif" || faces[1].Index != 1 ||
+		faces[1].Family != "Test Serif" || faces[1].Style != "Bold" || faces[1].PostScriptName != "TestSerif-Bold" {
+		t.Fatalf("unexpected faces %+v", faces)
+	}
+
+	pdf := GoPdf{}
+	pdf.Start(Config{PageSize: *PageSizeA4})
+	pdf.AddPage()
+	err = pdf.AddTTFFontData("first", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	option := defaultTtfFontOption()
+	option.FaceName = "TestSerif-Bold"
+	err = pdf.AddTTFFontByReaderWithOption("bold", bytes.NewReader(data), option)
+	if err != nil {
+		t.Fatal(err)
+	}
+	option.FaceName = ""
+	option.FaceIndex = 2
+	if err := pdf.AddTTFFontDataWithOption("missing", data, option); err != ErrFontFaceNotFound {
+		t.Fatalf("expected ErrFontFaceNotFound, got %v", err)
+	}
+	option.FaceName = "Missing"
+	if err := pdf.AddTTFFontDataWithOption("missing", data, option); err != ErrFontFaceNotFound {
+		t.Fatalf("expected ErrFontFaceNotFound, got %v", err)
+	}
+
+	for _, family := range []string{"first", "bold"} {
+		err = pdf.SetFont(family, "", 14)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = pdf.Cell(nil, "Hello "+family)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pdf.Br(20)
+	}
+	err = pdf.WritePdf("./test/out/font_collection.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// buildTestTTC builds a collection of LiberationSerif-Regular and of the same font renamed Test Serif Bold,
+// sharing all their tables but the name table.
+func buildTestTTC(t *testing.T) []byte {
+	var ttfp core.TTFParser
+	if err := ttfp.Parse("./test/res/LiberationSerif-Regular.ttf"); err != nil {
+		t.Fatal(err)
+	}
+	var tags []string
+	for tag := range ttfp.GetTables() {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	var names []byte
+	records := []struct {
+		id   uint16
+		name string
+	}{{1, "Test Serif"}, {2, "Bold"}, {6, "TestSerif-Bold"}}
+	header := []uint16{0, uint16(len(records)), uint16(6 + 12*len(records))}
+	var headerBuff bytes.Buffer
+	binary.Write(&headerBuff, binary.BigEndian, header)
+	for _, record := range records {
+		var s bytes.Buffer
+		binary.Write(&s, binary.BigEndian, utf16.Encode([]rune(record.name)))
+		binary.Write(&headerBuff, binary.BigEndian, []uint16{3, 1, 0x409, record.id, uint16(s.Len()), uint16(len(names))})
+		names = append(names, s.Bytes()...)
+	}
+	nameTable := append(headerBuff.Bytes(), names...)
+
+	directorySize := 12 + 16*len(tags)
+	tablesStart := 12 + 8 + 2*directorySize
+	var tables bytes.Buffer
+	offsets := map[string]int{}
+	for _, tag := range append(tags, "name2") {
+		data := ttfp.TableData(tag)
+		if tag == "name2" {
+			data = nameTable
+		}
+		offsets[tag] = tablesStart + tables.Len()
+		tables.Write(data)
+		tables.Write(make([]byte, (4-len(data)%4)%4))
+	}
+
+	var b bytes.Buffer
+	b.WriteString("ttcf")
+	binary.Write(&b, binary.BigEndian, []uint32{0x00010000, 2, 20, uint32(20 + directorySize)})
+	for face := 0; face < 2; face++ {
+		binary.Write(&b, binary.BigEndian, []uint32{0x00010000})
+		binary.Write(&b, binary.BigEndian, []uint16{uint16(len(tags)), 0, 0, 0})
+		for _, tag := range tags {
+			offset, length := offsets[tag], len(ttfp.TableData(tag))
+			if tag == "name" && face == 1 {
+				offset, length = offsets["name2"], len(nameTable)
+			}
+			b.WriteString(tag)
+			binary.Write(&b, binary.BigEndian, []uint32{0, uint32(offset), uint32(length)})
+		}
+	}
+	b.Write(tables.Bytes())
+	return b.Bytes()
+}
@@ -12,6 +12,8 @@ import (
 var ErrFontNotFound = errors.New("font not found")
 
 // FontContainer manages a collection of fonts.
+// It is safe for concurrent use, the documents adding a font of the container share its parsed tables
+// and only track the glyphs they use.
 type FontContainer struct {
 	fonts sync.Map
 }
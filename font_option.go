@@ -12,6 +12,20 @@ const Italic = 1 //000001
 const Bold = 2 //000010
 // Underline - font style underline
 const Underline = 4 //000100
+// Strikethrough - font style strikethrough
+const Strikethrough = 8 //001000
+// Overline - font style overline
+const Overline = 16 //010000
+// Superscript - font style superscript, smaller text raised above the baseline
+const Superscript = 32 //100000
+// Subscript - font style subscript, smaller text lowered below the baseline
+const Subscript = 64 //1000000
+
+// decorationStyles are the styles drawn as lines over the text, they are not part of the font
+const decorationStyles = Underline | Strikethrough | Overline
+
+// nonFontStyles are the styles that do not select a font
+const nonFontStyles = decorationStyles | Superscript | Subscript
 
 func getConvertedStyle(fontStyle string) (style int) {
 	fontStyle = strings.ToUpper(fontStyle)
@@ -24,5 +38,11 @@ func getConvertedStyle(fontStyle string) (style int) {
 	if strings.Contains(fontStyle, "U") {
 		style = style | Underline
 	}
+	if strings.Contains(fontStyle, "S") {
+		style = style | Strikethrough
+	}
+	if strings.Contains(fontStyle, "O") {
+		style = style | Overline
+	}
 	return
 }
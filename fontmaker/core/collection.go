@@ -0,0 +1,140 @@
+package core
+
+import (
+	"encoding/binary"
+	"errors"
+	"unicode/utf16"
+)
+
+var ERROR_INVALID_COLLECTION = errors.New("Invalid font collection")
+var ERROR_FACE_INDEX_OUT_OF_RANGE = errors.New("Font face index out of range")
+
+// FontNames are the names of a font from its name table.
+type FontNames struct {
+	Family         string // typographic family name, or font family name when absent
+	Style          string // typographic subfamily name, or font subfamily name when absent
+	FullName       string
+	PostScriptName string
+}
+
+// IsFontCollection returns true when data is a TrueType or OpenType collection (.ttc/.otc).
+func IsFontCollection(data []byte) bool {
+	return len(data) >= 4 && string(data[:4]) == "ttcf"
+}
+
+// CollectionFaceCount returns the number of fonts of a collection.
+func CollectionFaceCount(data []byte) (int, error) {
+	if !IsFontCollection(data) || len(data) < 12 {
+		return 0, ERROR_INVALID_COLLECTION
+	}
+	count := int(binary.BigEndian.Uint32(data[8:]))
+	if len(data) < 12+count*4 {
+		return 0, ERROR_INVALID_COLLECTION
+	}
+	return count, nil
+}
+
+// ExtractCollectionFace returns the font at index of a collection as standalone font data.
+// The tables of a collection can be shared by several fonts and their offsets are relative to the collection,
+// so the tables of the font are copied after a new table directory.
+func ExtractCollectionFace(data []byte, index int) ([]byte, error) {
+	count, err := CollectionFaceCount(data)
+	if err != nil {
+		return nil, err
+	}
+	if index < 0 || index >= count {
+		return nil, ERROR_FACE_INDEX_OUT_OF_RANGE
+	}
+	offset := int(binary.BigEndian.Uint32(data[12+index*4:]))
+	if offset+12 > len(data) {
+		return nil, ERROR_INVALID_COLLECTION
+	}
+	numTables := int(binary.BigEndian.Uint16(data[offset+4:]))
+	if offset+12+numTables*16 > len(data) {
+		return nil, ERROR_INVALID_COLLECTION
+	}
+
+	font := make([]byte, 12+numTables*16)
+	copy(font, data[offset:offset+12])
+	for i := 0; i < numTables; i++ {
+		entry := data[offset+12+i*16 : offset+12+(i+1)*16]
+		tableOffset := int(binary.BigEndian.Uint32(entry[8:]))
+		length := int(binary.BigEndian.Uint32(entry[12:]))
+		if tableOffset+length > len(data) {
+			return nil, ERROR_INVALID_COLLECTION
+		}
+		copy(font[12+i*16:], entry)
+		binary.BigEndian.PutUint32(font[12+i*16+8:], uint32(len(font)))
+		font = append(font, data[tableOffset:tableOffset+length]...)
+		for len(font)%4 != 0 {
+			font = append(font, 0)
+		}
+	}
+	return font, nil
+}
+
+// ParseFontNames reads the names of a font.
+func ParseFontNames(data []byte) (FontNames, error) {
+	var names FontNames
+	if len(data) < 12 {
+		return names, ERROR_INVALID_COLLECTION
+	}
+	numTables := int(binary.BigEndian.Uint16(data[4:]))
+	var table []byte
+	for i := 0; i < numTables && 12+(i+1)*16 <= len(data); i++ {
+		entry := data[12+i*16:]
+		if string(entry[:4]) != "name" {
+			continue
+		}
+		offset, length := int(binary.BigEndian.Uint32(entry[8:])), int(binary.BigEndian.Uint32(entry[12:]))
+		if offset+length > len(data) {
+			return names, ErrTableNotFound
+		}
+		table = data[offset : offset+length]
+	}
+	if len(table) < 6 {
+		return names, ErrTableNotFound
+	}
+
+	count, stringOffset := int(binary.BigEndian.Uint16(table[2:])), int(binary.BigEndian.Uint16(table[4:]))
+	found := map[uint16]string{}
+	unicode := map[uint16]bool{}
+	for i := 0; i < count && 6+(i+1)*12 <= len(table); i++ {
+		record := table[6+i*12:]
+		platformID := binary.BigEndian.Uint16(record)
+		nameID := binary.BigEndian.Uint16(record[6:])
+		length, offset := int(binary.BigEndian.Uint16(record[8:])), int(binary.BigEndian.Uint16(record[10:]))
+		start := stringOffset + offset
+		if start+length > len(table) || unicode[nameID] {
+			continue
+		}
+		s := table[start : start+length]
+		switch platformID {
+		case 0, 3: // Unicode and Windows names are UTF-16BE, preferred to Macintosh names
+			u := make([]uint16, len(s)/2)
+			for j := range u {
+				u[j] = binary.BigEndian.Uint16(s[j*2:])
+			}
+			found[nameID] = string(utf16.Decode(u))
+			unicode[nameID] = true
+		case 1:
+			if _, ok := found[nameID]; !ok {
+				found[nameID] = string(s)
+			}
+		}
+	}
+
+	first := func(ids ...uint16) string {
+		for _, id := range ids {
+			if s, ok := found[id]; ok && s != "" {
+				return s
+			}
+		}
+		return ""
+	}
+	names.Family = first(16, 1)
+	names.Style = first(17, 2)
+	names.FullName = first(4)
+	names.PostScriptName = first(6)
+	return names, nil
+}
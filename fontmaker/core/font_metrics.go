@@ -0,0 +1,47 @@
+package core
+
+// FontMetrics are the metrics of a font that is not read from a font file, like the standard 14 fonts of PDF.
+type FontMetrics struct {
+	PostScriptName     string
+	UnitsPerEm         uint
+	Widths             []uint // advance widths indexed by glyph
+	Ascender           int
+	Descender          int
+	CapHeight          int
+	XHeight            int
+	XMin, YMin         int
+	XMax, YMax         int
+	ItalicAngle        int
+	UnderlinePosition  int
+	UnderlineThickness int
+	IsFixedPitch       bool
+	Symbolic           bool
+}
+
+// SetFontMetrics initializes the parser with metrics instead of parsing a font file.
+func (t *TTFParser) SetFontMetrics(m FontMetrics) {
+	t.tables = make(map[string]TableDirectoryEntry)
+	t.postScriptName = m.PostScriptName
+	t.unitsPerEm = m.UnitsPerEm
+	t.widths = append([]uint(nil), m.Widths...)
+	t.numberOfHMetrics = uint(len(m.Widths))
+	t.numGlyphs = uint(len(m.Widths))
+	t.ascender, t.typoAscender = m.Ascender, m.Ascender
+	t.descender, t.typoDescender = m.Descender, m.Descender
+	t.usWinAscent = uint(m.Ascender)
+	if m.Descender < 0 {
+		t.usWinDescent = uint(-m.Descender)
+	} else {
+		t.usWinDescent = uint(m.Descender)
+	}
+	t.capHeight = m.CapHeight
+	t.os2Version = 2
+	t.sxHeight = m.XHeight
+	t.xMin, t.yMin, t.xMax, t.yMax = m.XMin, m.YMin, m.XMax, m.YMax
+	t.italicAngle = m.ItalicAngle
+	t.underlinePosition = m.UnderlinePosition
+	t.underlineThickness = m.UnderlineThickness
+	t.isFixedPitch = m.IsFixedPitch
+	t.symbol = m.Symbolic
+	t.Embeddable = true
+}
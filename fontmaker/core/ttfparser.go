@@ -17,6 +17,7 @@ var ERROR_NO_UNICODE_ENCODING_FOUND = errors.New("No Unicode encoding found")
 var ERROR_UNEXPECTED_SUBTABLE_FORMAT = errors.New("Unexpected subtable format")
 var ERROR_INCORRECT_MAGIC_NUMBER = errors.New("Incorrect magic number")
 var ERROR_POSTSCRIPT_NAME_NOT_FOUND = errors.New("PostScript name not found")
+var ERROR_GLYPH_NOT_FOUND = errors.New("Glyph not found")
 
 // TTFParser true type font parser
 type TTFParser struct {
@@ -32,6 +33,7 @@ type TTFParser struct {
 	numberOfHMetrics uint
 	ascender         int
 	descender        int
+	lineGap          int
 	//end Hhea
 
 	numGlyphs      uint
@@ -47,6 +49,13 @@ type TTFParser struct {
 	typoDescender int
 	capHeight     int
 	sxHeight      int
+	strikeoutSize int
+	strikeoutPos  int
+
+	subscriptYSize     int
+	subscriptYOffset   int
+	superscriptYSize   int
+	superscriptYOffset int
 
 	//post
 	italicAngle        int
@@ -158,6 +167,40 @@ func (t *TTFParser) Descender() int {
 	return descender
 }
 
+// LineGap returns the line gap of the hhea table.
+func (t *TTFParser) LineGap() int {
+	return t.lineGap
+}
+
+// TypoLineGap returns the line gap of the OS/2 table.
+func (t *TTFParser) TypoLineGap() int {
+	return t.sTypoLineGap
+}
+
+// GlyphBBox returns the bounding box of a glyph from the glyf table, in font units.
+// A glyph without outline, like a space, has an empty bounding box.
+// The fonts with CFF outlines have no glyf table, ERROR_GLYPH_NOT_FOUND is returned for them.
+func (t *TTFParser) GlyphBBox(glyph uint) (xMin, yMin, xMax, yMax int, err error) {
+	glyf, ok := t.tables["glyf"]
+	if !ok || glyph+1 >= uint(len(t.LocaTable)) {
+		return 0, 0, 0, 0, ERROR_GLYPH_NOT_FOUND
+	}
+	start, end := t.LocaTable[glyph], t.LocaTable[glyph+1]
+	if start == end {
+		return 0, 0, 0, 0, nil
+	}
+	offset := glyf.Offset + start
+	if offset+10 > uint(len(t.cachedFontData)) {
+		return 0, 0, 0, 0, ERROR_GLYPH_NOT_FOUND
+	}
+	header := t.cachedFontData[offset : offset+10]
+	xMin = int(int16(binary.BigEndian.Uint16(header[2:])))
+	yMin = int(int16(binary.BigEndian.Uint16(header[4:])))
+	xMax = int(int16(binary.BigEndian.Uint16(header[6:])))
+	yMax = int(int16(binary.BigEndian.Uint16(header[8:])))
+	return xMin, yMin, xMax, yMax, nil
+}
+
 func (t *TTFParser) TypoAscender() int {
 	return t.typoAscender
 }
@@ -166,6 +209,36 @@ func (t *TTFParser) TypoDescender() int {
 	return t.typoDescender
 }
 
+// StrikeoutSize thickness of the strikeout stroke
+func (t *TTFParser) StrikeoutSize() int {
+	return t.strikeoutSize
+}
+
+// StrikeoutPosition position of the top of the strikeout stroke above the baseline
+func (t *TTFParser) StrikeoutPosition() int {
+	return t.strikeoutPos
+}
+
+// SubscriptYSize font size of subscripts
+func (t *TTFParser) SubscriptYSize() int {
+	return t.subscriptYSize
+}
+
+// SubscriptYOffset distance of the subscript baseline below the baseline
+func (t *TTFParser) SubscriptYOffset() int {
+	return t.subscriptYOffset
+}
+
+// SuperscriptYSize font size of superscripts
+func (t *TTFParser) SuperscriptYSize() int {
+	return t.superscriptYSize
+}
+
+// SuperscriptYOffset distance of the superscript baseline above the baseline
+func (t *TTFParser) SuperscriptYOffset() int {
+	return t.superscriptYOffset
+}
+
 // CapHeight https://en.wikipedia.org/wiki/Cap_height
 func (t *TTFParser) CapHeight() int {
 	return t.capHeight
@@ -228,7 +301,7 @@ func (t *TTFParser) ParseFontData(fontData []byte) error {
 	if err != nil {
 		return err
 	}
-	if !bytes.Equal(version, []byte{0x00, 0x01, 0x00, 0x00}) {
+	if !bytes.Equal(version, []byte{0x00, 0x01, 0x00, 0x00}) && !bytes.Equal(version, []byte("OTTO")) {
 		return errors.New("Unrecognized file (font) format")
 	}
 
@@ -306,9 +379,11 @@ func (t *TTFParser) ParseFontData(fontData []byte) error {
 	if err != nil {
 		return err
 	}
-	err = t.ParseLoca(fd)
-	if err != nil {
-		return err
+	if !t.IsCFF() {
+		err = t.ParseLoca(fd)
+		if err != nil {
+			return err
+		}
 	}
 
 	if t.useKerning {
@@ -327,6 +402,22 @@ func (t *TTFParser) FontData() []byte {
 	return t.cachedFontData
 }
 
+// IsCFF returns true when the outlines of the font are in a CFF or CFF2 table (OpenType .otf) instead of a glyf table.
+func (t *TTFParser) IsCFF() bool {
+	_, cff := t.tables["CFF "]
+	_, cff2 := t.tables["CFF2"]
+	return cff || cff2
+}
+
+// TableData returns the content of the table tag, nil if the font has no such table.
+func (t *TTFParser) TableData(tag string) []byte {
+	table, ok := t.tables[tag]
+	if !ok || table.Offset+table.Length > uint(len(t.cachedFontData)) {
+		return nil
+	}
+	return t.cachedFontData[table.Offset : table.Offset+table.Length]
+}
+
 // ParseLoca parse loca table https://www.microsoft.com/typography/otspec/loca.htm
 func (t *TTFParser) ParseLoca(fd *bytes.Reader) error {
 
@@ -433,7 +524,48 @@ func (t *TTFParser) ParseOS2(fd *bytes.Reader) error {
 	}
 	t.Embeddable = (fsType != 2) && ((fsType & 0x200) == 0)
 
-	err = t.Skip(fd, (11*2)+10+(4*4)+4)
+	err = t.Skip(fd, 2) // ySubscriptXSize
+	if err != nil {
+		return err
+	}
+	t.subscriptYSize, err = t.ReadShort(fd)
+	if err != nil {
+		return err
+	}
+	err = t.Skip(fd, 2) // ySubscriptXOffset
+	if err != nil {
+		return err
+	}
+	t.subscriptYOffset, err = t.ReadShort(fd)
+	if err != nil {
+		return err
+	}
+	err = t.Skip(fd, 2) // ySuperscriptXSize
+	if err != nil {
+		return err
+	}
+	t.superscriptYSize, err = t.ReadShort(fd)
+	if err != nil {
+		return err
+	}
+	err = t.Skip(fd, 2) // ySuperscriptXOffset
+	if err != nil {
+		return err
+	}
+	t.superscriptYOffset, err = t.ReadShort(fd)
+	if err != nil {
+		return err
+	}
+	t.strikeoutSize, err = t.ReadShort(fd)
+	if err != nil {
+		return err
+	}
+	t.strikeoutPos, err = t.ReadShort(fd)
+	if err != nil {
+		return err
+	}
+
+	err = t.Skip(fd, 2+10+(4*4)+4) // sFamilyClass, panose, ulUnicodeRange, achVendID
 	if err != nil {
 		return err
 	}
@@ -917,7 +1049,12 @@ func (t *TTFParser) ParseHhea(fd *bytes.Reader) error {
 		return err
 	}
 
-	err = t.Skip(fd, 13*2)
+	t.lineGap, err = t.ReadShort(fd)
+	if err != nil {
+		return err
+	}
+
+	err = t.Skip(fd, 12*2)
 	if err != nil {
 		return err
 	}
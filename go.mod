@@ -2,4 +2,7 @@ module github.com/signintech/gopdf
 
 go 1.13
 
-require github.com/phpdave11/gofpdi v1.0.14-0.20211212211723-1f10f9844311
+require (
+	github.com/andybalholm/brotli v1.0.6
+	github.com/phpdave11/gofpdi v1.0.14-0.20211212211723-1f10f9844311
+)
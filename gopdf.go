@@ -41,6 +41,10 @@ var ErrInvalidRectangleCoordinates = errors.New("Invalid coordinates for the rec
 
 var ErrInvalidRectangleRadius = errors.New("Radius length cannot exceed rectangle height or width")
 
+var ErrInvalidHorizontalScaling = errors.New("horizontal scaling must be greater than 0")
+
+var ErrInvalidPageNumber = errors.New("invalid page number")
+
 // GoPdf : A simple library for generating PDF written in Go lang
 type GoPdf struct {
 
@@ -103,6 +107,17 @@ type GoPdf struct {
 
 	//placeholder text
 	placeHolderTexts map[string]([]placeHolderTextInfo)
+
+	//table of contents
+	toc *tableOfContents
+
+	//page count aliases
+	aliasNbPages        string
+	aliasSectionNbPages string
+	sectionStarts       []int
+
+	//characters drawn without a glyph, see MissingGlyphs
+	missingGlyphs map[missingGlyphKey]int
 }
 
 type DrawableRectOptions struct {
@@ -648,85 +663,97 @@ func (gp *GoPdf) createTransparencyXObjectGroup(image *cacheContentImage, opts M
 }
 
 func (gp *GoPdf) imageByHolder(img ImageHolder, opts ImageOptions) error {
-	cacheImageIndex := -1
+	cacheImageIndex := gp.cachedImageIndex(img)
 
+	if cacheImageIndex == -1 { //new image
+		index, rect, err := gp.addImageObj(img, opts.Rect, opts.Mask != nil)
+		if err != nil {
+			return err
+		}
+		opts.Rect = rect
+		if gp.indexOfProcSet != -1 {
+			gp.getContent().AppendStreamImage(index, opts)
+		}
+	} else { //same img
+		if opts.Rect == nil {
+			opts.Rect = gp.curr.ImgCaches[cacheImageIndex].Rect
+		}
+
+		gp.getContent().AppendStreamImage(cacheImageIndex, opts)
+	}
+	return nil
+}
+
+// cachedImageIndex returns the index of the image object of img if it was already added, -1 otherwise.
+func (gp *GoPdf) cachedImageIndex(img ImageHolder) int {
 	for _, imgcache := range gp.curr.ImgCaches {
 		if img.ID() == imgcache.Path {
-			cacheImageIndex = imgcache.Index
-			break
+			return imgcache.Index
 		}
 	}
+	return -1
+}
 
-	if cacheImageIndex == -1 { //new image
-
-		//create img object
-		imgobj := new(ImageObj)
-		if opts.Mask != nil {
-			imgobj.SplittedMask = true
-		}
+// addImageObj adds the objects of a new image and returns the index of its image object and its rect,
+// the size of the image if rect is nil.
+func (gp *GoPdf) addImageObj(img ImageHolder, rect *Rect, splittedMask bool) (int, *Rect, error) {
+	//create img object
+	imgobj := new(ImageObj)
+	imgobj.SplittedMask = splittedMask
 
-		imgobj.init(func() *GoPdf {
-			return gp
-		})
-		imgobj.setProtection(gp.protection())
+	imgobj.init(func() *GoPdf {
+		return gp
+	})
+	imgobj.setProtection(gp.protection())
 
-		err := imgobj.SetImage(img)
-		if err != nil {
-			return err
-		}
+	err := imgobj.SetImage(img)
+	if err != nil {
+		return 0, nil, err
+	}
 
-		if opts.Rect == nil {
-			if opts.Rect, err = imgobj.getRect(); err != nil {
-				return err
-			}
+	if rect == nil {
+		if rect, err = imgobj.getRect(); err != nil {
+			return 0, nil, err
 		}
+	}
 
-		err = imgobj.parse()
+	err = imgobj.parse()
+	if err != nil {
+		return 0, nil, err
+	}
+	index := gp.addObj(imgobj)
+	if gp.indexOfProcSet != -1 {
+		//ยัดรูป
+		procset := gp.pdfObjs[gp.indexOfProcSet].(*ProcSetObj)
+		procset.RelateXobjs = append(procset.RelateXobjs, RelateXobject{IndexOfObj: index})
+		//เก็บข้อมูลรูปเอาไว้
+		var imgcache ImageCache
+		imgcache.Index = index
+		imgcache.Path = img.ID()
+		imgcache.Rect = rect
+		gp.curr.ImgCaches[index] = imgcache
+		gp.curr.CountOfImg++
+	}
+
+	if imgobj.haveSMask() {
+		smaskObj, err := imgobj.createSMask()
 		if err != nil {
-			return err
-		}
-		index := gp.addObj(imgobj)
-		if gp.indexOfProcSet != -1 {
-			//ยัดรูป
-			procset := gp.pdfObjs[gp.indexOfProcSet].(*ProcSetObj)
-			gp.getContent().AppendStreamImage(index, opts)
-			procset.RelateXobjs = append(procset.RelateXobjs, RelateXobject{IndexOfObj: index})
-			//เก็บข้อมูลรูปเอาไว้
-			var imgcache ImageCache
-			imgcache.Index = index
-			imgcache.Path = img.ID()
-			imgcache.Rect = opts.Rect
-			gp.curr.ImgCaches[index] = imgcache
-			gp.curr.CountOfImg++
-		}
-
-		if imgobj.haveSMask() {
-			smaskObj, err := imgobj.createSMask()
-			if err != nil {
-				return err
-			}
-			imgobj.imginfo.smarkObjID = gp.addObj(smaskObj)
+			return 0, nil, err
 		}
+		imgobj.imginfo.smarkObjID = gp.addObj(smaskObj)
+	}
 
-		if imgobj.isColspaceIndexed() {
-			dRGB, err := imgobj.createDeviceRGB()
-			if err != nil {
-				return err
-			}
-			dRGB.getRoot = func() *GoPdf {
-				return gp
-			}
-			imgobj.imginfo.deviceRGBObjID = gp.addObj(dRGB)
+	if imgobj.isColspaceIndexed() {
+		dRGB, err := imgobj.createDeviceRGB()
+		if err != nil {
+			return 0, nil, err
 		}
-
-	} else { //same img
-		if opts.Rect == nil {
-			opts.Rect = gp.curr.ImgCaches[cacheImageIndex].Rect
+		dRGB.getRoot = func() *GoPdf {
+			return gp
 		}
-
-		gp.getContent().AppendStreamImage(cacheImageIndex, opts)
+		imgobj.imginfo.deviceRGBObjID = gp.addObj(dRGB)
 	}
-	return nil
+	return index, rect, nil
 }
 
 // Image : draw image
@@ -949,9 +976,11 @@ func convertNumericToFloat64(size interface{}) (fontSize float64, err error) {
 	}
 }
 
-// SetFontWithStyle : set font style support Regular or Underline
-// for Bold|Italic should be loaded appropriate fonts with same styles defined
+// SetFontWithStyle : set font style support Regular, Underline, Strikethrough, Overline, Superscript or Subscript
+// for Bold|Italic should be loaded appropriate fonts with same styles defined,
+// or a font of the family with TtfOption.SyntheticStyles to draw them synthetically
 // size MUST be uint*, int* or float64*
+// The standard fonts Helvetica, Times, Courier, Symbol and ZapfDingbats can be set without being added, they are not embedded
 func (gp *GoPdf) SetFontWithStyle(family string, style int, size interface{}) error {
 	fontSize, err := convertNumericToFloat64(size)
 	if err != nil {
@@ -965,7 +994,7 @@ func (gp *GoPdf) SetFontWithStyle(family string, style int, size interface{}) er
 			obj := gp.pdfObjs[i]
 			sub, ok := obj.(*SubsetFontObj)
 			if ok {
-				if sub.GetFamily() == family && sub.GetTtfFontOption().Style == style&^Underline {
+				if sub.GetFamily() == family && sub.GetTtfFontOption().Style == style&^nonFontStyles {
 					gp.curr.FontSize = fontSize
 					gp.curr.FontStyle = style
 					gp.curr.FontFontCount = sub.CountOfFont
@@ -979,13 +1008,28 @@ func (gp *GoPdf) SetFontWithStyle(family string, style int, size interface{}) er
 	}
 
 	if !found {
-		return ErrMissingFontFamily
+		if sub := gp.syntheticStyleFont(family, style); sub != nil {
+			gp.curr.FontSize = fontSize
+			gp.curr.FontStyle = style
+			gp.curr.FontFontCount = sub.CountOfFont
+			gp.curr.FontISubset = sub
+			return nil
+		}
+		// fall back on the standard fonts, which do not need to be added
+		sub, err := gp.addStandardFont(family, style)
+		if err != nil {
+			return err
+		}
+		gp.curr.FontSize = fontSize
+		gp.curr.FontStyle = style
+		gp.curr.FontFontCount = sub.CountOfFont
+		gp.curr.FontISubset = sub
 	}
 
 	return nil
 }
 
-// SetFont : set font style support "" or "U"
+// SetFont : set font style support "", "U" (underline), "S" (strikethrough) or "O" (overline)
 // for "B" and "I" should be loaded appropriate fonts with same styles defined
 // size MUST be uint*, int* or float64*
 func (gp *GoPdf) SetFont(family string, style string, size interface{}) error {
@@ -1006,6 +1050,29 @@ func (gp *GoPdf) SetCharSpacing(charSpacing float64) error {
 	return nil
 }
 
+// SetWordSpacing : set the extra space added to each space character of the currently active font
+func (gp *GoPdf) SetWordSpacing(wordSpacing float64) error {
+	gp.UnitsToPointsVar(&wordSpacing)
+	gp.curr.wordSpacing = wordSpacing
+	return nil
+}
+
+// SetTextRise : set the distance to move the baseline of the text up (positive) or down (negative),
+// it is added to the shift of the Superscript and Subscript styles
+func (gp *GoPdf) SetTextRise(rise float64) {
+	gp.UnitsToPointsVar(&rise)
+	gp.curr.textRise = rise
+}
+
+// SetHorizontalScaling : set the horizontal scaling of text in percent (100 is the normal width)
+func (gp *GoPdf) SetHorizontalScaling(percent float64) error {
+	if percent <= 0 {
+		return ErrInvalidHorizontalScaling
+	}
+	gp.curr.horizontalScaling = percent
+	return nil
+}
+
 // WritePdf : write pdf file
 func (gp *GoPdf) WritePdf(pdfPath string) error {
 	return os.WriteFile(pdfPath, gp.GetBytesPdf(), 0644)
@@ -1041,6 +1108,12 @@ func (gp *GoPdf) Close() error {
 }
 
 func (gp *GoPdf) compilePdf(w io.Writer) (n int64, err error) {
+	if err := gp.fillTOC(); err != nil {
+		return 0, err
+	}
+	if err := gp.replacePageAliases(); err != nil {
+		return 0, err
+	}
 	gp.prepare()
 	err = gp.Close()
 	if err != nil {
@@ -1104,7 +1177,7 @@ func (gp *GoPdf) GetBytesPdf() []byte {
 // Text write text start at current x,y ( current y is the baseline of text )
 func (gp *GoPdf) Text(text string) error {
 
-	text, err := gp.curr.FontISubset.AddChars(text)
+	text, err := gp.addChars(gp.curr.FontISubset, text, gp.indexOfContent)
 	if err != nil {
 		return err
 	}
@@ -1119,6 +1192,10 @@ func (gp *GoPdf) Text(text string) error {
 
 // CellWithOption create cell of text ( use current x,y is upper-left corner of cell)
 func (gp *GoPdf) CellWithOption(rectangle *Rect, text string, opt CellOption) error {
+	if gp.hasTabStops(text) {
+		return gp.cellWithTabs(rectangle, text, opt)
+	}
+
 	transparency, err := gp.getCachedTransparency(opt.Transparency)
 	if err != nil {
 		return err
@@ -1129,7 +1206,7 @@ func (gp *GoPdf) CellWithOption(rectangle *Rect, text string, opt CellOption) er
 	}
 
 	rectangle = rectangle.UnitsToPoints(gp.config.Unit)
-	text, err = gp.curr.FontISubset.AddChars(text)
+	text, err = gp.addChars(gp.curr.FontISubset, text, gp.indexOfContent)
 	if err != nil {
 		return err
 	}
@@ -1143,14 +1220,18 @@ func (gp *GoPdf) CellWithOption(rectangle *Rect, text string, opt CellOption) er
 // Cell : create cell of text ( use current x,y is upper-left corner of cell)
 // Note that this has no effect on Rect.H pdf (now). Fix later :-)
 func (gp *GoPdf) Cell(rectangle *Rect, text string) error {
-	rectangle = rectangle.UnitsToPoints(gp.config.Unit)
 	defaultopt := CellOption{
 		Align:  Left | Top,
 		Border: 0,
 		Float:  Right,
 	}
+	if gp.hasTabStops(text) {
+		return gp.cellWithTabs(rectangle, text, defaultopt)
+	}
 
-	text, err := gp.curr.FontISubset.AddChars(text)
+	rectangle = rectangle.UnitsToPoints(gp.config.Unit)
+
+	text, err := gp.addChars(gp.curr.FontISubset, text, gp.indexOfContent)
 	if err != nil {
 		return err
 	}
@@ -1170,11 +1251,11 @@ func (gp *GoPdf) MultiCell(rectangle *Rect, text string) error {
 	length := len([]rune(text))
 
 	// get lineHeight
-	text, err := gp.curr.FontISubset.AddChars(text)
+	text, err := gp.addChars(gp.curr.FontISubset, text, gp.indexOfContent)
 	if err != nil {
 		return err
 	}
-	_, lineHeight, _, err := createContent(gp.curr.FontISubset, text, gp.curr.FontSize, gp.curr.CharSpacing, nil)
+	_, lineHeight, _, err := createContentWithState(gp.curr.FontISubset, text, gp.curr.FontSize, gp.currTextState(), nil)
 	if err != nil {
 		return err
 	}
@@ -1185,7 +1266,7 @@ func (gp *GoPdf) MultiCell(rectangle *Rect, text string) error {
 			break
 		}
 		lineWidth, _ := gp.MeasureTextWidth(string(line))
-		runeWidth, _ := gp.MeasureTextWidth(string(v))
+		runeWidth, _ := gp.runeAdvance(line, lineWidth, v)
 
 		if lineWidth+runeWidth > rectangle.W {
 			gp.Cell(&Rect{W: rectangle.W, H: lineHeight}, string(line))
@@ -1217,7 +1298,7 @@ func (gp *GoPdf) IsFitMultiCell(rectangle *Rect, text string) (bool, float64, er
 	if err != nil {
 		return false, totalLineHeight, err
 	}
-	_, lineHeight, _, err := createContent(gp.curr.FontISubset, text, gp.curr.FontSize, gp.curr.CharSpacing, nil)
+	_, lineHeight, _, err := createContentWithState(gp.curr.FontISubset, text, gp.curr.FontSize, gp.currTextState(), nil)
 
 	if err != nil {
 		return false, totalLineHeight, err
@@ -1229,7 +1310,7 @@ func (gp *GoPdf) IsFitMultiCell(rectangle *Rect, text string) (bool, float64, er
 			return false, totalLineHeight, nil
 		}
 		lineWidth, _ := gp.MeasureTextWidth(string(line))
-		runeWidth, _ := gp.MeasureTextWidth(string(v))
+		runeWidth, _ := gp.runeAdvance(line, lineWidth, v)
 
 		if lineWidth+runeWidth > rectangle.W {
 			totalLineHeight += lineHeight
@@ -1251,6 +1332,29 @@ func (gp *GoPdf) IsFitMultiCell(rectangle *Rect, text string) (bool, float64, er
 	return ok, totalLineHeight, nil
 }
 
+// IsFitMultiCellWithOption : check whether the rectangle's area is big enough for the text,
+// breaking the lines like MultiCellWithOption with the same BreakOption
+func (gp *GoPdf) IsFitMultiCellWithOption(rectangle *Rect, text string, opt *BreakOption) (bool, float64, error) {
+	itext, err := gp.curr.FontISubset.AddChars(text)
+	if err != nil {
+		return false, 0, err
+	}
+	_, lineHeight, _, err := createContentWithState(gp.curr.FontISubset, itext, gp.curr.FontSize, gp.currTextState(), nil)
+	if err != nil {
+		return false, 0, err
+	}
+	gp.PointsToUnitsVar(&lineHeight)
+
+	lines, err := gp.SplitTextWithOption(text, rectangle.W, opt)
+	if err == ErrEmptyString {
+		return true, 0, nil
+	} else if err != nil {
+		return false, 0, err
+	}
+	totalLineHeight := float64(len(lines)) * lineHeight
+	return totalLineHeight <= rectangle.H, totalLineHeight, nil
+}
+
 // IsFitMultiCellWithNewline : similar to IsFitMultiCell, but process char newline as Br
 func (gp *GoPdf) IsFitMultiCellWithNewline(rectangle *Rect, text string) (bool, float64, error) {
 	r := *rectangle
@@ -1285,17 +1389,28 @@ func (gp *GoPdf) MultiCellWithOption(rectangle *Rect, text string, opt CellOptio
 	x := gp.GetX()
 
 	// get lineHeight
+	// the lines report their missing glyphs when drawn
 	itext, err := gp.curr.FontISubset.AddChars(text)
 	if err != nil {
 		return err
 	}
-	_, lineHeight, _, err := createContent(gp.curr.FontISubset, itext, gp.curr.FontSize, gp.curr.CharSpacing, nil)
+	if err := gp.missingGlyphsError(gp.curr.FontISubset); err != nil {
+		return err
+	}
+	_, lineHeight, _, err := createContentWithState(gp.curr.FontISubset, itext, gp.curr.FontSize, gp.currTextState(), nil)
 	if err != nil {
 		return err
 	}
 	gp.PointsToUnitsVar(&lineHeight)
 
-	textSplits, err := gp.SplitTextWithOption(text, rectangle.W, opt.BreakOption)
+	var textSplits []string
+	var paragraphEnds []bool
+	justify := opt.Align&Justify == Justify
+	if justify {
+		textSplits, paragraphEnds, err = gp.splitParagraphs(text, rectangle.W, opt.BreakOption)
+	} else {
+		textSplits, err = gp.SplitTextWithOption(text, rectangle.W, opt.BreakOption)
+	}
 	if err != nil {
 		return err
 	}
@@ -1309,8 +1424,12 @@ func (gp *GoPdf) MultiCellWithOption(rectangle *Rect, text string, opt CellOptio
 		startHeight = rectangle.H - (lineHeight+1.5)*float64(shiftLines)
 	}
 
-	for _, text := range textSplits {
-		gp.CellWithOption(&Rect{W: rectangle.W, H: startHeight}, string(text), opt)
+	for i, text := range textSplits {
+		if justify && !paragraphEnds[i] {
+			gp.justifiedCellWithOption(&Rect{W: rectangle.W, H: startHeight}, text, opt)
+		} else {
+			gp.CellWithOption(&Rect{W: rectangle.W, H: startHeight}, string(text), opt)
+		}
 		gp.Br(lineHeight)
 		gp.SetX(x)
 	}
@@ -1318,6 +1437,51 @@ func (gp *GoPdf) MultiCellWithOption(rectangle *Rect, text string, opt CellOptio
 	return nil
 }
 
+// splitParagraphs splits text like SplitTextWithOption, and also reports which lines end a paragraph.
+func (gp *GoPdf) splitParagraphs(text string, width float64, opt *BreakOption) ([]string, []bool, error) {
+	var lines []string
+	var paragraphEnds []bool
+	for _, paragraph := range strings.Split(text, "\n") {
+		splits, err := gp.SplitTextWithOption(paragraph, width, opt)
+		if err == ErrEmptyString {
+			splits = []string{""}
+		} else if err != nil {
+			return nil, nil, err
+		}
+		for i, line := range splits {
+			lines = append(lines, line)
+			paragraphEnds = append(paragraphEnds, i == len(splits)-1)
+		}
+	}
+	return lines, paragraphEnds, nil
+}
+
+// justifiedCellWithOption draws a line stretched to the width of rectangle, widening the spaces,
+// or every character if the line has no spaces.
+func (gp *GoPdf) justifiedCellWithOption(rectangle *Rect, text string, opt CellOption) error {
+	text = strings.TrimRight(text, " ")
+	opt.Align = opt.Align&^(Justify|Center|Right) | Left
+
+	textWidth, err := gp.MeasureTextWidth(text)
+	if err != nil {
+		return err
+	}
+	extra := gp.UnitsToPoints(rectangle.W-textWidth) / gp.currTextState().scale()
+
+	charSpacing, wordSpacing := gp.curr.CharSpacing, gp.curr.wordSpacing
+	defer func() {
+		gp.curr.CharSpacing, gp.curr.wordSpacing = charSpacing, wordSpacing
+	}()
+	if extra > 0 {
+		if spaces := strings.Count(text, " "); spaces > 0 {
+			gp.curr.wordSpacing += extra / float64(spaces)
+		} else if count := len([]rune(text)); count > 1 {
+			gp.curr.CharSpacing += extra / float64(count-1)
+		}
+	}
+	return gp.CellWithOption(rectangle, text, opt)
+}
+
 // SplitText splits text into multiple lines based on width performing potential mid-word breaks.
 func (gp *GoPdf) SplitText(text string, width float64) ([]string, error) {
 	return gp.SplitTextWithOption(text, width, &DefaultBreakOption)
@@ -1339,6 +1503,19 @@ func (gp *GoPdf) SplitTextWithOption(text string, width float64, opt *BreakOptio
 	if opt == nil {
 		opt = &DefaultBreakOption
 	}
+	switch opt.Mode {
+	case BreakModeHyphenation:
+		utf8Texts := []rune(text)
+		return gp.splitTextAtBreaks(utf8Texts, hyphenationBreaks(utf8Texts, opt.Hyphenator), width, opt.hyphenSeparator())
+	case BreakModeUnicode:
+		utf8Texts := []rune(text)
+		return gp.splitTextAtBreaks(utf8Texts, unicodeLineBreaks(utf8Texts), width, opt.hyphenSeparator())
+	case BreakModeWordSegmentation:
+		utf8Texts := []rune(text)
+		return gp.splitTextAtBreaks(utf8Texts, wordSegmentationBreaks(utf8Texts, opt.Dictionary), width, opt.hyphenSeparator())
+	case BreakModeCJK:
+		return gp.splitTextCJK(text, width, opt)
+	}
 	var lineText []rune
 	var lineTexts []string
 	utf8Texts := []rune(text)
@@ -1357,7 +1534,7 @@ func (gp *GoPdf) SplitTextWithOption(text string, width float64, opt *BreakOptio
 		if err != nil {
 			return nil, err
 		}
-		runeWidth, err := gp.MeasureTextWidth(string(utf8Texts[i]))
+		runeWidth, err := gp.runeAdvance(lineText, lineWidth, utf8Texts[i])
 		if err != nil {
 			return nil, err
 		}
@@ -1450,7 +1627,9 @@ func (gp *GoPdf) FillInPlaceHoldText(placeHolderName string, text string, align
 		if !ok {
 			return errors.New("listCache.caches is not *cacheContentText")
 		}
-		info.fontISubset.AddChars(text)
+		if _, err := gp.addChars(info.fontISubset, text, info.indexOfContent); err != nil {
+			return err
+		}
 		contentText.text = text
 
 		//Calculate position
@@ -1736,6 +1915,11 @@ func (gp *GoPdf) AddTTFFontData(family string, fontData []byte) error {
 
 // AddTTFFontDataWithOption adds font data with option.
 func (gp *GoPdf) AddTTFFontDataWithOption(family string, fontData []byte, option TtfOption) error {
+	if gp.config.FontCache != nil {
+		return gp.addCachedTTFFont(family, fontDataKey(fontData), func() ([]byte, error) {
+			return fontData, nil
+		}, option)
+	}
 	subsetFont := new(SubsetFontObj)
 	subsetFont.init(func() *GoPdf {
 		return gp
@@ -1757,6 +1941,13 @@ func (gp *GoPdf) AddTTFFontByReader(family string, rd io.Reader) error {
 
 // AddTTFFontByReaderWithOption adds font file by reader with option.
 func (gp *GoPdf) AddTTFFontByReaderWithOption(family string, rd io.Reader, option TtfOption) error {
+	if gp.config.FontCache != nil {
+		data, err := io.ReadAll(rd)
+		if err != nil {
+			return err
+		}
+		return gp.AddTTFFontDataWithOption(family, data, option)
+	}
 	subsetFont := new(SubsetFontObj)
 	subsetFont.init(func() *GoPdf {
 		return gp
@@ -1812,8 +2003,8 @@ func (gp *GoPdf) setSubsetFontObject(subsetFont *SubsetFontObj, family string, o
 
 	if gp.indexOfProcSet != -1 {
 		procset := gp.pdfObjs[gp.indexOfProcSet].(*ProcSetObj)
-		if !procset.Relates.IsContainsFamilyAndStyle(family, option.Style&^Underline) {
-			procset.Relates = append(procset.Relates, RelateFont{Family: family, IndexOfObj: index, CountOfFont: gp.curr.CountOfFont, Style: option.Style &^ Underline})
+		if !procset.Relates.IsContainsFamilyAndStyle(family, option.Style&^nonFontStyles) {
+			procset.Relates = append(procset.Relates, RelateFont{Family: family, IndexOfObj: index, CountOfFont: gp.curr.CountOfFont, Style: option.Style &^ nonFontStyles})
 			subsetFont.CountOfFont = gp.curr.CountOfFont
 			gp.curr.CountOfFont++
 		}
@@ -1824,9 +2015,15 @@ func (gp *GoPdf) setSubsetFontObject(subsetFont *SubsetFontObj, family string, o
 // AddTTFFontWithOption : add font file
 func (gp *GoPdf) AddTTFFontWithOption(family string, ttfpath string, option TtfOption) error {
 
-	if _, err := os.Stat(ttfpath); os.IsNotExist(err) {
+	info, err := os.Stat(ttfpath)
+	if os.IsNotExist(err) {
 		return err
 	}
+	if gp.config.FontCache != nil && err == nil {
+		return gp.addCachedTTFFont(family, fontFileKey(ttfpath, info), func() ([]byte, error) {
+			return os.ReadFile(ttfpath)
+		}, option)
+	}
 	data, err := os.ReadFile(ttfpath)
 	if err != nil {
 		return err
@@ -1904,19 +2101,35 @@ func (gp *GoPdf) SetFillColorCMYK(c, m, y, k uint8) {
 
 // MeasureTextWidth : measure Width of text (use current font)
 func (gp *GoPdf) MeasureTextWidth(text string) (float64, error) {
+	if gp.hasTabStops(text) {
+		_, width, err := gp.layoutTabs(text)
+		return width, err
+	}
 
 	text, err := gp.curr.FontISubset.AddChars(text) //AddChars for create CharacterToGlyphIndex
 	if err != nil {
 		return 0, err
 	}
 
-	_, _, textWidthPdfUnit, err := createContent(gp.curr.FontISubset, text, gp.curr.FontSize, gp.curr.CharSpacing, nil)
+	_, _, textWidthPdfUnit, err := createContentWithState(gp.curr.FontISubset, text, gp.curr.FontSize, gp.currTextState(), nil)
 	if err != nil {
 		return 0, err
 	}
 	return pointsToUnits(gp.config, textWidthPdfUnit), nil
 }
 
+func (gp *GoPdf) currTextState() textState {
+	sizeScale, rise := scriptSizeAndRise(gp.curr.FontISubset, gp.curr.FontSize, gp.curr.FontStyle)
+	state := textState{
+		charSpacing:       gp.curr.CharSpacing,
+		wordSpacing:       gp.curr.wordSpacing,
+		horizontalScaling: gp.curr.horizontalScaling,
+		sizeScale:         sizeScale,
+		rise:              rise + gp.curr.textRise,
+	}
+	return state.withSyntheticStyles(gp.curr.FontISubset, gp.curr.FontSize, gp.curr.FontStyle)
+}
+
 // MeasureCellHeightByText : measure Height of cell by text (use current font)
 func (gp *GoPdf) MeasureCellHeightByText(text string) (float64, error) {
 
@@ -1925,7 +2138,7 @@ func (gp *GoPdf) MeasureCellHeightByText(text string) (float64, error) {
 		return 0, err
 	}
 
-	_, cellHeightPdfUnit, _, err := createContent(gp.curr.FontISubset, text, gp.curr.FontSize, gp.curr.CharSpacing, nil)
+	_, cellHeightPdfUnit, _, err := createContentWithState(gp.curr.FontISubset, text, gp.curr.FontSize, gp.currTextState(), nil)
 	if err != nil {
 		return 0, err
 	}
@@ -2157,6 +2370,11 @@ func (gp *GoPdf) init(importer ...*gofpdi.Importer) {
 	gp.curr.extGStatesMap = NewExtGStatesMap()
 	gp.curr.transparencyMap = NewTransparencyMap()
 	gp.anchors = make(map[string]anchorOption)
+	gp.toc = nil
+	gp.aliasNbPages = ""
+	gp.aliasSectionNbPages = ""
+	gp.sectionStarts = nil
+	gp.missingGlyphs = nil
 	gp.curr.txtColorMode = "gray"
 
 	//init index
@@ -2255,8 +2473,12 @@ func (gp *GoPdf) prepare() {
 				pagesObj.PageCount++
 				indexCurrPage = i
 			case "Content":
-				if indexCurrPage != -1 {
-					gp.pdfObjs[indexCurrPage].(*PageObj).Contents = fmt.Sprintf("%s %d 0 R ", gp.pdfObjs[indexCurrPage].(*PageObj).Contents, i+1)
+				indexOfPage := indexCurrPage
+				if content := gp.pdfObjs[i].(*ContentObj); content.indexOfPageObj != -1 {
+					indexOfPage = content.indexOfPageObj
+				}
+				if indexOfPage != -1 {
+					gp.pdfObjs[indexOfPage].(*PageObj).Contents = fmt.Sprintf("%s %d 0 R ", gp.pdfObjs[indexOfPage].(*PageObj).Contents, i+1)
 				}
 			case "Font":
 				tmpfont := gp.pdfObjs[i].(*FontObj)
@@ -2365,6 +2587,7 @@ func (gp *GoPdf) getContent() *ContentObj {
 		content.init(func() *GoPdf {
 			return gp
 		})
+		content.indexOfPageObj = gp.curr.IndexOfPageObj
 		gp.indexOfContent = gp.addObj(content)
 	} else {
 		content = gp.pdfObjs[gp.indexOfContent].(*ContentObj)
@@ -2474,21 +2697,30 @@ func (gp *GoPdf) IsCurrFontContainGlyph(r rune) (bool, error) {
 	return true, nil
 }
 
-// SetPage set current page
+// SetPage set current page, creating its content if nothing was drawn on it yet
 func (gp *GoPdf) SetPage(pageno int) error {
-	var pageIndex int
-	for i := 0; i < len(gp.pdfObjs); i++ {
-		switch gp.pdfObjs[i].(type) {
-		case *ContentObj:
-			pageIndex += 1
-			if pageIndex == pageno {
-				gp.indexOfContent = i
-				return nil
-			}
+	indexOfPageObj := -1
+	for index, number := range gp.pageNumbers() {
+		if number == pageno {
+			indexOfPageObj = index
+			break
 		}
 	}
+	if indexOfPageObj == -1 {
+		return ErrInvalidPageNumber
+	}
 
-	return errors.New("invalid page number")
+	gp.curr.IndexOfPageObj = indexOfPageObj
+	gp.curr.pageSize = gp.pageSizeOf(indexOfPageObj)
+	gp.indexOfContent = -1
+	for i, obj := range gp.pdfObjs {
+		if content, ok := obj.(*ContentObj); ok && content.indexOfPageObj == indexOfPageObj {
+			gp.indexOfContent = i
+			break
+		}
+	}
+	gp.getContent()
+	return nil
 }
 
 func (gp *GoPdf) SetColorSpace(name string) error {
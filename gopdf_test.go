@@ -5,7 +5,10 @@ import (
 	"errors"
 	"io"
 	"log"
+	"math"
 	"os"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -906,3 +909,132 @@ func TestImportPagesFromFile(t *testing.T) {
 		return
 	}
 }
+
+func TestWordSpacingAndHorizontalScaling(t *testing.T) {
+	pdf := setupDefaultA4PDF(t)
+	pdf.AddPage()
+
+	width, err := pdf.MeasureTextWidth("a b c")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pdf.SetWordSpacing(3)
+	spaced, err := pdf.MeasureTextWidth("a b c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(spaced-width-6) > 0.001 {
+		t.Fatalf("expected two spaces of 3 to add 6, got %f", spaced-width)
+	}
+	pdf.SetWordSpacing(0)
+
+	if err := pdf.SetHorizontalScaling(0); err != ErrInvalidHorizontalScaling {
+		t.Fatalf("expected ErrInvalidHorizontalScaling, got %v", err)
+	}
+	pdf.SetHorizontalScaling(50)
+	scaled, err := pdf.MeasureTextWidth("a b c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(scaled-width/2) > 0.001 {
+		t.Fatalf("expected half width %f, got %f", width/2, scaled)
+	}
+	pdf.SetHorizontalScaling(100)
+}
+
+func TestMultiCellJustify(t *testing.T) {
+	err := initTesting()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pdf := setupDefaultA4PDF(t)
+	pdf.SetCompressLevel(0)
+	pdf.AddPage()
+
+	text := "Lorem ipsum dolor sit amet, consetetur sadipscing elitr, sed diam nonumy eirmod tempor.\nLast paragraph"
+	opt := CellOption{
+		Align: Justify | Top,
+		BreakOption: &BreakOption{
+			Mode:           BreakModeIndicatorSensitive,
+			BreakIndicator: ' ',
+		},
+	}
+	pdf.SetXY(50, 50)
+	err = pdf.MultiCellWithOption(&Rect{W: 150, H: 400}, text, opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pdf.curr.wordSpacing != 0 || pdf.curr.CharSpacing != 0 {
+		t.Fatalf("spacing was not restored: %f %f", pdf.curr.wordSpacing, pdf.curr.CharSpacing)
+	}
+
+	var buff bytes.Buffer
+	content := pdf.pdfObjs[pdf.indexOfContent].(*ContentObj)
+	if err := content.listCache.write(&buff, nil); err != nil {
+		t.Fatal(err)
+	}
+	stream := buff.String()
+	if !strings.Contains(stream, "0003>-") {
+		t.Fatalf("expected word spacing offsets after spaces in %q", stream)
+	}
+
+	lines := strings.Count(stream, "TJ")
+	justified := 0
+	for _, l := range strings.Split(stream, "TJ") {
+		if strings.Contains(l, "0003>-") {
+			justified++
+		}
+	}
+	// the last line of each of the two paragraphs is not justified
+	if justified != lines-2 {
+		t.Fatalf("expected %d justified lines, got %d", lines-2, justified)
+	}
+
+	err = pdf.WritePdf("./test/out/multicell_justify.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSetPageBlankPages(t *testing.T) {
+	err := initTesting()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf := setupDefaultA4PDF(t)
+	err = pdf.SetFont("LiberationSerif-Regular", "", 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf.AddPage()
+	pdf.AddPageWithOption(PageOption{PageSize: PageSizeA4Landscape})
+	pdf.AddPage()
+
+	for _, page := range []int{2, 1} {
+		err = pdf.SetPage(page)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = pdf.Cell(nil, "page "+strconv.Itoa(page))
+		if err != nil {
+			t.Fatal(err)
+		}
+		content := pdf.pdfObjs[pdf.indexOfContent].(*ContentObj)
+		if number := pdf.pageNumbers()[content.indexOfPageObj]; number != page {
+			t.Fatalf("expected the text on page %d, got page %d", page, number)
+		}
+	}
+	if pdf.curr.pageSize.H != PageSizeA4.H {
+		t.Fatalf("expected the size of page 1, got height %f", pdf.curr.pageSize.H)
+	}
+	if err := pdf.SetPage(4); err != ErrInvalidPageNumber {
+		t.Fatalf("expected ErrInvalidPageNumber, got %v", err)
+	}
+
+	err = pdf.WritePdf("./test/out/set_page_blank_pages.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
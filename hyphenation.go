@@ -0,0 +1,285 @@
+package gopdf
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidHyphenationPattern occurs when a hyphenation pattern cannot be parsed.
+var ErrInvalidHyphenationPattern = errors.New("invalid hyphenation pattern")
+
+// Hyphenator finds the positions where words can be hyphenated, using Liang's algorithm
+// with the patterns of one language (as in the hyph-*.tex files distributed with TeX).
+type Hyphenator struct {
+	Language string
+	LeftMin  int // minimum number of characters before a hyphen
+	RightMin int // minimum number of characters after a hyphen
+
+	patterns         map[string][]int
+	exceptions       map[string][]int
+	maxPatternLength int
+}
+
+// NewHyphenator creates a Hyphenator without patterns, which only hyphenates its exceptions.
+// LeftMin and RightMin default to 2 and 3 like in TeX.
+func NewHyphenator(language string) *Hyphenator {
+	return &Hyphenator{
+		Language:   language,
+		LeftMin:    2,
+		RightMin:   3,
+		patterns:   make(map[string][]int),
+		exceptions: make(map[string][]int),
+	}
+}
+
+// LoadHyphenator creates a Hyphenator from a TeX pattern file.
+// The \patterns{...} and \hyphenation{...} groups are read, comments and other commands are ignored.
+// A file without any group is read as a list of patterns separated by white space.
+//
+//	Usage:
+//	f, _ := os.Open("hyph-en-us.tex")
+//	h, err := gopdf.LoadHyphenator("en-us", f)
+//	pdf.SplitTextWithOption(text, 100, &gopdf.BreakOption{Mode: gopdf.BreakModeHyphenation, Hyphenator: h})
+func LoadHyphenator(language string, patterns io.Reader) (*Hyphenator, error) {
+	data, err := io.ReadAll(patterns)
+	if err != nil {
+		return nil, err
+	}
+	src := decodeTeXChars(stripTeXComments(string(data)))
+
+	h := NewHyphenator(language)
+	groups := 0
+	for _, group := range []string{`\patterns`, `\hyphenation`} {
+		body, ok := texGroup(src, group)
+		if !ok {
+			continue
+		}
+		groups++
+		for _, token := range strings.Fields(body) {
+			if strings.HasPrefix(token, `\`) {
+				continue
+			}
+			if group == `\patterns` {
+				err = h.addPattern(token)
+			} else {
+				h.AddExceptions(token)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if groups == 0 {
+		if err := h.AddPatterns(strings.Fields(src)...); err != nil {
+			return nil, err
+		}
+	}
+	return h, nil
+}
+
+// AddPatterns adds Liang patterns such as "hy3ph" or ".ach4".
+func (h *Hyphenator) AddPatterns(patterns ...string) error {
+	for _, p := range patterns {
+		if err := h.addPattern(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *Hyphenator) addPattern(pattern string) error {
+	var letters []rune
+	values := []int{0}
+	for _, r := range strings.ToLower(pattern) {
+		if r >= '0' && r <= '9' {
+			values[len(values)-1] = int(r - '0')
+			continue
+		}
+		letters = append(letters, r)
+		values = append(values, 0)
+	}
+	if len(letters) == 0 {
+		return ErrInvalidHyphenationPattern
+	}
+	h.patterns[string(letters)] = values
+	if len(letters) > h.maxPatternLength {
+		h.maxPatternLength = len(letters)
+	}
+	return nil
+}
+
+// AddExceptions adds words with their hyphens written out, such as "as-so-ciate".
+// These words are hyphenated only where the hyphens are.
+func (h *Hyphenator) AddExceptions(words ...string) {
+	for _, word := range words {
+		var letters []rune
+		var positions []int
+		for _, r := range strings.ToLower(word) {
+			if r == '-' {
+				positions = append(positions, len(letters))
+				continue
+			}
+			letters = append(letters, r)
+		}
+		h.exceptions[string(letters)] = positions
+	}
+}
+
+// Hyphenate returns the rune offsets in word before which a hyphen may be inserted.
+func (h *Hyphenator) Hyphenate(word string) []int {
+	letters := []rune(strings.ToLower(word))
+	if len(letters) < h.LeftMin+h.RightMin {
+		return nil
+	}
+
+	var positions []int
+	if exception, ok := h.exceptions[string(letters)]; ok {
+		positions = exception
+	} else {
+		positions = h.hyphenatePatterns(letters)
+	}
+
+	var result []int
+	for _, p := range positions {
+		if p >= h.LeftMin && p <= len(letters)-h.RightMin {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+func (h *Hyphenator) hyphenatePatterns(letters []rune) []int {
+	// the word is surrounded by dots which match the word boundaries in patterns
+	word := make([]rune, 0, len(letters)+2)
+	word = append(word, '.')
+	word = append(word, letters...)
+	word = append(word, '.')
+
+	points := make([]int, len(word)+1)
+	for i := 0; i < len(word); i++ {
+		for j := i + 1; j <= len(word) && j-i <= h.maxPatternLength; j++ {
+			values, ok := h.patterns[string(word[i:j])]
+			if !ok {
+				continue
+			}
+			for k, v := range values {
+				if v > points[i+k] {
+					points[i+k] = v
+				}
+			}
+		}
+	}
+
+	var positions []int
+	for i := 1; i < len(letters); i++ {
+		// points[i+1] lies between letters[i-1] and letters[i], offset by the leading dot
+		if points[i+1]%2 == 1 {
+			positions = append(positions, i)
+		}
+	}
+	return positions
+}
+
+// hyphenationBreaks returns the breaks after spaces, after hyphens, at soft hyphens and inside words.
+func hyphenationBreaks(text []rune, h *Hyphenator) []lineBreak {
+	var breaks []lineBreak
+	wordStart := -1
+	hasSoftHyphen := false
+	endWord := func(end int) {
+		if wordStart < 0 {
+			return
+		}
+		if h != nil && !hasSoftHyphen {
+			for _, p := range h.Hyphenate(string(text[wordStart:end])) {
+				breaks = append(breaks, lineBreak{index: wordStart + p, hyphen: true})
+			}
+		}
+		wordStart = -1
+		hasSoftHyphen = false
+	}
+
+	for i, r := range text {
+		switch {
+		case r == '\n':
+			endWord(i)
+			breaks = append(breaks, lineBreak{index: i + 1, mandatory: true})
+		case isTrailingSpace(r):
+			endWord(i)
+			if i+1 < len(text) && !isTrailingSpace(text[i+1]) {
+				breaks = append(breaks, lineBreak{index: i + 1})
+			}
+		case r == softHyphen:
+			hasSoftHyphen = true
+			breaks = append(breaks, lineBreak{index: i + 1, hyphen: true})
+		case r == '-' || r == '\u2010':
+			endWord(i)
+			if i > 0 && unicode.IsLetter(text[i-1]) && i+1 < len(text) && unicode.IsLetter(text[i+1]) {
+				breaks = append(breaks, lineBreak{index: i + 1})
+			}
+		case unicode.IsLetter(r) || unicode.IsMark(r):
+			if wordStart < 0 {
+				wordStart = i
+			}
+		default:
+			endWord(i)
+		}
+	}
+	endWord(len(text))
+	sortLineBreaks(breaks)
+	return breaks
+}
+
+// stripTeXComments removes everything from an unescaped % to the end of the line.
+func stripTeXComments(src string) string {
+	var sb strings.Builder
+	for _, line := range strings.Split(src, "\n") {
+		for i := 0; i < len(line); i++ {
+			if line[i] == '%' && (i == 0 || line[i-1] != '\\') {
+				line = line[:i]
+				break
+			}
+		}
+		sb.WriteString(line)
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// decodeTeXChars decodes the ^^xx notation used in old pattern files for the Latin-1 characters.
+func decodeTeXChars(src string) string {
+	if !strings.Contains(src, "^^") {
+		return src
+	}
+	var out strings.Builder
+	for i := 0; i < len(src); i++ {
+		if i+3 < len(src) && src[i] == '^' && src[i+1] == '^' {
+			if v, err := strconv.ParseUint(src[i+2:i+4], 16, 8); err == nil {
+				out.WriteRune(rune(v))
+				i += 3
+				continue
+			}
+		}
+		out.WriteByte(src[i])
+	}
+	return out.String()
+}
+
+// texGroup returns the content between the braces following command.
+func texGroup(src string, command string) (string, bool) {
+	i := strings.Index(src, command+"{")
+	if i < 0 {
+		i = strings.Index(src, command+" {")
+		if i < 0 {
+			return "", false
+		}
+	}
+	start := strings.IndexByte(src[i:], '{') + i + 1
+	end := strings.IndexByte(src[start:], '}')
+	if end < 0 {
+		return src[start:], true
+	}
+	return src[start : start+end], true
+}
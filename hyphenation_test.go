@@ -0,0 +1,82 @@
+package gopdf
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testHyphenator(t *testing.T) *Hyphenator {
+	h, err := LoadHyphenator("en", strings.NewReader(`% patterns from Liang's thesis
+\patterns{ hy3ph he2n hena4 hen5at 1na n2at 1tio 2io o2n }
+\hyphenation{ ta-ble }`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func TestHyphenate(t *testing.T) {
+	h := testHyphenator(t)
+	if got := h.Hyphenate("Hyphenation"); !reflect.DeepEqual(got, []int{2, 6}) {
+		t.Fatalf("expected hy-phen-ation, got %v", got)
+	}
+	if got := h.Hyphenate("table"); !reflect.DeepEqual(got, []int{2}) {
+		t.Fatalf("expected the exception ta-ble, got %v", got)
+	}
+	if got := h.Hyphenate("hyp"); got != nil {
+		t.Fatalf("expected no hyphenation for a short word, got %v", got)
+	}
+	if _, err := LoadHyphenator("en", strings.NewReader("a1b 12")); err != ErrInvalidHyphenationPattern {
+		t.Fatalf("expected ErrInvalidHyphenationPattern, got %v", err)
+	}
+
+	if got := decodeTeXChars("^^e9t^^e9 ^^5e"); got != "été ^" {
+		t.Fatalf("expected the ^^xx characters to be decoded, got %q", got)
+	}
+	fr, err := LoadHyphenator("fr", strings.NewReader(`\patterns{ ^^e91t }`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fr.Hyphenate("détente"); !reflect.DeepEqual(got, []int{2}) {
+		t.Fatalf("expected dé-tente, got %v", got)
+	}
+}
+
+func TestSplitTextWithHyphenation(t *testing.T) {
+	err := initTesting()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf := setupDefaultA4PDF(t)
+	opt := &BreakOption{Mode: BreakModeHyphenation, Hyphenator: testHyphenator(t)}
+
+	width, err := pdf.MeasureTextWidth("the hyphen-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines, err := pdf.SplitTextWithOption("the hyphenation of text", width+0.1, opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) < 2 || lines[0] != "the hyphen-" || !strings.HasPrefix(lines[1], "ation") {
+		t.Fatalf("unexpected lines %q", lines)
+	}
+
+	width, err = pdf.MeasureTextWidth("in-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines, err = pdf.SplitTextWithOption("in\u00ADcredible\nwell-known", width+0.1, &BreakOption{Mode: BreakModeHyphenation})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines[0] != "in-" {
+		t.Fatalf("expected a break at the soft hyphen, got %q", lines)
+	}
+	for _, line := range lines {
+		if strings.ContainsRune(line, softHyphen) {
+			t.Fatalf("soft hyphen left in line %q", line)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package gopdf
 
+import "io"
+
 type ICacheColorText interface {
 	ICacheContent
 	equal(obj ICacheColorText) bool
+	writeStroke(w io.Writer) error //writes the color as the stroke color, for text drawn with a stroke
 }
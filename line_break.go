@@ -0,0 +1,135 @@
+package gopdf
+
+import (
+	"sort"
+	"strings"
+)
+
+// softHyphen marks a position where a word may be hyphenated, it is only visible when the line is broken there.
+const softHyphen = '\u00AD'
+
+// lineBreak is a position in a text, before the rune at index, where a line may be broken.
+type lineBreak struct {
+	index     int
+	hyphen    bool // the separator has to be appended to the line
+	mandatory bool // the line must be broken here (e.g. after a newline)
+}
+
+func sortLineBreaks(breaks []lineBreak) {
+	sort.SliceStable(breaks, func(i, j int) bool {
+		return breaks[i].index < breaks[j].index
+	})
+}
+
+// isTrailingSpace reports whether r is dropped when it ends a line.
+func isTrailingSpace(r rune) bool {
+	switch r {
+	case ' ', '\t', '\n', '\v', '\f', '\r', '\u0085', '\u200B', '\u2028', '\u2029', '\u3000':
+		return true
+	}
+	return false
+}
+
+// isInvisibleFormat reports whether r only controls the line breaking and is not drawn.
+func isInvisibleFormat(r rune) bool {
+	switch r {
+	case softHyphen, '\u200B', '\u2060', '\uFEFF':
+		return true
+	}
+	return false
+}
+
+// lineText returns the visible text of a line, without trailing spaces, soft hyphens and other invisible format characters.
+func lineText(text []rune, separator string, hyphen bool) string {
+	end := len(text)
+	for end > 0 && isTrailingSpace(text[end-1]) {
+		end--
+	}
+	line := make([]rune, 0, end+len(separator))
+	for _, r := range text[:end] {
+		if !isInvisibleFormat(r) {
+			line = append(line, r)
+		}
+	}
+	if hyphen {
+		line = append(line, []rune(separator)...)
+	}
+	return string(line)
+}
+
+// splitTextAtBreaks fills each line up to width, breaking it at the last possible break that still fits.
+// A line that cannot be broken at any of the breaks is broken mid-word like BreakModeStrict.
+// The breaks must be sorted by index.
+func (gp *GoPdf) splitTextAtBreaks(text []rune, breaks []lineBreak, width float64, separator string) ([]string, error) {
+	return gp.splitTextAtBreaksHanging(text, breaks, width, separator, nil)
+}
+
+// splitTextAtBreaksHanging works like splitTextAtBreaks, but the characters at the end of a line
+// for which hanging returns true are not taken into account for its width.
+func (gp *GoPdf) splitTextAtBreaksHanging(text []rune, breaks []lineBreak, width float64, separator string, hanging func(rune) bool) ([]string, error) {
+	if len(text) == 0 {
+		return nil, ErrEmptyString
+	}
+	if len(breaks) == 0 || breaks[len(breaks)-1].index < len(text) {
+		breaks = append(breaks, lineBreak{index: len(text), mandatory: true})
+	}
+
+	var lines []string
+	start := 0
+	next := 0 // first break that may end the current line
+	for start < len(text) {
+		for next < len(breaks) && breaks[next].index <= start {
+			next++
+		}
+
+		best := -1
+		for i := next; i < len(breaks); i++ {
+			b := breaks[i]
+			line := lineText(text[start:b.index], separator, b.hyphen)
+			if hanging != nil && !b.hyphen {
+				line = strings.TrimRightFunc(line, hanging)
+			}
+			w, err := gp.MeasureTextWidth(line)
+			if err != nil {
+				return nil, err
+			}
+			if w > width {
+				break
+			}
+			best = i
+			if b.mandatory {
+				break
+			}
+		}
+
+		if best >= 0 {
+			b := breaks[best]
+			lines = append(lines, lineText(text[start:b.index], separator, b.hyphen))
+			start = b.index
+			continue
+		}
+
+		// nothing fits, break mid-word before the next mandatory break
+		limit := len(text)
+		for i := next; i < len(breaks); i++ {
+			if breaks[i].mandatory {
+				limit = breaks[i].index
+				break
+			}
+		}
+		end := start + 1
+		for end < limit {
+			w, err := gp.MeasureTextWidth(lineText(text[start:end+1], separator, false))
+			if err != nil {
+				return nil, err
+			}
+			if w > width {
+				break
+			}
+			end++
+		}
+		lines = append(lines, lineText(text[start:end], separator, false))
+		start = end
+	}
+	return lines, nil
+}
@@ -0,0 +1,113 @@
+package gopdf
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MissingGlyphsError is returned when drawing characters without a glyph in the font with Config.StrictGlyphs.
+type MissingGlyphsError struct {
+	Family string
+	Runes  []rune // characters without a glyph, in the order of the text
+}
+
+func (e *MissingGlyphsError) Error() string {
+	chars := make([]string, len(e.Runes))
+	for i, r := range e.Runes {
+		chars[i] = fmt.Sprintf("%#U", r)
+	}
+	return fmt.Sprintf("glyph not found in font %s for %s", e.Family, strings.Join(chars, ", "))
+}
+
+// Is reports whether target is ErrGlyphNotFound, so that errors.Is(err, ErrGlyphNotFound) matches a MissingGlyphsError.
+func (e *MissingGlyphsError) Is(target error) bool {
+	return target == ErrGlyphNotFound
+}
+
+// MissingGlyph is a character drawn without a glyph in its font, replaced by the OnGlyphNotFoundSubstitute of the font.
+type MissingGlyph struct {
+	Family string
+	Page   int // number of the page, from 1
+	Rune   rune
+	Count  int // number of times the character was drawn on the page
+}
+
+type missingGlyphKey struct {
+	family string
+	page   int
+	r      rune
+}
+
+// MissingGlyphs returns the characters drawn without a glyph in the document, by family, page and character.
+// Checked after WritePdf, it lets a build fail when the fonts no longer cover the text.
+func (gp *GoPdf) MissingGlyphs() []MissingGlyph {
+	var missing []MissingGlyph
+	for key, count := range gp.missingGlyphs {
+		missing = append(missing, MissingGlyph{Family: key.family, Page: key.page, Rune: key.r, Count: count})
+	}
+	sort.Slice(missing, func(i, j int) bool {
+		a, b := missing[i], missing[j]
+		if a.Family != b.Family {
+			return a.Family < b.Family
+		}
+		if a.Page != b.Page {
+			return a.Page < b.Page
+		}
+		return a.Rune < b.Rune
+	})
+	return missing
+}
+
+// addChars adds the characters of text to f like AddChars, for text drawn on the content at indexOfContent.
+// The characters without a glyph are counted in MissingGlyphs, or returned in a MissingGlyphsError with Config.StrictGlyphs.
+func (gp *GoPdf) addChars(f *SubsetFontObj, text string, indexOfContent int) (string, error) {
+	text, err := f.AddChars(text)
+	if err != nil {
+		return "", err
+	}
+	if err := gp.missingGlyphsError(f); err != nil {
+		return "", err
+	}
+	if len(f.missingRunes) == 0 {
+		return text, nil
+	}
+	page := gp.contentPageNumber(indexOfContent)
+	if gp.missingGlyphs == nil {
+		gp.missingGlyphs = make(map[missingGlyphKey]int)
+	}
+	for _, r := range f.missingRunes {
+		gp.missingGlyphs[missingGlyphKey{family: f.GetFamily(), page: page, r: r}]++
+	}
+	return text, nil
+}
+
+// missingGlyphsError returns the MissingGlyphsError of the characters without a glyph of the last AddChars of f
+// with Config.StrictGlyphs, nil otherwise.
+func (gp *GoPdf) missingGlyphsError(f *SubsetFontObj) error {
+	if !gp.config.StrictGlyphs || len(f.missingRunes) == 0 {
+		return nil
+	}
+	e := &MissingGlyphsError{Family: f.GetFamily()}
+	for _, r := range f.missingRunes {
+		if !strings.ContainsRune(string(e.Runes), r) {
+			e.Runes = append(e.Runes, r)
+		}
+	}
+	return e
+}
+
+// contentPageNumber returns the number of the page of the content at indexOfContent,
+// the current page for a content not added yet.
+func (gp *GoPdf) contentPageNumber(indexOfContent int) int {
+	indexOfPageObj := gp.curr.IndexOfPageObj
+	if indexOfContent >= 0 && indexOfContent < len(gp.pdfObjs) {
+		if content, ok := gp.pdfObjs[indexOfContent].(*ContentObj); ok && content.indexOfPageObj != -1 {
+			indexOfPageObj = content.indexOfPageObj
+		}
+	}
+	if indexOfPageObj == -1 {
+		return 0
+	}
+	return gp.pageNumbers()[indexOfPageObj]
+}
@@ -0,0 +1,154 @@
+package gopdf
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMissingGlyphs(t *testing.T) {
+	err := initTesting()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ttfp := parseTestFont(t, "./test/res/LiberationSerif-Regular.ttf")
+	for _, r := range "中ก" {
+		if _, ok := ttfp.Chars()[int(r)]; ok {
+			t.Fatalf("expected no glyph for %c in the test font", r)
+		}
+	}
+
+	pdf := GoPdf{}
+	pdf.Start(Config{PageSize: *PageSizeA4})
+	for _, family := range []string{"serif", "other"} {
+		err = pdf.AddTTFFont(family, "./test/res/LiberationSerif-Regular.ttf")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = pdf.SetFont("serif", "", 14)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf.AddPage()
+	err = pdf.Cell(nil, "A中B中")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf.AddPage()
+	err = pdf.Text("กA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pdf.MultiCellWithOption(&Rect{W: 100, H: 100}, "中 ก", CellOption{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pdf.SetFont("other", "", 14)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pdf.MultiCell(&Rect{W: 100, H: 100}, "ก")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// measuring text is not drawing it
+	_, err = pdf.MeasureTextWidth("中")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pdf.SetPage(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pdf.Text("中")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pdf.WritePdf("./test/out/missing_glyphs.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []MissingGlyph{
+		{Family: "other", Page: 1, Rune: '中', Count: 1},
+		{Family: "other", Page: 2, Rune: 'ก', Count: 1},
+		{Family: "serif", Page: 1, Rune: '中', Count: 2},
+		{Family: "serif", Page: 2, Rune: 'ก', Count: 2},
+		{Family: "serif", Page: 2, Rune: '中', Count: 1},
+	}
+	if got := pdf.MissingGlyphs(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected missing glyphs %+v", got)
+	}
+
+	// a frame on a blank page drawn after later pages reports its own page
+	frames := GoPdf{}
+	frames.Start(Config{PageSize: *PageSizeA4})
+	err = frames.AddTTFFont("serif", "./test/res/LiberationSerif-Regular.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = frames.SetFont("serif", "", 14)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		frames.AddPage()
+	}
+	_, err = frames.FillTextFrame(NewTextFrame(1, 50, 50, 200, 200), "中")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = frames.SetPage(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = frames.Text("ก")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []MissingGlyph{
+		{Family: "serif", Page: 1, Rune: '中', Count: 1},
+		{Family: "serif", Page: 2, Rune: 'ก', Count: 1},
+	}
+	if got := frames.MissingGlyphs(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected missing glyphs %+v", got)
+	}
+
+	strict := GoPdf{}
+	strict.Start(Config{PageSize: *PageSizeA4, StrictGlyphs: true})
+	strict.AddPage()
+	err = strict.AddTTFFont("serif", "./test/res/LiberationSerif-Regular.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = strict.SetFont("serif", "", 14)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, draw := range []func() error{
+		func() error { return strict.Cell(nil, "A中Bก中") },
+		func() error { return strict.Text("A中Bก中") },
+		func() error { return strict.MultiCell(&Rect{W: 100, H: 100}, "A中Bก中") },
+		func() error { return strict.MultiCellWithOption(&Rect{W: 100, H: 100}, "A中Bก中", CellOption{}) },
+	} {
+		err = draw()
+		var missing *MissingGlyphsError
+		if !errors.As(err, &missing) || !errors.Is(err, ErrGlyphNotFound) {
+			t.Fatalf("expected a MissingGlyphsError, got %v", err)
+		}
+		if missing.Family != "serif" || string(missing.Runes) != "中ก" {
+			t.Fatalf("unexpected missing glyphs %+v", missing)
+		}
+		if !strings.Contains(err.Error(), "U+4E2D '中', U+0E01 'ก'") {
+			t.Fatalf("unexpected error %q", err)
+		}
+	}
+	err = strict.Cell(nil, "AB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strict.MissingGlyphs(); len(got) != 0 {
+		t.Fatalf("expected no missing glyphs drawn, got %+v", got)
+	}
+}
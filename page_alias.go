@@ -0,0 +1,106 @@
+package gopdf
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultAliasNbPages is the alias used by AliasNbPages when it is called with an empty alias.
+const DefaultAliasNbPages = "{nb}"
+
+// DefaultAliasSectionNbPages is the alias used by AliasSectionNbPages when it is called with an empty alias.
+const DefaultAliasSectionNbPages = "{snb}"
+
+// AliasNbPages : enable the replacement of alias with the number of pages of the document when the pdf is written,
+// "{nb}" if alias is empty. The aliases are not replaced until AliasNbPages is called.
+// The alias is replaced in Text, Cell and MultiCell, and cells aligned Right or Center are aligned again.
+//
+//	Usage:
+//	pdf.AliasNbPages("")
+//	pdf.AddFooter(func() {
+//		pdf.SetY(800)
+//		pdf.CellWithOption(&gopdf.Rect{W: 500, H: 20}, fmt.Sprintf("Page %d of {nb}", pdf.GetNumberOfPages()), gopdf.CellOption{Align: gopdf.Right})
+//	})
+func (gp *GoPdf) AliasNbPages(alias string) {
+	if alias == "" {
+		alias = DefaultAliasNbPages
+	}
+	gp.aliasNbPages = alias
+}
+
+// AliasSectionNbPages : enable the replacement of alias with the number of pages of the current section
+// when the pdf is written, "{snb}" if alias is empty. The aliases are not replaced until AliasSectionNbPages is called.
+func (gp *GoPdf) AliasSectionNbPages(alias string) {
+	if alias == "" {
+		alias = DefaultAliasSectionNbPages
+	}
+	gp.aliasSectionNbPages = alias
+}
+
+// StartPageSection : start a new section of pages with the next page added by AddPage.
+// The pages before the first section are a section too.
+func (gp *GoPdf) StartPageSection() {
+	gp.sectionStarts = append(gp.sectionStarts, gp.numOfPagesObj+1)
+}
+
+// sectionNbPages returns the number of pages of the section of page.
+func (gp *GoPdf) sectionNbPages(page int) int {
+	starts := append([]int{1}, gp.sectionStarts...)
+	sort.Ints(starts)
+	i := sort.SearchInts(starts, page+1) - 1
+	end := gp.numOfPagesObj + 1
+	for _, start := range starts[i+1:] {
+		if start > starts[i] {
+			end = start
+			break
+		}
+	}
+	return end - starts[i]
+}
+
+// replacePageAliases replaces the page count aliases of the texts of every page.
+func (gp *GoPdf) replacePageAliases() error {
+	if gp.aliasNbPages == "" && gp.aliasSectionNbPages == "" {
+		return nil
+	}
+	total := strconv.Itoa(gp.numOfPagesObj)
+	numbers := gp.pageNumbers()
+	page := 0
+	for _, obj := range gp.pdfObjs {
+		switch obj := obj.(type) {
+		case *PageObj:
+			page++
+		case *ContentObj:
+			page := page
+			if obj.indexOfPageObj != -1 {
+				page = numbers[obj.indexOfPageObj]
+			}
+			for _, cache := range obj.listCache.caches {
+				c, ok := cache.(*cacheContentText)
+				if !ok || c.isPlaceHolder {
+					continue
+				}
+				text := c.text
+				if gp.aliasNbPages != "" {
+					text = strings.Replace(text, gp.aliasNbPages, total, -1)
+				}
+				if gp.aliasSectionNbPages != "" {
+					text = strings.Replace(text, gp.aliasSectionNbPages, strconv.Itoa(gp.sectionNbPages(page)), -1)
+				}
+				if text == c.text {
+					continue
+				}
+				text, err := c.fontSubset.AddChars(text)
+				if err != nil {
+					return err
+				}
+				c.text = text
+				if _, _, err := c.createContent(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
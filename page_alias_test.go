@@ -0,0 +1,102 @@
+package gopdf
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestPageAliases(t *testing.T) {
+	err := initTesting()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pdf := setupDefaultA4PDF(t)
+	err = pdf.SetFont("LiberationSerif-Regular", "", 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf.AliasNbPages("")
+	pdf.AliasSectionNbPages("")
+	var footers []*cacheContentText
+	pdf.AddFooter(func() {
+		pdf.SetXY(50, 800)
+		text := fmt.Sprintf("Page %d of {nb}, {snb} in section", pdf.GetNumberOfPages())
+		if err := pdf.CellWithOption(&Rect{W: 500, H: 20}, text, CellOption{Align: Right}); err != nil {
+			t.Fatal(err)
+		}
+		footers = append(footers, pdf.getContent().listCache.last().(*cacheContentText))
+	})
+	for i := 0; i < 12; i++ {
+		if i == 2 {
+			pdf.StartPageSection()
+		}
+		pdf.AddPage()
+	}
+
+	err = pdf.WritePdf("./test/out/page_aliases.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"Page 1 of 12, 2 in section",
+		"Page 2 of 12, 2 in section",
+		"Page 3 of 12, 10 in section",
+		"Page 12 of 12, 10 in section",
+	}
+	for i, footer := range []*cacheContentText{footers[0], footers[1], footers[2], footers[11]} {
+		if footer.text != expected[i] {
+			t.Fatalf("expected %q, got %q", expected[i], footer.text)
+		}
+	}
+
+	width, err := pdf.MeasureTextWidth(expected[3])
+	if err != nil {
+		t.Fatal(err)
+	}
+	x, err := footers[11].calX()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(x+width-550) > 0.01 {
+		t.Fatalf("expected the footer to end at 550, got %f", x+width)
+	}
+
+	// the aliases are kept as they are without AliasNbPages and AliasSectionNbPages
+	pdf = setupDefaultA4PDF(t)
+	pdf.AddPage()
+	err = pdf.SetFont("LiberationSerif-Regular", "", 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pdf.Cell(nil, "{nb} {snb}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pdf.GetBytesPdfReturnErr(); err != nil {
+		t.Fatal(err)
+	}
+	if text := pdf.getContent().listCache.last().(*cacheContentText).text; text != "{nb} {snb}" {
+		t.Fatalf("expected the aliases to be kept without the opt-in, got %q", text)
+	}
+
+	pdf = setupDefaultA4PDF(t)
+	pdf.AliasNbPages("[total]")
+	pdf.AddPage()
+	err = pdf.SetFont("LiberationSerif-Regular", "", 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pdf.Cell(nil, "{nb} [total]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pdf.GetBytesPdfReturnErr(); err != nil {
+		t.Fatal(err)
+	}
+	if text := pdf.getContent().listCache.last().(*cacheContentText).text; text != "{nb} 1" {
+		t.Fatalf("expected only the custom alias to be replaced, got %q", text)
+	}
+}
@@ -61,7 +61,11 @@ func (p *PdfDictionaryObj) write(w io.Writer, objID int) error {
 
 	fmt.Fprintf(w, "<</Length %d\n", zbuff.Len())
 	io.WriteString(w, "/Filter /FlateDecode\n")
-	fmt.Fprintf(w, "/Length1 %d\n", len(b))
+	if subtype := p.fontFileSubtype(); subtype != "" {
+		fmt.Fprintf(w, "/Subtype /%s\n", subtype)
+	} else {
+		fmt.Fprintf(w, "/Length1 %d\n", len(b))
+	}
 	io.WriteString(w, ">>\n")
 	io.WriteString(w, "stream\n")
 	if p.protection() != nil {
@@ -108,7 +112,7 @@ func (p *PdfDictionaryObj) makeGlyfAndLocaTable() ([]byte, []int, error) {
 
 	numGlyphs := int(ttfp.NumGlyphs())
 
-	glyphArray := p.completeGlyphClosure(p.PtrToSubsetFontObj.CharacterToGlyphIndex)
+	glyphArray := p.completeGlyphClosure(p.PtrToSubsetFontObj.glyphs())
 	sort.Ints(glyphArray)
 	glyphArray = p.distinctInts(glyphArray)
 	glyphCount := len(glyphArray)
@@ -184,6 +188,11 @@ func (p *PdfDictionaryObj) getGlyphData(glyph int) []byte {
 func (p *PdfDictionaryObj) makeFont() ([]byte, error) {
 	var buff Buff
 	ttfp := p.PtrToSubsetFontObj.GetTTFParser()
+	if p.PtrToSubsetFontObj.ttfFontOption.EmbedFullFont {
+		return p.makeFullFont()
+	} else if ttfp.IsCFF() {
+		return p.makeCFFFont()
+	}
 	tables := make(map[string]core.TableDirectoryEntry)
 	tables["cvt "] = ttfp.GetTables()["cvt "] //มีช่องว่างด้วยนะ
 	tables["fpgm"] = ttfp.GetTables()["fpgm"]
@@ -278,11 +287,90 @@ func (p *PdfDictionaryObj) makeFont() ([]byte, error) {
 	return buff.Bytes(), nil
 }
 
-func (p *PdfDictionaryObj) completeGlyphClosure(mapOfglyphs *MapOfCharacterToGlyphIndex) []int {
+// fontFileSubtype returns the subtype of the FontFile3 stream of a CFF font, "" for a TrueType font embedded as FontFile2.
+func (p *PdfDictionaryObj) fontFileSubtype() string {
+	ttfp := p.PtrToSubsetFontObj.GetTTFParser()
+	if !ttfp.IsCFF() {
+		return ""
+	} else if ttfp.TableData("CFF2") != nil || p.PtrToSubsetFontObj.ttfFontOption.EmbedFullFont {
+		return "OpenType"
+	}
+	return "CIDFontType0C"
+}
+
+// makeCFFFont subsets the CFF table of the font, embedded as is,
+// or the CFF2 table embedded in an OpenType font since PDF has no bare CFF2 font program.
+func (p *PdfDictionaryObj) makeCFFFont() ([]byte, error) {
+	ttfp := p.PtrToSubsetFontObj.GetTTFParser()
+	tag := "CFF "
+	if ttfp.TableData("CFF2") != nil {
+		tag = "CFF2"
+	}
+	cff, err := parseCFF(ttfp.TableData(tag))
+	if err != nil {
+		return nil, err
+	}
+	var glyphs []int
+	for _, g := range p.PtrToSubsetFontObj.glyphs() {
+		glyphs = append(glyphs, int(g))
+	}
+	b, err := cff.subset(glyphs)
+	if err != nil || tag == "CFF " {
+		return b, err
+	}
+	return p.makeOpenTypeFont(map[string][]byte{tag: b}), nil
+}
+
+// makeFullFont returns the complete font. The CFF table of an OpenType font is written CID-keyed with all its glyphs,
+// like the subsets, since the glyph indexes are used as CIDs.
+func (p *PdfDictionaryObj) makeFullFont() ([]byte, error) {
+	ttfp := p.PtrToSubsetFontObj.GetTTFParser()
+	if !ttfp.IsCFF() {
+		return ttfp.FontData(), nil
+	}
+	tag := "CFF "
+	if ttfp.TableData("CFF2") != nil {
+		tag = "CFF2"
+	}
+	cff, err := parseCFF(ttfp.TableData(tag))
+	if err != nil {
+		return nil, err
+	}
+	glyphs := make([]int, len(cff.charStrings))
+	for i := range glyphs {
+		glyphs[i] = i
+	}
+	b, err := cff.subset(glyphs)
+	if err != nil {
+		return nil, err
+	}
+	return p.makeOpenTypeFont(map[string][]byte{tag: b}), nil
+}
+
+// openTypeDroppedTables are the tables not needed to draw glyphs by index, left out of embedded OpenType subsets.
+var openTypeDroppedTables = map[string]bool{
+	"BASE": true, "DSIG": true, "GDEF": true, "GPOS": true, "GSUB": true, "JSTF": true, "MATH": true,
+}
+
+// makeOpenTypeFont writes the tables of the font with the tables of replace instead of the original ones.
+func (p *PdfDictionaryObj) makeOpenTypeFont(replace map[string][]byte) []byte {
+	ttfp := p.PtrToSubsetFontObj.GetTTFParser()
+	tables := make(map[string][]byte)
+	for tag := range ttfp.GetTables() {
+		if !openTypeDroppedTables[tag] || p.PtrToSubsetFontObj.ttfFontOption.EmbedFullFont {
+			tables[tag] = ttfp.TableData(tag)
+		}
+	}
+	for tag, data := range replace {
+		tables[tag] = data
+	}
+	return sfntBytes(0x4f54544f, tables) // OTTO
+}
+
+func (p *PdfDictionaryObj) completeGlyphClosure(glyphs []uint) []int {
 	var glyphArray []int
 	//copy
 	isContainZero := false
-	glyphs := mapOfglyphs.AllVals()
 	for _, v := range glyphs {
 		glyphArray = append(glyphArray, int(v))
 		if v == 0 {
@@ -361,6 +449,8 @@ const moreComponents = 32
 const arg1and2areWords = 1
 const xAndYScale = 64
 const twoByTwo = 128
+const argsAreXYValues = 2
+const weHaveInstructions = 256
 
 // GetOffset get offset from glyf table
 func (p *PdfDictionaryObj) GetOffset(glyph int) int {
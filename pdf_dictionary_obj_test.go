@@ -0,0 +1,120 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/signintech/gopdf/fontmaker/core"
+)
+
+func TestEmbedFullFont(t *testing.T) {
+	err := initTesting()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ttf, err := os.ReadFile("./test/res/LiberationSerif-Regular.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	otf := buildTestOTF(t, false)
+
+	pdf := GoPdf{}
+	pdf.Start(Config{PageSize: *PageSizeA4})
+	pdf.SetCompressLevel(0)
+	pdf.AddPage()
+	for _, font := range []struct {
+		family string
+		data   []byte
+		full   bool
+	}{{"subset", ttf, false}, {"full", ttf, true}, {"full-cff", otf, true}} {
+		err = pdf.AddTTFFontDataWithOption(font.family, font.data, TtfOption{EmbedFullFont: font.full})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = pdf.SetFont(font.family, "", 14)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = pdf.Cell(nil, "Hello")
+		if err != nil {
+			t.Fatal(err)
+		}
+		pdf.Br(20)
+	}
+
+	for _, obj := range pdf.pdfObjs {
+		dict, ok := obj.(*PdfDictionaryObj)
+		if !ok {
+			continue
+		}
+		b, err := dict.makeFont()
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch dict.PtrToSubsetFontObj.GetFamily() {
+		case "subset":
+			if len(b) >= len(ttf) {
+				t.Fatalf("expected a subset smaller than the font, got %d bytes", len(b))
+			}
+		case "full":
+			if !bytes.Equal(b, ttf) {
+				t.Fatal("expected the complete font")
+			}
+		case "full-cff":
+			var ttfp core.TTFParser
+			if err := ttfp.ParseFontData(b); err != nil {
+				t.Fatal(err)
+			}
+			original := dict.PtrToSubsetFontObj.GetTTFParser()
+			if len(ttfp.GetTables()) != len(original.GetTables()) {
+				t.Fatalf("expected all the tables of the font, got %v", sortedTags(&ttfp))
+			}
+			cff, err := parseCFF(ttfp.TableData("CFF "))
+			if err != nil {
+				t.Fatal(err)
+			}
+			font, err := parseCFF(original.TableData("CFF "))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for g := range font.charStrings {
+				if !bytes.Equal(cff.charStrings[g], font.charStrings[g]) {
+					t.Fatalf("expected the charstring of glyph %d", g)
+				}
+			}
+		}
+	}
+
+	var buff bytes.Buffer
+	_, err = pdf.WriteTo(&buff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := buff.String()
+	ttfp := parseTestFont(t, "./test/res/LiberationSerif-Regular.ttf")
+	var widths []string
+	for g := uint(0); g < ttfp.NumGlyphs(); g++ {
+		widths = append(widths, fmt.Sprint(pdf.curr.FontISubset.GlyphIndexToPdfWidth(g)))
+	}
+	z := ttfp.Chars()['Z']
+	for _, want := range []string{
+		"/W [0[" + strings.Join(widths, " ") + "]]",
+		// Z is not used but in the complete ToUnicode map, in blocks of 100 ranges
+		fmt.Sprintf("<%04X><%04X><005A>\n", z, z), "100 beginbfrange\n",
+		"/FontFile2", "/Subtype /OpenType",
+	} {
+		if !strings.Contains(content, want) {
+			t.Fatalf("expected %q in the pdf", want)
+		}
+	}
+	if n := strings.Count(content, "/W [0["); n != 2 {
+		t.Fatalf("expected the widths of all the glyphs of 2 fonts, got %d", n)
+	}
+	err = pdf.WritePdf("./test/out/embed_full_font.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,508 @@
+package gopdf
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidRichTextMarkup occurs when ParseRichText meets an unknown or unbalanced tag.
+var ErrInvalidRichTextMarkup = errors.New("invalid rich text markup")
+
+// ErrRichTextOverflow is returned when the lines of rich text runs do not all fit in the height of the cell.
+var ErrRichTextOverflow = errors.New("rich text does not fit in the cell")
+
+// TextRun is a piece of text drawn with one font, size, color and decoration.
+// Zero values are inherited from the current state of the document.
+type TextRun struct {
+	Text          string
+	Family        string    // font family, empty means the current family
+	Style         int       // Regular|Bold|Italic|Underline|Strikethrough|Overline
+	Size          float64   // font size, 0 means the current font size
+	Color         *RGBColor // text color, nil means the current text color
+	Link          string    // external link URL, empty means no link
+	BaselineShift float64   // raise (positive) or lower (negative) the text, in document units
+}
+
+// richPiece is a fragment of a run that is measured and placed as a whole.
+type richPiece struct {
+	run     int
+	text    string
+	width   float64
+	isSpace bool
+}
+
+// richWord is a group of pieces that must not be broken apart (e.g. "<b>bold</b>ed").
+type richWord struct {
+	pieces    []richPiece
+	width     float64
+	isSpace   bool
+	isNewline bool
+	run       int
+}
+
+type richLine struct {
+	pieces       []richPiece
+	width        float64
+	ascent       float64
+	descent      float64
+	paragraphEnd bool
+}
+
+// richMetric is the vertical extent of a run above and below the baseline, in document units.
+type richMetric struct {
+	ascent  float64
+	descent float64
+}
+
+// fontState is the part of Current that text runs temporarily override.
+type fontState struct {
+	fontISubset   *SubsetFontObj
+	fontSize      float64
+	fontStyle     int
+	fontFontCount int
+	txtColor      ICacheColorText
+	txtColorMode  string
+	charSpacing   float64
+	wordSpacing   float64
+}
+
+func (gp *GoPdf) saveFontState() fontState {
+	return fontState{
+		fontISubset:   gp.curr.FontISubset,
+		fontSize:      gp.curr.FontSize,
+		fontStyle:     gp.curr.FontStyle,
+		fontFontCount: gp.curr.FontFontCount,
+		txtColor:      gp.curr.txtColor,
+		txtColorMode:  gp.curr.txtColorMode,
+		charSpacing:   gp.curr.CharSpacing,
+		wordSpacing:   gp.curr.wordSpacing,
+	}
+}
+
+func (gp *GoPdf) restoreFontState(s fontState) {
+	gp.curr.FontISubset = s.fontISubset
+	gp.curr.FontSize = s.fontSize
+	gp.curr.FontStyle = s.fontStyle
+	gp.curr.FontFontCount = s.fontFontCount
+	gp.curr.txtColor = s.txtColor
+	gp.curr.txtColorMode = s.txtColorMode
+	gp.curr.CharSpacing = s.charSpacing
+	gp.curr.wordSpacing = s.wordSpacing
+}
+
+// applyTextRun selects the font and color of run, falling back to base for unset fields.
+func (gp *GoPdf) applyTextRun(run TextRun, base fontState) error {
+	if base.fontISubset == nil {
+		return ErrMissingFontFamily
+	}
+	family := run.Family
+	if family == "" {
+		family = base.fontISubset.GetFamily()
+	}
+	size := run.Size
+	if size == 0 {
+		size = base.fontSize
+	}
+	if err := gp.SetFontWithStyle(family, run.Style, size); err != nil {
+		return err
+	}
+	if run.Color != nil {
+		gp.SetTextColor(run.Color.R, run.Color.G, run.Color.B)
+	} else {
+		gp.curr.txtColor = base.txtColor
+		gp.curr.txtColorMode = base.txtColorMode
+	}
+	return nil
+}
+
+// textRunMetric returns the extent of the current font above and below the baseline, in document units.
+func (gp *GoPdf) textRunMetric(run TextRun) richMetric {
+	f := gp.curr.FontISubset
+	ascent := convertTypoUnit(float64(f.ttfp.TypoAscender()), f.ttfp.UnitsPerEm(), gp.curr.FontSize)
+	descent := -convertTypoUnit(float64(f.ttfp.TypoDescender()), f.ttfp.UnitsPerEm(), gp.curr.FontSize)
+	gp.PointsToUnitsVar(&ascent, &descent)
+	return richMetric{
+		ascent:  ascent + run.BaselineShift,
+		descent: descent - run.BaselineShift,
+	}
+}
+
+// MultiCellRich draws styled text runs as one paragraph wrapped at spaces
+// ( use current x,y is upper-left corner of cell)
+func (gp *GoPdf) MultiCellRich(rectangle *Rect, runs []TextRun) error {
+	return gp.MultiCellRichWithOption(rectangle, runs, CellOption{Align: Left | Top})
+}
+
+// MultiCellRichWithOption draws styled text runs as one paragraph wrapped at spaces.
+// Lines are as tall as their largest run, and are aligned according to opt.Align (Left, Center, Right, Justify).
+// When the lines are taller than rectangle.H, the lines that fit are drawn and ErrRichTextOverflow is returned,
+// a height of 0 draws all the lines.
+//
+//	Usage:
+//	runs, _ := gopdf.ParseRichText("Hello <b>bold</b> and <color=#ff0000>red</color> text")
+//	pdf.MultiCellRichWithOption(&gopdf.Rect{W: 200, H: 100}, runs, gopdf.CellOption{Align: gopdf.Left})
+func (gp *GoPdf) MultiCellRichWithOption(rectangle *Rect, runs []TextRun, opt CellOption) error {
+	if len(runs) == 0 {
+		return ErrEmptyString
+	}
+
+	base := gp.saveFontState()
+	defer gp.restoreFontState(base)
+
+	lines, metrics, err := gp.layoutTextRuns(runs, rectangle.W, base)
+	if err != nil {
+		return err
+	}
+
+	x := gp.GetX()
+	y := gp.GetY()
+	var totalLineHeight float64
+	for _, line := range lines {
+		lineHeight := line.ascent + line.descent
+		if rectangle.H > 0 && totalLineHeight+lineHeight > rectangle.H {
+			gp.SetXY(x, y+totalLineHeight)
+			return ErrRichTextOverflow
+		}
+		if err := gp.drawRichLine(runs, metrics, line, x, y+totalLineHeight, rectangle.W, opt, base); err != nil {
+			return err
+		}
+		totalLineHeight += lineHeight
+	}
+
+	gp.SetXY(x, y+totalLineHeight)
+	return nil
+}
+
+// layoutTextRuns measures runs and breaks them into lines no wider than width.
+func (gp *GoPdf) layoutTextRuns(runs []TextRun, width float64, base fontState) ([]richLine, []richMetric, error) {
+	metrics := make([]richMetric, len(runs))
+	var words []richWord
+	glue := false
+	for i, run := range runs {
+		if err := gp.applyTextRun(run, base); err != nil {
+			return nil, nil, err
+		}
+		metrics[i] = gp.textRunMetric(run)
+
+		for _, token := range splitRichTokens(run.Text) {
+			if token == "\n" {
+				words = append(words, richWord{isNewline: true, run: i})
+				glue = false
+				continue
+			}
+			w, err := gp.MeasureTextWidth(token)
+			if err != nil {
+				return nil, nil, err
+			}
+			piece := richPiece{run: i, text: token, width: w, isSpace: token == " "}
+			if piece.isSpace {
+				words = append(words, richWord{pieces: []richPiece{piece}, width: w, isSpace: true, run: i})
+				glue = false
+				continue
+			}
+			if glue {
+				last := &words[len(words)-1]
+				last.pieces = append(last.pieces, piece)
+				last.width += w
+				continue
+			}
+			words = append(words, richWord{pieces: []richPiece{piece}, width: w, run: i})
+			glue = true
+		}
+	}
+
+	var lines []richLine
+	var line richLine
+	lastRun := 0
+	flush := func(paragraphEnd bool) {
+		// trailing spaces do not count toward the width of a line
+		for len(line.pieces) > 0 && line.pieces[len(line.pieces)-1].isSpace {
+			line.width -= line.pieces[len(line.pieces)-1].width
+			line.pieces = line.pieces[:len(line.pieces)-1]
+		}
+		if line.ascent == 0 && line.descent == 0 {
+			line.ascent = metrics[lastRun].ascent
+			line.descent = metrics[lastRun].descent
+		}
+		line.paragraphEnd = paragraphEnd
+		lines = append(lines, line)
+		line = richLine{}
+	}
+	add := func(p richPiece) {
+		line.pieces = append(line.pieces, p)
+		line.width += p.width
+		if m := metrics[p.run]; m.ascent > line.ascent {
+			line.ascent = m.ascent
+		}
+		if m := metrics[p.run]; m.descent > line.descent {
+			line.descent = m.descent
+		}
+		lastRun = p.run
+	}
+
+	for _, word := range words {
+		if word.isNewline {
+			lastRun = word.run
+			flush(true)
+			continue
+		}
+		if word.isSpace {
+			if len(line.pieces) > 0 {
+				add(word.pieces[0])
+			}
+			continue
+		}
+		if line.width+word.width > width && len(line.pieces) > 0 {
+			flush(false)
+		}
+		if word.width <= width {
+			for _, p := range word.pieces {
+				add(p)
+			}
+			continue
+		}
+		// the word alone is wider than the cell, so break it mid-word
+		for _, p := range word.pieces {
+			if err := gp.applyTextRun(runs[p.run], base); err != nil {
+				return nil, nil, err
+			}
+			var chunk []rune
+			chunkWidth := 0.0
+			for _, r := range p.text {
+				rw, err := gp.MeasureTextWidth(string(r))
+				if err != nil {
+					return nil, nil, err
+				}
+				if line.width+chunkWidth+rw > width && (len(line.pieces) > 0 || len(chunk) > 0) {
+					if len(chunk) > 0 {
+						add(richPiece{run: p.run, text: string(chunk), width: chunkWidth})
+					}
+					flush(false)
+					chunk = chunk[:0]
+					chunkWidth = 0
+				}
+				chunk = append(chunk, r)
+				chunkWidth += rw
+			}
+			if len(chunk) > 0 {
+				add(richPiece{run: p.run, text: string(chunk), width: chunkWidth})
+			}
+		}
+	}
+	if len(line.pieces) > 0 {
+		flush(true)
+	}
+	return lines, metrics, nil
+}
+
+// drawRichLine draws one laid out line whose top is at y.
+func (gp *GoPdf) drawRichLine(runs []TextRun, metrics []richMetric, line richLine, x, y, width float64, opt CellOption, base fontState) error {
+	// extra space given to each space (or to each character) of a justified line
+	var wordSpacing, charSpacing float64
+	if opt.Align&Justify == Justify {
+		if !line.paragraphEnd && width > line.width {
+			spaces, count := 0, 0
+			for _, p := range line.pieces {
+				if p.isSpace {
+					spaces++
+				}
+				count += len([]rune(p.text))
+			}
+			if spaces > 0 {
+				wordSpacing = (width - line.width) / float64(spaces)
+			} else if count > 1 {
+				charSpacing = (width - line.width) / float64(count-1)
+			}
+		}
+	} else if opt.Align&Right == Right {
+		x += width - line.width
+	} else if opt.Align&Center == Center {
+		x += (width - line.width) / 2
+	}
+
+	baseline := y + line.ascent
+	for i := 0; i < len(line.pieces); {
+		// consecutive pieces of the same run are drawn together so decorations span the spaces
+		run := line.pieces[i].run
+		var text strings.Builder
+		fragWidth := 0.0
+		for ; i < len(line.pieces) && line.pieces[i].run == run; i++ {
+			text.WriteString(line.pieces[i].text)
+			fragWidth += line.pieces[i].width
+			if line.pieces[i].isSpace {
+				fragWidth += wordSpacing
+			}
+			fragWidth += charSpacing * float64(len([]rune(line.pieces[i].text)))
+		}
+
+		if err := gp.applyTextRun(runs[run], base); err != nil {
+			return err
+		}
+		scale := gp.currTextState().scale()
+		gp.curr.wordSpacing = base.wordSpacing + gp.UnitsToPoints(wordSpacing)/scale
+		gp.curr.CharSpacing = base.charSpacing + gp.UnitsToPoints(charSpacing)/scale
+		m := metrics[run]
+		top := baseline - m.ascent
+		gp.SetXY(x, top)
+		if err := gp.Cell(nil, text.String()); err != nil {
+			return err
+		}
+		if runs[run].Link != "" {
+			gp.AddExternalLink(runs[run].Link, x, top, fragWidth, m.ascent+m.descent)
+		}
+		x += fragWidth
+	}
+	return nil
+}
+
+// splitRichTokens splits text into words, single spaces and newlines.
+func splitRichTokens(text string) []string {
+	var tokens []string
+	var word []rune
+	for _, r := range text {
+		if r == ' ' || r == '\n' {
+			if len(word) > 0 {
+				tokens = append(tokens, string(word))
+				word = word[:0]
+			}
+			tokens = append(tokens, string(r))
+			continue
+		}
+		word = append(word, r)
+	}
+	if len(word) > 0 {
+		tokens = append(tokens, string(word))
+	}
+	return tokens
+}
+
+// ParseRichText converts a tiny markup language into text runs for MultiCellRich.
+// Supported tags are <b>, <i>, <u>, <s>, <o>, <color=#RRGGBB> and <a href=URL>;
+// the entities &lt; &gt; and &amp; can be used for literal characters.
+//
+//	Usage:
+//	runs, err := gopdf.ParseRichText(`See <a href=https://example.com><u>the docs</u></a> <b>now</b>`)
+func ParseRichText(markup string) ([]TextRun, error) {
+	type frame struct {
+		tag string
+		run TextRun
+	}
+	var runs []TextRun
+	stack := []frame{{}}
+	var text strings.Builder
+
+	emit := func() {
+		if text.Len() == 0 {
+			return
+		}
+		run := stack[len(stack)-1].run
+		run.Text = text.String()
+		runs = append(runs, run)
+		text.Reset()
+	}
+
+	for len(markup) > 0 {
+		switch {
+		case strings.HasPrefix(markup, "&lt;"):
+			text.WriteByte('<')
+			markup = markup[4:]
+			continue
+		case strings.HasPrefix(markup, "&gt;"):
+			text.WriteByte('>')
+			markup = markup[4:]
+			continue
+		case strings.HasPrefix(markup, "&amp;"):
+			text.WriteByte('&')
+			markup = markup[5:]
+			continue
+		case markup[0] != '<':
+			text.WriteByte(markup[0])
+			markup = markup[1:]
+			continue
+		}
+
+		end := strings.IndexByte(markup, '>')
+		if end < 0 {
+			return nil, fmt.Errorf("%w: unterminated tag", ErrInvalidRichTextMarkup)
+		}
+		tag := strings.TrimSpace(markup[1:end])
+		markup = markup[end+1:]
+		emit()
+
+		if strings.HasPrefix(tag, "/") {
+			name := strings.ToLower(strings.TrimSpace(tag[1:]))
+			if len(stack) == 1 || stack[len(stack)-1].tag != name {
+				return nil, fmt.Errorf("%w: unexpected </%s>", ErrInvalidRichTextMarkup, name)
+			}
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		name, value := splitRichTag(tag)
+		run := stack[len(stack)-1].run
+		switch name {
+		case "b":
+			run.Style |= Bold
+		case "i":
+			run.Style |= Italic
+		case "u":
+			run.Style |= Underline
+		case "s":
+			run.Style |= Strikethrough
+		case "o":
+			run.Style |= Overline
+		case "color":
+			color, err := parseHexColor(value)
+			if err != nil {
+				return nil, err
+			}
+			run.Color = &color
+		case "a":
+			run.Link = value
+		default:
+			return nil, fmt.Errorf("%w: unknown tag <%s>", ErrInvalidRichTextMarkup, name)
+		}
+		stack = append(stack, frame{tag: name, run: run})
+	}
+	emit()
+
+	if len(stack) != 1 {
+		return nil, fmt.Errorf("%w: missing </%s>", ErrInvalidRichTextMarkup, stack[len(stack)-1].tag)
+	}
+	return runs, nil
+}
+
+// splitRichTag splits `color=#ff0000` or `a href="url"` into its name and value.
+func splitRichTag(tag string) (string, string) {
+	name := tag
+	value := ""
+	if i := strings.IndexFunc(tag, func(r rune) bool { return r == '=' || unicode.IsSpace(r) }); i >= 0 {
+		name = tag[:i]
+		value = strings.TrimSpace(tag[i:])
+		if strings.HasPrefix(value, "href") {
+			value = strings.TrimSpace(value[len("href"):])
+		}
+		value = strings.TrimSpace(strings.TrimPrefix(value, "="))
+		value = strings.Trim(value, `"'`)
+	}
+	return strings.ToLower(name), value
+}
+
+// parseHexColor parses colors written as #RRGGBB or #RGB.
+func parseHexColor(s string) (RGBColor, error) {
+	s = strings.TrimPrefix(s, "#")
+	if len(s) == 3 {
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	}
+	if len(s) != 6 {
+		return RGBColor{}, fmt.Errorf("%w: invalid color %q", ErrInvalidRichTextMarkup, s)
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return RGBColor{}, fmt.Errorf("%w: invalid color %q", ErrInvalidRichTextMarkup, s)
+	}
+	return RGBColor{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v)}, nil
+}
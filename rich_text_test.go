@@ -0,0 +1,111 @@
+package gopdf
+
+import (
+	"testing"
+)
+
+func TestParseRichText(t *testing.T) {
+	runs, err := ParseRichText(`plain <b>bold <i>both</i></b> <color=#ff0000>red</color> <a href="https://example.com">link</a> &lt;x&gt;`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var exp = []TextRun{
+		{Text: "plain "},
+		{Text: "bold ", Style: Bold},
+		{Text: "both", Style: Bold | Italic},
+		{Text: " "},
+		{Text: "red", Color: &RGBColor{R: 255}},
+		{Text: " "},
+		{Text: "link", Link: "https://example.com"},
+		{Text: " <x>"},
+	}
+	if len(runs) != len(exp) {
+		t.Fatalf("expected %d runs, got %d: %+v", len(exp), len(runs), runs)
+	}
+	for i, e := range exp {
+		r := runs[i]
+		if r.Text != e.Text || r.Style != e.Style || r.Link != e.Link {
+			t.Fatalf("run %d: expected %+v, got %+v", i, e, r)
+		}
+		if (r.Color == nil) != (e.Color == nil) || (r.Color != nil && *r.Color != *e.Color) {
+			t.Fatalf("run %d: expected color %v, got %v", i, e.Color, r.Color)
+		}
+	}
+
+	runs, err = ParseRichText("<B>bold</B> <Color=#00f>blue</COLOR>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 3 || runs[0].Style != Bold || runs[2].Color == nil || *runs[2].Color != (RGBColor{B: 255}) {
+		t.Fatalf("unexpected runs of upper case tags: %+v", runs)
+	}
+
+	for _, bad := range []string{"<b>open", "</b>", "<blink>x</blink>", "<color=#12>x</color>", "<b"} {
+		if _, err := ParseRichText(bad); err == nil {
+			t.Fatalf("expected an error for %q", bad)
+		}
+	}
+}
+
+func TestMultiCellRich(t *testing.T) {
+	err := initTesting()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pdf := setupDefaultA4PDF(t)
+	err = pdf.AddTTFFontWithOption("LiberationSerif-Regular", "./test/res/LiberationSerif-Regular.ttf", TtfOption{Style: Bold})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf.AddPage()
+
+	runs, err := ParseRichText("Lorem <b>ipsum</b> dolor <color=#0000ff><u>sit amet</u></color>, consetetur sadipscing elitr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	runs = append(runs, TextRun{Text: " big", Size: 28}, TextRun{Text: "2", Size: 8, BaselineShift: 6})
+
+	base := pdf.saveFontState()
+	lines, _, err := pdf.layoutTextRuns(runs, 150, base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf.restoreFontState(base)
+	if len(lines) < 2 {
+		t.Fatalf("expected the runs to wrap, got %d line(s)", len(lines))
+	}
+	for i, line := range lines {
+		if line.width > 150 {
+			t.Fatalf("line %d is %f wide, more than the cell", i, line.width)
+		}
+	}
+	last := lines[len(lines)-1]
+	if last.ascent <= lines[0].ascent {
+		t.Fatalf("the line with the large run should be taller: %f <= %f", last.ascent, lines[0].ascent)
+	}
+
+	pdf.SetXY(50, 50)
+	err = pdf.MultiCellRich(&Rect{W: 150, H: 500}, runs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pdf.curr.FontSize != 14 || pdf.curr.FontStyle != Regular {
+		t.Fatalf("font state was not restored: size %f style %d", pdf.curr.FontSize, pdf.curr.FontStyle)
+	}
+
+	pdf.SetXY(250, 50)
+	err = pdf.MultiCellRich(&Rect{W: 150, H: 20}, runs)
+	if err != ErrRichTextOverflow {
+		t.Fatalf("expected ErrRichTextOverflow, got %v", err)
+	}
+	if pdf.curr.FontSize != 14 || pdf.curr.FontStyle != Regular {
+		t.Fatalf("font state was not restored after an overflow: size %f style %d", pdf.curr.FontSize, pdf.curr.FontStyle)
+	}
+
+	err = pdf.WritePdf("./test/out/rich_text.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
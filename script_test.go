@@ -0,0 +1,79 @@
+package gopdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSuperscriptSubscript(t *testing.T) {
+	err := initTesting()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf := setupDefaultA4PDF(t)
+	pdf.AddPage()
+
+	normalWidth, err := pdf.MeasureTextWidth("2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	normalHeight, err := pdf.MeasureCellHeightByText("2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = pdf.SetFontWithStyle("LiberationSerif-Regular", Superscript, 14)
+	if err != nil {
+		t.Fatal(err)
+	}
+	superWidth, err := pdf.MeasureTextWidth("2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if superWidth >= normalWidth {
+		t.Fatalf("a superscript should be narrower: %f >= %f", superWidth, normalWidth)
+	}
+	scale, rise := scriptSizeAndRise(pdf.curr.FontISubset, 14, Superscript)
+	if scale <= 0 || scale >= 1 || rise <= 0 {
+		t.Fatalf("unexpected superscript metrics %f %f", scale, rise)
+	}
+	if _, subRise := scriptSizeAndRise(pdf.curr.FontISubset, 14, Subscript); subRise >= 0 {
+		t.Fatalf("a subscript should be lowered, got %f", subRise)
+	}
+
+	err = pdf.Cell(nil, "2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := pdf.getContent().listCache.last().write(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	if out := buf.String(); !strings.Contains(out, FormatFloatTrim(rise)+" Ts") || !strings.Contains(out, "0 Ts\nET") {
+		t.Fatalf("expected the text rise to be set and reset, got:\n%s", out)
+	}
+
+	err = pdf.SetFont("LiberationSerif-Regular", "", 14)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf.SetTextRise(20)
+	raisedHeight, err := pdf.MeasureCellHeightByText("2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raisedHeight <= normalHeight {
+		t.Fatalf("raised text should need a taller line: %f <= %f", raisedHeight, normalHeight)
+	}
+	err = pdf.Cell(nil, "raised")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf.SetTextRise(0)
+
+	err = pdf.WritePdf("./test/out/script.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,192 @@
+package gopdf
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/signintech/gopdf/fontmaker/core"
+)
+
+// ErrCharNotInEncoding is returned when a standard font is used with a character outside of its encoding.
+var ErrCharNotInEncoding = errors.New("char not in the encoding of the standard font")
+
+// standardFont is one of the standard 14 fonts, which are not embedded in the pdf.
+type standardFont struct {
+	name     string
+	encoding map[rune]uint // character codes of the unicode characters
+	builtIn  bool          // the font uses its own encoding instead of WinAnsiEncoding
+}
+
+// standardFontName returns the PostScript name of the standard font of a family and style.
+// Symbol and ZapfDingbats have no bold or italic variant.
+func standardFontName(family string, style int) (string, bool) {
+	bold := style&Bold == Bold
+	italic := style&Italic == Italic
+	switch strings.ToLower(family) {
+	case "helvetica", "courier":
+		name := "Helvetica"
+		if strings.EqualFold(family, "courier") {
+			name = "Courier"
+		}
+		switch {
+		case bold && italic:
+			return name + "-BoldOblique", true
+		case bold:
+			return name + "-Bold", true
+		case italic:
+			return name + "-Oblique", true
+		}
+		return name, true
+	case "times":
+		switch {
+		case bold && italic:
+			return "Times-BoldItalic", true
+		case bold:
+			return "Times-Bold", true
+		case italic:
+			return "Times-Italic", true
+		}
+		return "Times-Roman", true
+	case "symbol":
+		return "Symbol", true
+	case "zapfdingbats":
+		return "ZapfDingbats", true
+	}
+	return "", false
+}
+
+// addStandardFont adds the standard font of a family and style, Helvetica, Times, Courier, Symbol or ZapfDingbats.
+func (gp *GoPdf) addStandardFont(family string, style int) (*SubsetFontObj, error) {
+	name, ok := standardFontName(family, style)
+	if !ok {
+		return nil, ErrMissingFontFamily
+	}
+	font := &standardFont{name: name, encoding: winAnsiEncoding}
+	switch name {
+	case "Symbol":
+		font.encoding, font.builtIn = symbolEncoding, true
+	case "ZapfDingbats":
+		font.encoding, font.builtIn = zapfDingbatsEncoding, true
+	}
+
+	option := defaultTtfFontOption()
+	option.Style = style &^ nonFontStyles
+	subsetFont := new(SubsetFontObj)
+	subsetFont.init(func() *GoPdf {
+		return gp
+	})
+	subsetFont.SetTtfFontOption(option)
+	subsetFont.SetFamily(family)
+	subsetFont.standard = font
+	subsetFont.ttfp = new(core.TTFParser)
+	subsetFont.ttfp.SetFontMetrics(standardFontMetrics[name])
+
+	index := gp.addObj(subsetFont)
+	if gp.indexOfProcSet != -1 {
+		procset := gp.pdfObjs[gp.indexOfProcSet].(*ProcSetObj)
+		procset.Relates = append(procset.Relates, RelateFont{Family: family, IndexOfObj: index, CountOfFont: gp.curr.CountOfFont, Style: option.Style})
+		subsetFont.CountOfFont = gp.curr.CountOfFont
+		gp.curr.CountOfFont++
+	}
+	return subsetFont, nil
+}
+
+// write writes the dictionary of a standard font.
+func (f *standardFont) write(w io.Writer) {
+	io.WriteString(w, "<<\n")
+	io.WriteString(w, "/Type /Font\n")
+	io.WriteString(w, "/Subtype /Type1\n")
+	fmt.Fprintf(w, "/BaseFont /%s\n", f.name)
+	if !f.builtIn {
+		io.WriteString(w, "/Encoding /WinAnsiEncoding\n")
+	}
+	io.WriteString(w, ">>\n")
+}
+
+// winAnsiEncoding maps unicode to WinAnsiEncoding, the control characters are kept so that they can be measured.
+var winAnsiEncoding = func() map[rune]uint {
+	encoding := make(map[rune]uint)
+	for c := rune(0); c < 0x80; c++ {
+		encoding[c] = uint(c)
+	}
+	for c := rune(0xA0); c <= 0xFF; c++ {
+		encoding[c] = uint(c)
+	}
+	for code, r := range map[uint]rune{
+		0x80: '€', 0x82: '‚', 0x83: 'ƒ', 0x84: '„', 0x85: '…', 0x86: '†', 0x87: '‡',
+		0x88: 'ˆ', 0x89: '‰', 0x8A: 'Š', 0x8B: '‹', 0x8C: 'Œ', 0x8E: 'Ž',
+		0x91: '‘', 0x92: '’', 0x93: '“', 0x94: '”', 0x95: '•', 0x96: '–', 0x97: '—',
+		0x98: '˜', 0x99: '™', 0x9A: 'š', 0x9B: '›', 0x9C: 'œ', 0x9E: 'ž', 0x9F: 'Ÿ',
+	} {
+		encoding[r] = code
+	}
+	return encoding
+}()
+
+// symbolEncoding maps unicode to the built-in encoding of the Symbol font.
+var symbolEncoding = func() map[rune]uint {
+	encoding := make(map[rune]uint)
+	for c := rune(0); c < 0x20; c++ {
+		encoding[c] = uint(c)
+	}
+	for _, c := range " !#%&()+,./0123456789:;<=>?[]_{|}" {
+		encoding[c] = uint(c)
+	}
+	codes := map[rune]uint{
+		'∀': 0x22, '∃': 0x24, '∋': 0x27, '∗': 0x2A, '−': 0x2D, '≅': 0x40,
+		'Α': 0x41, 'Β': 0x42, 'Χ': 0x43, 'Δ': 0x44, '∆': 0x44, 'Ε': 0x45, 'Φ': 0x46,
+		'Γ': 0x47, 'Η': 0x48, 'Ι': 0x49, 'ϑ': 0x4A, 'Κ': 0x4B, 'Λ': 0x4C, 'Μ': 0x4D,
+		'Ν': 0x4E, 'Ο': 0x4F, 'Π': 0x50, 'Θ': 0x51, 'Ρ': 0x52, 'Σ': 0x53, 'Τ': 0x54,
+		'Υ': 0x55, 'ς': 0x56, 'Ω': 0x57, 'Ω': 0x57, 'Ξ': 0x58, 'Ψ': 0x59, 'Ζ': 0x5A,
+		'∴': 0x5C, '⊥': 0x5E,
+		'α': 0x61, 'β': 0x62, 'χ': 0x63, 'δ': 0x64, 'ε': 0x65, 'φ': 0x66, 'γ': 0x67,
+		'η': 0x68, 'ι': 0x69, 'ϕ': 0x6A, 'κ': 0x6B, 'λ': 0x6C, 'μ': 0x6D, 'µ': 0x6D,
+		'ν': 0x6E, 'ο': 0x6F, 'π': 0x70, 'θ': 0x71, 'ρ': 0x72, 'σ': 0x73, 'τ': 0x74,
+		'υ': 0x75, 'ϖ': 0x76, 'ω': 0x77, 'ξ': 0x78, 'ψ': 0x79, 'ζ': 0x7A, '∼': 0x7E,
+		'€': 0xA0, 'ϒ': 0xA1, '′': 0xA2, '≤': 0xA3, '⁄': 0xA4, '∞': 0xA5, 'ƒ': 0xA6,
+		'♣': 0xA7, '♦': 0xA8, '♥': 0xA9, '♠': 0xAA, '↔': 0xAB, '←': 0xAC, '↑': 0xAD,
+		'→': 0xAE, '↓': 0xAF, '°': 0xB0, '±': 0xB1, '″': 0xB2, '≥': 0xB3, '×': 0xB4,
+		'∝': 0xB5, '∂': 0xB6, '•': 0xB7, '÷': 0xB8, '≠': 0xB9, '≡': 0xBA, '≈': 0xBB,
+		'…': 0xBC, '⎯': 0xBE, '↵': 0xBF, 'ℵ': 0xC0, 'ℑ': 0xC1, 'ℜ': 0xC2, '℘': 0xC3,
+		'⊗': 0xC4, '⊕': 0xC5, '∅': 0xC6, '∩': 0xC7, '∪': 0xC8, '⊃': 0xC9, '⊇': 0xCA,
+		'⊄': 0xCB, '⊂': 0xCC, '⊆': 0xCD, '∈': 0xCE, '∉': 0xCF, '∠': 0xD0, '∇': 0xD1,
+		'®': 0xD2, '©': 0xD3, '™': 0xD4, '∏': 0xD5, '√': 0xD6, '⋅': 0xD7, '¬': 0xD8,
+		'∧': 0xD9, '∨': 0xDA, '⇔': 0xDB, '⇐': 0xDC, '⇑': 0xDD, '⇒': 0xDE, '⇓': 0xDF,
+		'◊': 0xE0, '〈': 0xE1, '∑': 0xE5, '〉': 0xF1, '∫': 0xF2, '⌠': 0xF3, '⎮': 0xF4,
+		'⌡': 0xF5,
+	}
+	for r, code := range codes {
+		encoding[r] = code
+	}
+	return encoding
+}()
+
+// zapfDingbatsEncoding maps unicode to the built-in encoding of the ZapfDingbats font.
+var zapfDingbatsEncoding = func() map[rune]uint {
+	encoding := make(map[rune]uint)
+	for c := rune(0); c < 0x20; c++ {
+		encoding[c] = uint(c)
+	}
+	encoding[' '] = 0x20
+	ranges := []struct {
+		code  uint
+		first rune
+		count int
+	}{
+		{0x21, '✁', 4}, {0x25, '☎', 1}, {0x26, '✆', 4}, {0x2A, '☛', 1}, {0x2B, '☞', 1},
+		{0x2C, '✌', 1}, {0x2D, '✍', 27}, {0x48, '★', 1}, {0x49, '✩', 35}, {0x6C, '●', 1},
+		{0x6D, '❍', 1}, {0x6E, '■', 1}, {0x6F, '❏', 4}, {0x73, '▲', 1}, {0x74, '▼', 1},
+		{0x75, '◆', 1}, {0x76, '❖', 1}, {0x77, '◗', 1}, {0x78, '❘', 7}, {0x80, '❨', 14},
+		{0xA1, '❡', 7}, {0xA8, '♣', 1}, {0xA9, '♦', 1}, {0xAA, '♥', 1}, {0xAB, '♠', 1},
+		{0xAC, '①', 10}, {0xB6, '❶', 30}, {0xD4, '➔', 1}, {0xD5, '→', 1}, {0xD6, '↔', 2},
+		{0xD8, '➘', 24}, {0xF1, '➱', 14},
+	}
+	for _, r := range ranges {
+		for i := 0; i < r.count; i++ {
+			encoding[r.first+rune(i)] = r.code + uint(i)
+		}
+	}
+	return encoding
+}()
@@ -0,0 +1,110 @@
+package gopdf
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestStandardFonts(t *testing.T) {
+	err := initTesting()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pdf := GoPdf{}
+	pdf.Start(Config{PageSize: *PageSizeA4})
+	pdf.SetCompressLevel(0)
+	pdf.AddPage()
+
+	err = pdf.SetFont("Helvetica", "", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	width, err := pdf.MeasureTextWidth("Hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// H 722, e 556, l 222, l 222, o 556
+	if math.Abs(width-22.78) > 0.001 {
+		t.Fatalf("unexpected width %f", width)
+	}
+	err = pdf.Cell(nil, "Helvetica – “quotes” and €uro")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pdf.Cell(nil, "Ελληνικά"); err != ErrCharNotInEncoding {
+		t.Fatalf("expected ErrCharNotInEncoding, got %v", err)
+	}
+	if _, err := pdf.MeasureTextWidth("漢字"); err != ErrCharNotInEncoding {
+		t.Fatalf("expected ErrCharNotInEncoding, got %v", err)
+	}
+
+	for _, style := range []string{"B", "I", "BI"} {
+		for _, family := range []string{"Times", "Courier"} {
+			if err := pdf.SetFont(family, style, 12); err != nil {
+				t.Fatal(err)
+			}
+			pdf.Br(20)
+			if err := pdf.Cell(nil, family+" "+style); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	courier, err := pdf.MeasureTextWidth("iiii")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(courier-4*0.6*12) > 0.001 {
+		t.Fatalf("expected Courier to be monospaced, got %f", courier)
+	}
+	lines, err := pdf.SplitText("one two three four five six", 60)
+	if err != nil || len(lines) < 2 {
+		t.Fatalf("unexpected split %q, %v", lines, err)
+	}
+
+	err = pdf.SetFont("Symbol", "", 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf.Br(20)
+	err = pdf.Cell(nil, "α + β ≤ ∞")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pdf.SetFont("ZapfDingbats", "", 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf.Br(20)
+	err = pdf.Cell(nil, "✈ ❤ ①")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pdf.SetFont("Unknown", "", 12); err != ErrMissingFontFamily {
+		t.Fatalf("expected ErrMissingFontFamily, got %v", err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := pdf.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, expected := range []string{"/BaseFont /Helvetica\n", "/BaseFont /Times-BoldItalic\n", "/BaseFont /Courier-Oblique\n", "/BaseFont /Symbol\n", "/Subtype /Type1", "/WinAnsiEncoding"} {
+		if !strings.Contains(out, expected) {
+			t.Fatalf("expected %q in the pdf", expected)
+		}
+	}
+	if strings.Contains(out, "/FontFile") {
+		t.Fatal("expected the standard fonts not to be embedded")
+	}
+	if !strings.Contains(out, "[<48656C766574696361") {
+		t.Fatal("expected single byte codes in the content")
+	}
+
+	err = pdf.WritePdf("./test/out/standard_fonts.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -34,7 +34,11 @@ func (s *SubfontDescriptorObj) write(w io.Writer, objID int) error {
 		DesignUnitsToPdf(ttfp.XMax(), ttfp.UnitsPerEm()),
 		DesignUnitsToPdf(ttfp.YMax(), ttfp.UnitsPerEm()),
 	)
-	fmt.Fprintf(w, "/FontFile2 %d 0 R\n", s.indexObjPdfDictionary+1)
+	if ttfp.IsCFF() {
+		fmt.Fprintf(w, "/FontFile3 %d 0 R\n", s.indexObjPdfDictionary+1)
+	} else {
+		fmt.Fprintf(w, "/FontFile2 %d 0 R\n", s.indexObjPdfDictionary+1)
+	}
 	fmt.Fprintf(w, "/FontName /%s\n", CreateEmbeddedFontSubsetName(s.PtrToSubsetFontObj.GetFamily()))
 	fmt.Fprintf(w, "/ItalicAngle %d\n", ttfp.ItalicAngle())
 	io.WriteString(w, "/StemV 0\n")
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
+	"sort"
 
 	"github.com/signintech/gopdf/fontmaker/core"
 )
@@ -16,7 +18,7 @@ var ErrGlyphNotFound = errors.New("glyph not found")
 
 // SubsetFontObj pdf subsetFont object
 type SubsetFontObj struct {
-	ttfp                  core.TTFParser
+	ttfp                  *core.TTFParser // read-only once parsed, shared by the documents of a FontCache or FontContainer
 	Family                string
 	CharacterToGlyphIndex *MapOfCharacterToGlyphIndex
 	CountOfFont           int
@@ -26,6 +28,13 @@ type SubsetFontObj struct {
 	funcKernOverride      FuncKernOverride
 	funcGetRoot           func() *GoPdf
 	addCharsBuff          []rune
+	missingRunes          []rune        // runes of the last AddChars without a glyph in the font
+	color                 *colorFont    // color glyphs of the font, nil if it has none
+	colorGlyphs           []uint        // glyphs of the layers of the color glyphs added, embedded without a character
+	colorImages           map[uint]int  // index of the image object of the bitmap of each color glyph added
+	colorAlphas           map[uint8]int // index of the ExtGState of each alpha of the layers added
+	standard              *standardFont // not nil for the standard 14 fonts, which are not embedded
+	cff                   *cffFont      // outlines of a CFF or CFF2 font, parsed when a glyph bounding box is needed
 }
 
 func (s *SubsetFontObj) init(funcGetRoot func() *GoPdf) {
@@ -36,6 +45,10 @@ func (s *SubsetFontObj) init(funcGetRoot func() *GoPdf) {
 }
 
 func (s *SubsetFontObj) write(w io.Writer, objID int) error {
+	if s.standard != nil {
+		s.standard.write(w)
+		return nil
+	}
 	//me.AddChars("จ")
 	io.WriteString(w, "<<\n")
 	fmt.Fprintf(w, "/BaseFont /%s\n", CreateEmbeddedFontSubsetName(s.Family))
@@ -102,31 +115,48 @@ func (s *SubsetFontObj) KernValueByLeft(left uint) (bool, *core.KernValue) {
 
 // SetTTFByPath set ttf
 func (s *SubsetFontObj) SetTTFByPath(ttfpath string) error {
-	useKerning := s.ttfFontOption.UseKerning
-	s.ttfp.SetUseKerning(useKerning)
-	err := s.ttfp.Parse(ttfpath)
+	data, err := os.ReadFile(ttfpath)
 	if err != nil {
 		return err
 	}
-	return nil
+	return s.SetTTFData(data)
 }
 
 // SetTTFByReader set ttf
 func (s *SubsetFontObj) SetTTFByReader(rd io.Reader) error {
-	useKerning := s.ttfFontOption.UseKerning
-	s.ttfp.SetUseKerning(useKerning)
-	err := s.ttfp.ParseByReader(rd)
+	data, err := io.ReadAll(rd)
 	if err != nil {
 		return err
 	}
-	return nil
+	return s.SetTTFData(data)
 }
 
 // SetTTFData set ttf
 func (s *SubsetFontObj) SetTTFData(data []byte) error {
 	useKerning := s.ttfFontOption.UseKerning
+	s.ttfp = new(core.TTFParser)
 	s.ttfp.SetUseKerning(useKerning)
-	err := s.ttfp.ParseFontData(data)
+	if isWOFF(data) {
+		var err error
+		if data, err = decodeWOFF(data); err != nil {
+			return err
+		}
+	}
+	data, err := selectFontFace(data, s.ttfFontOption)
+	if err != nil {
+		return err
+	}
+	data = addEmptyOutlines(data)
+	if len(s.ttfFontOption.Axes) > 0 {
+		if data, err = instanceVariableFont(data, s.ttfFontOption.Axes); err != nil {
+			return err
+		}
+	}
+	err = s.ttfp.ParseFontData(data)
+	if err != nil {
+		return err
+	}
+	s.color, err = parseColorFont(s.ttfp)
 	if err != nil {
 		return err
 	}
@@ -136,6 +166,7 @@ func (s *SubsetFontObj) SetTTFData(data []byte) error {
 // AddChars add char to map CharacterToGlyphIndex
 func (s *SubsetFontObj) AddChars(txt string) (string, error) {
 	s.addCharsBuff = s.addCharsBuff[:0]
+	s.missingRunes = s.missingRunes[:0]
 	for _, runeValue := range txt {
 		if s.CharacterToGlyphIndex.KeyExists(runeValue) {
 			s.addCharsBuff = append(s.addCharsBuff, runeValue)
@@ -147,6 +178,7 @@ func (s *SubsetFontObj) AddChars(txt string) (string, error) {
 			if s.ttfFontOption.OnGlyphNotFound != nil {
 				s.ttfFontOption.OnGlyphNotFound(runeValue)
 			}
+			s.missingRunes = append(s.missingRunes, runeValue)
 			//start: try to find rune for replace
 			alreadyExists, runeValueReplace, glyphIndexReplace := s.replaceGlyphThatNotFound(runeValue)
 			if !alreadyExists {
@@ -160,10 +192,98 @@ func (s *SubsetFontObj) AddChars(txt string) (string, error) {
 		}
 		s.CharacterToGlyphIndex.Set(runeValue, glyphIndex) // [runeValue] = glyphIndex
 		s.addCharsBuff = append(s.addCharsBuff, runeValue)
+		if err := s.addColorGlyph(glyphIndex); err != nil {
+			return "", err
+		}
 	}
 	return string(s.addCharsBuff), nil
 }
 
+// addColorGlyph adds the glyphs, alphas and bitmap of the color glyph glyphIndex, if it is one.
+func (s *SubsetFontObj) addColorGlyph(glyphIndex uint) error {
+	if !s.color.isColorGlyph(glyphIndex) {
+		return nil
+	}
+	gp := s.funcGetRoot()
+	for _, layer := range s.color.layers[glyphIndex] {
+		if !containsGlyph(s.glyphs(), layer.glyph) {
+			s.colorGlyphs = append(s.colorGlyphs, layer.glyph)
+		}
+		if _, ok := s.colorAlphas[layer.a]; ok || layer.a == 255 || layer.textColor {
+			continue
+		}
+		transparency, err := gp.saveTransparency(&Transparency{Alpha: float64(layer.a) / 255, BlendModeType: NormalBlendMode})
+		if err != nil {
+			return err
+		}
+		if s.colorAlphas == nil {
+			s.colorAlphas = make(map[uint8]int)
+		}
+		s.colorAlphas[layer.a] = transparency.extGStateIndex
+	}
+	if bitmap, ok := s.color.bitmaps[glyphIndex]; ok && len(s.color.layers[glyphIndex]) == 0 {
+		img, err := ImageHolderByBytes(bitmap.png)
+		if err != nil {
+			return err
+		}
+		index := gp.cachedImageIndex(img)
+		if index == -1 {
+			if index, _, err = gp.addImageObj(img, nil, false); err != nil {
+				return err
+			}
+		}
+		if s.colorImages == nil {
+			s.colorImages = make(map[uint]int)
+		}
+		s.colorImages[glyphIndex] = index
+	}
+	return nil
+}
+
+// fontCharacters returns the characters of the cmap of the font, the smallest one of each glyph, and their glyphs by glyph.
+func (s *SubsetFontObj) fontCharacters() ([]rune, []uint) {
+	runes := make(map[uint]rune)
+	add := func(r rune, glyph uint) {
+		if old, ok := runes[glyph]; glyph != 0 && (!ok || r < old) {
+			runes[glyph] = r
+		}
+	}
+	for c, glyph := range s.ttfp.Chars() {
+		add(rune(c), glyph)
+	}
+	for _, group := range s.ttfp.GroupingTables() {
+		for c := group.StartCharCode; c <= group.EndCharCode; c++ {
+			add(rune(c), group.GlyphID+c-group.StartCharCode)
+		}
+	}
+	glyphs := make([]uint, 0, len(runes))
+	for glyph := range runes {
+		glyphs = append(glyphs, glyph)
+	}
+	sort.Slice(glyphs, func(i, j int) bool { return glyphs[i] < glyphs[j] })
+	chars := make([]rune, len(glyphs))
+	for i, glyph := range glyphs {
+		chars[i] = runes[glyph]
+	}
+	return chars, glyphs
+}
+
+// glyphs returns the glyphs of the characters added and of the layers of their color glyphs.
+func (s *SubsetFontObj) glyphs() []uint {
+	glyphs := append([]uint(nil), s.CharacterToGlyphIndex.AllVals()...)
+	return append(glyphs, s.colorGlyphs...)
+}
+
+// containsGlyph returns true if glyphs contains glyph.
+func containsGlyph(glyphs []uint, glyph uint) bool {
+	for _, g := range glyphs {
+		if g == glyph {
+			return true
+		}
+	}
+	return false
+}
+
 /*
 //AddChars add char to map CharacterToGlyphIndex
 func (s *SubsetFontObj) AddChars(txt string) error {
@@ -282,6 +402,14 @@ func (s *SubsetFontObj) charCodeToGlyphIndexFormat4(r rune) (uint, error) {
 
 // CharCodeToGlyphIndex gets glyph index from char code.
 func (s *SubsetFontObj) CharCodeToGlyphIndex(r rune) (uint, error) {
+	if s.standard != nil {
+		// the glyphs of the standard fonts are indexed by their character code
+		code, ok := s.standard.encoding[r]
+		if !ok {
+			return 0, ErrCharNotInEncoding
+		}
+		return code, nil
+	}
 	value := uint64(r)
 	if value <= 0xFFFF {
 		gIndex, err := s.charCodeToGlyphIndexFormat4(r)
@@ -297,6 +425,15 @@ func (s *SubsetFontObj) CharCodeToGlyphIndex(r rune) (uint, error) {
 	return gIndex, nil
 }
 
+// writeGlyph writes the code of a glyph in a hexadecimal string of the font.
+func (s *SubsetFontObj) writeGlyph(w io.Writer, glyphIndex uint) {
+	if s.standard != nil {
+		fmt.Fprintf(w, "%02X", glyphIndex)
+		return
+	}
+	fmt.Fprintf(w, "%04X", glyphIndex)
+}
+
 // GlyphIndexToPdfWidth gets width from glyphIndex.
 func (s *SubsetFontObj) GlyphIndexToPdfWidth(glyphIndex uint) uint {
 
@@ -315,7 +452,7 @@ func (s *SubsetFontObj) GlyphIndexToPdfWidth(glyphIndex uint) uint {
 
 // GetTTFParser gets TTFParser.
 func (s *SubsetFontObj) GetTTFParser() *core.TTFParser {
-	return &s.ttfp
+	return s.ttfp
 }
 
 // GetUnderlineThickness underlineThickness.
@@ -0,0 +1,46 @@
+package gopdf
+
+import "math"
+
+// syntheticBoldStroke is the line width of the stroke of synthetic bold relative to the font size.
+const syntheticBoldStroke = 1.0 / 30
+
+// syntheticItalicSkew is the horizontal skew of synthetic italic, a slant of 12 degrees.
+var syntheticItalicSkew = math.Tan(12 * math.Pi / 180)
+
+// syntheticStyles returns the Bold and Italic styles of style that f draws synthetically.
+func syntheticStyles(f *SubsetFontObj, style int) int {
+	if f == nil || !f.ttfFontOption.SyntheticStyles {
+		return 0
+	}
+	return style & (Bold | Italic) &^ f.ttfFontOption.Style
+}
+
+// syntheticStyleFont returns the font of family with SyntheticStyles having the most of the Bold and Italic styles of style,
+// nil if there is none. The styles it does not have are drawn synthetically.
+func (gp *GoPdf) syntheticStyleFont(family string, style int) *SubsetFontObj {
+	count := func(style int) int {
+		n := 0
+		for _, s := range []int{Bold, Italic} {
+			if style&s != 0 {
+				n++
+			}
+		}
+		return n
+	}
+	var found *SubsetFontObj
+	for _, obj := range gp.pdfObjs {
+		sub, ok := obj.(*SubsetFontObj)
+		if !ok || sub.GetFamily() != family || !sub.ttfFontOption.SyntheticStyles {
+			continue
+		}
+		fontStyle := sub.ttfFontOption.Style &^ nonFontStyles
+		if fontStyle&^style != 0 {
+			continue
+		}
+		if found == nil || count(fontStyle) > count(found.ttfFontOption.Style&^nonFontStyles) {
+			found = sub
+		}
+	}
+	return found
+}
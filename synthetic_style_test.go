@@ -0,0 +1,94 @@
+package gopdf
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestSyntheticStyles(t *testing.T) {
+	err := initTesting()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pdf := GoPdf{}
+	pdf.Start(Config{PageSize: *PageSizeA4})
+	pdf.SetCompressLevel(0)
+	pdf.AddPage()
+	err = pdf.AddTTFFont("regular-only", "./test/res/LiberationSerif-Regular.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pdf.SetFont("regular-only", "B", 14); err != ErrMissingFontFamily {
+		t.Fatalf("expected ErrMissingFontFamily without synthetic styles, got %v", err)
+	}
+	err = pdf.AddTTFFontWithOption("synthetic", "./test/res/LiberationSerif-Regular.ttf", TtfOption{SyntheticStyles: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text := "Hello"
+	widths := map[string]float64{}
+	for _, style := range []string{"", "B", "I", "BI"} {
+		err = pdf.SetFont("synthetic", style, 14)
+		if err != nil {
+			t.Fatalf("%q: %v", style, err)
+		}
+		err = pdf.Cell(nil, text+" "+style)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pdf.Br(20)
+		if widths[style], err = pdf.MeasureTextWidth(text); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// each glyph is widened by the stroke of bold, measured in thousandths of the font size, italic does not change the width
+	stroke := 14 * syntheticBoldStroke
+	if math.Abs(widths["B"]-widths[""]-5*stroke) > 5*0.014 || widths["I"] != widths[""] || widths["BI"] != widths["B"] {
+		t.Fatalf("unexpected widths %v", widths)
+	}
+
+	err = pdf.SetFont("synthetic", "BI", 14)
+	if err != nil {
+		t.Fatal(err)
+	}
+	glyphs, err := pdf.MeasureGlyphs("H")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pdf.SetFont("synthetic", "", 14)
+	if err != nil {
+		t.Fatal(err)
+	}
+	regular, err := pdf.MeasureGlyphs("H")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, r := glyphs[0].BBox, regular[0].BBox; b.Top >= r.Top || b.Right <= r.Right+stroke/2 ||
+		math.Abs(glyphs[0].Advance-regular[0].Advance-stroke) > 1e-9 {
+		t.Fatalf("unexpected bold italic glyph %+v, regular %+v", glyphs[0], regular[0])
+	}
+
+	var buff bytes.Buffer
+	_, err = pdf.WriteTo(&buff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := buff.String()
+	skew := FormatFloatTrim(syntheticItalicSkew)
+	for _, want := range []string{"q\nBT\n", "0 G\n0.467 w 2 Tr\n", "1 0 " + skew + " 1 ", "ET\nQ\n"} {
+		if !strings.Contains(content, want) {
+			t.Fatalf("expected %q in the pdf", want)
+		}
+	}
+	if n := strings.Count(content, "2 Tr"); n != 2 {
+		t.Fatalf("expected 2 bold texts, got %d", n)
+	}
+	err = pdf.WritePdf("./test/out/synthetic_styles.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,198 @@
+package gopdf
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// TabAlign is the alignment of the text following a tab character at a TabStop.
+type TabAlign int
+
+const (
+	// TabLeft starts the text at the tab stop.
+	TabLeft TabAlign = iota
+	// TabRight ends the text at the tab stop.
+	TabRight
+	// TabCenter centers the text on the tab stop.
+	TabCenter
+	// TabDecimal aligns the decimal separator of the text on the tab stop.
+	TabDecimal
+)
+
+// TabStop is a position where the text following a tab character ('\t') is aligned by Cell and MultiCell.
+type TabStop struct {
+	Position         float64  // distance from the left of the cell
+	Align            TabAlign // TabLeft, TabRight, TabCenter or TabDecimal
+	Leader           string   // text repeated to fill the space before the tab stop, e.g. "."
+	DecimalSeparator rune     // separator aligned by TabDecimal, '.' if not set
+}
+
+// SetTabStops : set the tab stops used to expand the tab characters of Cell, CellWithOption and MultiCell.
+// Without tab stops the tab characters are drawn like any other character.
+//
+//	Usage:
+//	pdf.SetTabStops(gopdf.TabStop{Position: 200, Align: gopdf.TabRight, Leader: "."})
+//	pdf.Cell(nil, "Chapter 3\t42")
+func (gp *GoPdf) SetTabStops(stops ...TabStop) {
+	gp.curr.tabStops = append([]TabStop(nil), stops...)
+	sort.SliceStable(gp.curr.tabStops, func(i, j int) bool {
+		return gp.curr.tabStops[i].Position < gp.curr.tabStops[j].Position
+	})
+}
+
+// tabbedSegment is a part of a line between tab characters, placed at x relative to the start of the line.
+type tabbedSegment struct {
+	text   string
+	x      float64
+	width  float64
+	leader string
+	// leaderStart and leaderEnd delimit the space to fill with the leader
+	leaderStart, leaderEnd float64
+}
+
+// hasTabStops reports whether the tab characters of text must be expanded.
+func (gp *GoPdf) hasTabStops(text string) bool {
+	return len(gp.curr.tabStops) > 0 && strings.ContainsRune(text, '\t')
+}
+
+// layoutTabs places the parts of text between tab characters at the tab stops, in document units.
+func (gp *GoPdf) layoutTabs(text string) ([]tabbedSegment, float64, error) {
+	var segments []tabbedSegment
+	pen := 0.0
+	for i, part := range strings.Split(text, "\t") {
+		width, err := gp.MeasureTextWidth(part)
+		if err != nil {
+			return nil, 0, err
+		}
+		segment := tabbedSegment{text: part, x: pen, width: width}
+		if i > 0 {
+			if err := gp.placeAtTabStop(&segment, pen); err != nil {
+				return nil, 0, err
+			}
+		}
+		segments = append(segments, segment)
+		pen = segment.x + width
+	}
+	return segments, pen, nil
+}
+
+// placeAtTabStop moves segment to the first tab stop after pen.
+func (gp *GoPdf) placeAtTabStop(segment *tabbedSegment, pen float64) error {
+	var stop *TabStop
+	for i := range gp.curr.tabStops {
+		if gp.curr.tabStops[i].Position > pen {
+			stop = &gp.curr.tabStops[i]
+			break
+		}
+	}
+	if stop == nil {
+		// past the last tab stop the tab is as wide as a space
+		space, err := gp.MeasureTextWidth(" ")
+		if err != nil {
+			return err
+		}
+		segment.x = pen + space
+		return nil
+	}
+
+	x := stop.Position
+	switch stop.Align {
+	case TabRight:
+		x -= segment.width
+	case TabCenter:
+		x -= segment.width / 2
+	case TabDecimal:
+		separator := stop.DecimalSeparator
+		if separator == 0 {
+			separator = '.'
+		}
+		integer := segment.text
+		if i := strings.IndexRune(integer, separator); i >= 0 {
+			integer = integer[:i]
+		}
+		width, err := gp.MeasureTextWidth(integer)
+		if err != nil {
+			return err
+		}
+		x -= width
+	}
+	if x < pen {
+		x = pen
+	}
+	segment.x = x
+	segment.leader = stop.Leader
+	segment.leaderStart = pen
+	segment.leaderEnd = x
+	return nil
+}
+
+// cellWithTabs draws a cell whose text contains tab characters, aligning the parts of the text at the tab stops.
+func (gp *GoPdf) cellWithTabs(rectangle *Rect, text string, opt CellOption) error {
+	x, y := gp.GetX(), gp.GetY()
+	segments, width, err := gp.layoutTabs(text)
+	if err != nil {
+		return err
+	}
+
+	var cell Rect
+	if rectangle != nil {
+		cell = *rectangle
+	} else {
+		height, err := gp.MeasureCellHeightByText(text)
+		if err != nil {
+			return err
+		}
+		cell = Rect{W: width, H: height}
+	}
+
+	part := opt
+	part.Align = opt.Align &^ (Left | Right | Center | Justify)
+	part.Align |= Left
+	part.Border = 0
+	part.Float = Right
+	for _, s := range segments {
+		if s.leader != "" {
+			if err := gp.drawTabLeader(x, y, cell.H, s, part); err != nil {
+				return err
+			}
+		}
+		if s.text == "" {
+			continue
+		}
+		gp.SetXY(x+s.x, y)
+		if err := gp.CellWithOption(&Rect{W: s.width, H: cell.H}, s.text, part); err != nil {
+			return err
+		}
+	}
+
+	// the cell itself draws the borders and moves the current position
+	gp.SetXY(x, y)
+	return gp.CellWithOption(&cell, "", opt)
+}
+
+// drawTabLeader fills the space before a segment with its leader.
+// The leaders are aligned on a grid so that they line up on consecutive lines.
+func (gp *GoPdf) drawTabLeader(x, y, height float64, s tabbedSegment, opt CellOption) error {
+	leaderWidth, err := gp.MeasureTextWidth(s.leader)
+	if err != nil || leaderWidth <= 0 {
+		return err
+	}
+	first := math.Ceil(s.leaderStart/leaderWidth) * leaderWidth
+	count := int(math.Floor((s.leaderEnd - first) / leaderWidth))
+	if count <= 0 {
+		return nil
+	}
+	gp.SetXY(x+first, y)
+	return gp.CellWithOption(&Rect{W: float64(count) * leaderWidth, H: height}, strings.Repeat(s.leader, count), opt)
+}
+
+// runeAdvance returns the width added to line, whose width is lineWidth, by appending r.
+// The width of a tab depends on its position in the line when tab stops are set.
+func (gp *GoPdf) runeAdvance(line []rune, lineWidth float64, r rune) (float64, error) {
+	if r == '\t' && len(gp.curr.tabStops) > 0 {
+		width, err := gp.MeasureTextWidth(string(line) + "\t")
+		return width - lineWidth, err
+	}
+	return gp.MeasureTextWidth(string(r))
+}
@@ -0,0 +1,96 @@
+package gopdf
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestTabStops(t *testing.T) {
+	err := initTesting()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pdf := setupDefaultA4PDF(t)
+	pdf.AddPage()
+	err = pdf.SetFont("LiberationSerif-Regular", "", 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pdf.SetTabStops(
+		TabStop{Position: 300, Align: TabRight, Leader: "."},
+		TabStop{Position: 100, Align: TabLeft},
+		TabStop{Position: 400, Align: TabDecimal},
+	)
+	pdf.SetXY(50, 50)
+	err = pdf.Cell(&Rect{W: 500, H: 20}, "3\tChapter\t42\t12.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x := pdf.GetX(); math.Abs(x-550) > 0.01 {
+		t.Fatalf("expected the cell to move to 550, got %f", x)
+	}
+
+	texts := map[string]float64{}
+	for _, c := range pdf.getContent().listCache.caches {
+		if text, ok := c.(*cacheContentText); ok && text.text != "" {
+			texts[text.text] = text.x
+		}
+	}
+	width := func(s string) float64 {
+		w, err := pdf.MeasureTextWidth(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return w
+	}
+	expected := map[string]float64{
+		"3":       50,
+		"Chapter": 150,
+		"42":      350 - width("42"),
+		"12.5":    450 - width("12"),
+	}
+	for text, x := range expected {
+		if math.Abs(texts[text]-x) > 0.01 {
+			t.Fatalf("expected %q at %f, got %f", text, x, texts[text])
+		}
+	}
+	var leader string
+	for text := range texts {
+		if strings.Trim(text, ".") == "" {
+			leader = text
+		}
+	}
+	if len(leader) < 10 || texts[leader] < 150+width("Chapter") {
+		t.Fatalf("unexpected leader %q at %f", leader, texts[leader])
+	}
+
+	if w := width("a\tb"); math.Abs(w-100-width("b")) > 0.01 {
+		t.Fatalf("expected the tab to be expanded when measuring, got %f", w)
+	}
+	lines, err := pdf.SplitText("Introduction\t1", 150)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 1 {
+		t.Fatalf("expected the text to fit in one line, got %q", lines)
+	}
+
+	pdf.SetXY(50, 100)
+	err = pdf.MultiCell(&Rect{W: 350, H: 100}, "Introduction\t1\tthe end")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pdf.SetTabStops()
+	if w := width("a\tb"); w >= 100 {
+		t.Fatalf("expected the tab stops to be cleared, got width %f", w)
+	}
+
+	err = pdf.WritePdf("./test/out/tab_stops.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
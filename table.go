@@ -19,11 +19,13 @@ type BorderStyle struct {
 
 // Defines the style for a cell, including border, fill, text, and font properties
 type CellStyle struct {
-	BorderStyle BorderStyle // Border style for the cell
-	FillColor   RGBColor    // Background color of the cell
-	TextColor   RGBColor    // Color of the text in the cell
-	Font        string      // Font name for the cell text
-	FontSize    float64     // Font size for the cell text
+	BorderStyle BorderStyle     // Border style for the cell
+	FillColor   RGBColor        // Background color of the cell
+	TextColor   RGBColor        // Color of the text in the cell
+	Font        string          // Font name for the cell text
+	FontSize    float64         // Font size for the cell text
+	FontStyle   string          // Font style for the cell text, e.g. "U", "S" or "O" (used with Font)
+	Decoration  *TextDecoration // How the underline, strikethrough and overline of the cell text are drawn
 }
 
 type RowCell struct {
@@ -301,7 +303,10 @@ func (t *tableLayout) drawCell(
 	// Set the text color and font
 	t.pdf.SetTextColor(style.TextColor.R, style.TextColor.G, style.TextColor.B)
 	if style.Font != "" {
-		t.pdf.SetFont(style.Font, "", style.FontSize)
+		t.pdf.SetFont(style.Font, style.FontStyle, style.FontSize)
+	}
+	if style.Decoration != nil {
+		textOption.Decoration = style.Decoration
 	}
 
 	// Draw the cell content
@@ -0,0 +1,240 @@
+package gopdf
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// DecorationLineStyle is the shape of the lines drawn by TextDecoration.
+type DecorationLineStyle int
+
+const (
+	// DecorationSolid draws a single straight line.
+	DecorationSolid DecorationLineStyle = iota
+	// DecorationDouble draws two parallel lines.
+	DecorationDouble
+	// DecorationWavy draws a wavy line.
+	DecorationWavy
+)
+
+// TextDecoration configures the lines drawn by the Underline, Strikethrough and Overline font styles.
+type TextDecoration struct {
+	Color              *RGBColor           // color of the lines, nil draws them in the text color
+	Thickness          float64             // thickness of the lines, 0 uses the thickness defined by the font
+	Dash               []float64           // lengths of alternating dashes and gaps, nil draws a continuous line
+	Style              DecorationLineStyle // DecorationSolid, DecorationDouble or DecorationWavy
+	SkipTrailingSpaces bool                // do not decorate the spaces at the end of the text
+}
+
+func (d *TextDecoration) equal(other *TextDecoration) bool {
+	if d == nil || other == nil {
+		return d == other
+	}
+	if (d.Color == nil) != (other.Color == nil) || (d.Color != nil && *d.Color != *other.Color) {
+		return false
+	}
+	if d.Thickness != other.Thickness || d.Style != other.Style || d.SkipTrailingSpaces != other.SkipTrailingSpaces {
+		return false
+	}
+	if len(d.Dash) != len(other.Dash) {
+		return false
+	}
+	for i := range d.Dash {
+		if d.Dash[i] != other.Dash[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// SetTextDecoration : set how the Underline, Strikethrough and Overline styles are drawn by Text, Cell and MultiCell,
+// CellOption.Decoration takes precedence. Use nil to draw the default underline.
+//
+//	Usage:
+//	pdf.SetFont("LiberationSerif-Regular", "US", 14)
+//	pdf.SetTextDecoration(&gopdf.TextDecoration{Color: &gopdf.RGBColor{R: 255}, Style: gopdf.DecorationWavy})
+//	pdf.Cell(nil, "misspelled")
+func (gp *GoPdf) SetTextDecoration(decoration *TextDecoration) {
+	gp.curr.textDecoration = decoration
+}
+
+// textDecorationInPoints returns a copy of decoration with its lengths converted to points.
+func (gp *GoPdf) textDecorationInPoints(decoration *TextDecoration) *TextDecoration {
+	if decoration == nil {
+		return nil
+	}
+	d := *decoration
+	d.Thickness = gp.UnitsToPoints(d.Thickness)
+	d.Dash = make([]float64, len(decoration.Dash))
+	for i, l := range decoration.Dash {
+		d.Dash[i] = gp.UnitsToPoints(l)
+	}
+	return &d
+}
+
+// decorate draws the lines of the Underline, Strikethrough and Overline styles.
+// x and y are the start of the baseline of the text.
+func (c *cacheContentText) decorate(w io.Writer, x, y float64) error {
+	if c.fontStyle&decorationStyles == 0 {
+		return nil
+	}
+	if c.fontStyle&Underline == Underline && c.decoration == nil {
+		if err := c.underline(w); err != nil {
+			return err
+		}
+	}
+	if c.fontStyle&(Strikethrough|Overline) == 0 && c.decoration == nil {
+		return nil
+	}
+
+	decoration := c.decoration
+	if decoration == nil {
+		decoration = &TextDecoration{}
+	}
+	width := c.textWidthPdfUnit
+	if decoration.SkipTrailingSpaces {
+		_, _, trimmed, err := createContentWithState(c.fontSubset, strings.TrimRight(c.text, " "), c.fontSize, c.textState(), nil)
+		if err != nil {
+			return err
+		}
+		width = trimmed
+	}
+	if width <= 0 {
+		return nil
+	}
+
+	ttfp := c.fontSubset.ttfp
+	toPt := func(v int) float64 {
+		return float64(v) * c.fontSize / float64(ttfp.UnitsPerEm())
+	}
+	fontThickness := c.fontSubset.GetUnderlineThicknessPx(c.fontSize)
+	if c.cellOpt.CoefUnderlineThickness != 0 {
+		fontThickness *= c.cellOpt.CoefUnderlineThickness
+	}
+
+	if decoration.Color != nil {
+		fmt.Fprintf(w, "q %.3f %.3f %.3f %s\n", float64(decoration.Color.R)/255, float64(decoration.Color.G)/255, float64(decoration.Color.B)/255, colorTypeFillRGB)
+	}
+
+	if c.fontStyle&Underline == Underline && c.decoration != nil {
+		thickness := decoration.Thickness
+		if thickness == 0 {
+			thickness = fontThickness
+		}
+		top := y + c.fontSubset.GetUnderlinePositionPx(c.fontSize)
+		if c.cellOpt.CoefUnderlinePosition != 0 {
+			top = y + c.fontSubset.GetUnderlinePositionPx(c.fontSize)*c.cellOpt.CoefUnderlinePosition
+		}
+		writeDecorationLine(w, decoration, x, width, top, thickness, -1)
+	}
+	if c.fontStyle&Strikethrough == Strikethrough {
+		thickness := decoration.Thickness
+		if thickness == 0 {
+			thickness = toPt(ttfp.StrikeoutSize())
+		}
+		if thickness <= 0 {
+			thickness = fontThickness
+		}
+		top := y + toPt(ttfp.StrikeoutPosition())
+		if ttfp.StrikeoutPosition() == 0 {
+			top = y + toPt(ttfp.XHeight())/2 + thickness/2
+		}
+		writeDecorationLine(w, decoration, x, width, top, thickness, 0)
+	}
+	if c.fontStyle&Overline == Overline {
+		thickness := decoration.Thickness
+		if thickness == 0 {
+			thickness = fontThickness
+		}
+		top := y + c.calTypoAscender() + thickness
+		writeDecorationLine(w, decoration, x, width, top, thickness, 1)
+	}
+
+	if decoration.Color != nil {
+		io.WriteString(w, "Q\n")
+	}
+	return nil
+}
+
+// writeDecorationLine fills a line from x to x+width whose top edge is at top.
+// The second line of DecorationDouble is drawn below the first one when direction is -1,
+// above it when direction is 1 and both lines are centered on the position when direction is 0.
+func writeDecorationLine(w io.Writer, d *TextDecoration, x, width, top, thickness float64, direction int) {
+	var tops []float64
+	switch d.Style {
+	case DecorationDouble:
+		switch direction {
+		case -1:
+			tops = []float64{top, top - 2*thickness}
+		case 1:
+			tops = []float64{top, top + 2*thickness}
+		default:
+			tops = []float64{top + thickness, top - thickness}
+		}
+	default:
+		tops = []float64{top}
+	}
+
+	for _, segment := range dashSegments(x, width, d.Dash) {
+		for _, t := range tops {
+			if d.Style == DecorationWavy {
+				writeWave(w, segment[0], segment[1], t-thickness/2, thickness)
+			} else {
+				fmt.Fprintf(w, "%0.2f %0.2f %0.2f %0.2f re f\n", segment[0], t-thickness, segment[1]-segment[0], thickness)
+			}
+		}
+	}
+}
+
+// dashSegments splits the line from x to x+width into the dashes of the pattern.
+func dashSegments(x, width float64, dash []float64) [][2]float64 {
+	total := 0.0
+	for _, l := range dash {
+		total += l
+	}
+	if total <= 0 {
+		return [][2]float64{{x, x + width}}
+	}
+
+	var segments [][2]float64
+	pos := x
+	end := x + width
+	for i := 0; pos < end; i++ {
+		l := dash[i%len(dash)]
+		if i%2 == 0 && l > 0 {
+			segments = append(segments, [2]float64{pos, math.Min(pos+l, end)})
+		}
+		pos += l
+	}
+	return segments
+}
+
+// writeWave fills a wavy band of the given thickness centered on y, from x1 to x2.
+func writeWave(w io.Writer, x1, x2, y, thickness float64) {
+	amplitude := thickness * 1.5
+	period := math.Max(thickness*8, 2)
+	step := period / 8
+	var upper, lower []Point
+	for px := x1; ; px += step {
+		if px > x2 {
+			px = x2
+		}
+		dy := amplitude * math.Sin(2*math.Pi*(px-x1)/period)
+		upper = append(upper, Point{X: px, Y: y + dy + thickness/2})
+		lower = append(lower, Point{X: px, Y: y + dy - thickness/2})
+		if px == x2 {
+			break
+		}
+	}
+
+	fmt.Fprintf(w, "%0.2f %0.2f m\n", upper[0].X, upper[0].Y)
+	for _, p := range upper[1:] {
+		fmt.Fprintf(w, "%0.2f %0.2f l\n", p.X, p.Y)
+	}
+	for i := len(lower) - 1; i >= 0; i-- {
+		fmt.Fprintf(w, "%0.2f %0.2f l\n", lower[i].X, lower[i].Y)
+	}
+	io.WriteString(w, "h f\n")
+}
@@ -0,0 +1,85 @@
+package gopdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTextDecoration(t *testing.T) {
+	err := initTesting()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if style := getConvertedStyle("BUSO"); style != Bold|Underline|Strikethrough|Overline {
+		t.Fatalf("unexpected style %d", style)
+	}
+
+	pdf := setupDefaultA4PDF(t)
+	pdf.AddPage()
+	err = pdf.SetFont("LiberationSerif-Regular", "S", 14)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pdf.Cell(nil, "struck through")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	cache := pdf.getContent().listCache.last().(*cacheContentText)
+	if err := cache.write(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(buf.String(), "re f") != 1 {
+		t.Fatalf("expected one strikethrough line, got:\n%s", buf.String())
+	}
+
+	decoration := &TextDecoration{
+		Color:              &RGBColor{R: 255},
+		Dash:               []float64{2, 1},
+		Style:              DecorationDouble,
+		SkipTrailingSpaces: true,
+	}
+	err = pdf.SetFont("LiberationSerif-Regular", "UO", 14)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf.SetXY(20, 100)
+	err = pdf.CellWithOption(&Rect{W: 200, H: 20}, "dashed   ", CellOption{Align: Center | Middle, Decoration: decoration})
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	cache = pdf.getContent().listCache.last().(*cacheContentText)
+	if err := cache.write(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "q 1.000 0.000 0.000 rg") || !strings.HasSuffix(out, "Q\n") {
+		t.Fatalf("expected the lines in their own color, got:\n%s", out)
+	}
+	// two decorations with two lines each, split in dashes of 3pt
+	dashes := strings.Count(out, "re f")
+	if dashes < 8 || dashes%4 != 0 {
+		t.Fatalf("unexpected number of dashes %d:\n%s", dashes, out)
+	}
+
+	pdf.SetTextDecoration(&TextDecoration{Style: DecorationWavy})
+	err = pdf.SetFont("LiberationSerif-Regular", "U", 14)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf.SetXY(20, 150)
+	err = pdf.MultiCell(&Rect{W: 100, H: 100}, "wavy underlined text in a multi cell")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf.SetTextDecoration(nil)
+
+	err = pdf.WritePdf("./test/out/text_decoration.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,142 @@
+package gopdf
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// TextFrame is a box of text on a page that can be linked to a next frame where the text that does not fit continues.
+type TextFrame struct {
+	Page        int          // page number of the frame, 0 for the current page
+	X, Y        float64      // upper-left corner of the frame
+	W, H        float64      // size of the frame
+	LineHeight  float64      // height of a line, 0 uses the height of the current font
+	Align       int          // alignment of the lines, Left, Center, Right or Justify
+	BreakOption *BreakOption // how lines are broken, DefaultBreakOption if nil
+
+	next *TextFrame
+}
+
+// NewTextFrame : create a frame on a page, 0 for the current page.
+//
+//	Usage:
+//	first := gopdf.NewTextFrame(1, 50, 50, 200, 300)
+//	first.Link(gopdf.NewTextFrame(2, 300, 400, 200, 300))
+//	remainder, err := pdf.FillTextFrame(first, text)
+func NewTextFrame(page int, x, y, w, h float64) *TextFrame {
+	return &TextFrame{Page: page, X: x, Y: y, W: w, H: h}
+}
+
+// Link : continue the text that does not fit in the frame in next, and return next to link it in turn.
+func (f *TextFrame) Link(next *TextFrame) *TextFrame {
+	f.next = next
+	return next
+}
+
+// Next returns the frame linked after f, nil if f is the last frame of its chain.
+func (f *TextFrame) Next() *TextFrame {
+	return f.next
+}
+
+// FillTextFrame : fill the chain of frames starting at frame with text,
+// and return the text that does not fit in the last frame.
+// The current page and position are kept.
+func (gp *GoPdf) FillTextFrame(frame *TextFrame, text string) (string, error) {
+	indexOfContent, indexOfPageObj, pageSize := gp.indexOfContent, gp.curr.IndexOfPageObj, gp.curr.pageSize
+	x, y := gp.GetX(), gp.GetY()
+	defer func() {
+		gp.indexOfContent, gp.curr.IndexOfPageObj, gp.curr.pageSize = indexOfContent, indexOfPageObj, pageSize
+		gp.SetXY(x, y)
+	}()
+
+	for f := frame; f != nil && text != ""; f = f.next {
+		if f.Page != 0 {
+			if err := gp.SetPage(f.Page); err != nil {
+				return text, err
+			}
+		} else {
+			gp.indexOfContent, gp.curr.IndexOfPageObj, gp.curr.pageSize = indexOfContent, indexOfPageObj, pageSize
+		}
+		remainder, err := gp.fillTextFrame(f, text)
+		if err != nil {
+			return text, err
+		}
+		text = remainder
+	}
+	return text, nil
+}
+
+// fillTextFrame draws the lines of text that fit in a frame and returns the rest of the text.
+func (gp *GoPdf) fillTextFrame(f *TextFrame, text string) (string, error) {
+	breakOption := f.BreakOption
+	if breakOption == nil {
+		breakOption = &DefaultBreakOption
+	}
+	lineHeight := f.LineHeight
+	if lineHeight == 0 {
+		var err error
+		if lineHeight, err = gp.fontLineHeight(); err != nil {
+			return text, err
+		}
+	}
+
+	lines, paragraphEnds, err := gp.splitParagraphs(text, f.W, breakOption)
+	if err != nil {
+		return text, err
+	}
+	count := int(math.Floor(f.H/lineHeight + 1e-9))
+	if count >= len(lines) {
+		return "", gp.drawFrameLines(f, lines, paragraphEnds, lineHeight)
+	}
+	if count <= 0 {
+		return text, nil
+	}
+	if err := gp.drawFrameLines(f, lines[:count], paragraphEnds[:count], lineHeight); err != nil {
+		return text, err
+	}
+	rest := text[consumedLength(text, lines[:count]):]
+	return strings.TrimLeft(rest, " \n"), nil
+}
+
+// drawFrameLines draws lines from the top of a frame.
+func (gp *GoPdf) drawFrameLines(f *TextFrame, lines []string, paragraphEnds []bool, lineHeight float64) error {
+	for i, line := range lines {
+		gp.SetXY(f.X, f.Y+float64(i)*lineHeight)
+		if err := gp.alignedLine(&Rect{W: f.W, H: lineHeight}, line, paragraphEnds[i], f.Align); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// consumedLength returns the length of the beginning of text that was split into lines.
+// The spaces and new lines dropped between the lines, the invisible characters removed from them
+// and the separators added at their end are taken into account.
+func consumedLength(text string, lines []string) int {
+	pos := 0
+	for _, line := range lines {
+		for pos < len(text) {
+			r, size := utf8.DecodeRuneInString(text[pos:])
+			if (r != ' ' && r != '\n') || strings.HasPrefix(line, string(r)) {
+				break
+			}
+			pos += size
+		}
+		for _, want := range line {
+			for pos < len(text) {
+				r, size := utf8.DecodeRuneInString(text[pos:])
+				if r == want {
+					pos += size
+					break
+				}
+				if !isInvisibleFormat(r) {
+					// want was added by the line break, like a hyphen
+					break
+				}
+				pos += size
+			}
+		}
+	}
+	return pos
+}
@@ -0,0 +1,80 @@
+package gopdf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTextFrame(t *testing.T) {
+	err := initTesting()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n := consumedLength("hyphen\u00adation is fun", []string{"hyphen-"}); n != len("hyphen\u00ad") {
+		t.Fatalf("unexpected consumed length %d", n)
+	}
+	if n := consumedLength("one two\nthree four", []string{"one two", "three"}); n != len("one two\nthree") {
+		t.Fatalf("unexpected consumed length %d", n)
+	}
+
+	pdf := setupDefaultA4PDF(t)
+	err = pdf.SetFont("LiberationSerif-Regular", "", 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf.AddPage()
+	pdf.AddPage()
+	pdf.SetXY(10, 20)
+
+	words := strings.Repeat("lorem ipsum dolor sit amet ", 30)
+	first := NewTextFrame(1, 50, 50, 150, 60)
+	second := first.Link(NewTextFrame(2, 300, 400, 150, 60))
+	second.Align = Justify
+	if first.Next() != second || second.Next() != nil {
+		t.Fatal("unexpected chain")
+	}
+	remainder, err := pdf.FillTextFrame(first, words)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if remainder == "" || !strings.HasSuffix(words, remainder) {
+		t.Fatalf("expected the remainder to be the end of the text, got %q", remainder)
+	}
+	if pdf.GetX() != 10 || pdf.GetY() != 20 {
+		t.Fatalf("expected the position to be kept, got %f,%f", pdf.GetX(), pdf.GetY())
+	}
+
+	var lines []string
+	for page := 1; page <= 2; page++ {
+		if err := pdf.SetPage(page); err != nil {
+			t.Fatal(err)
+		}
+		count := 0
+		for _, c := range pdf.getContent().listCache.caches {
+			if text, ok := c.(*cacheContentText); ok {
+				lines = append(lines, text.text)
+				count++
+			}
+		}
+		if count == 0 {
+			t.Fatalf("expected lines on page %d", page)
+		}
+	}
+	noSpace := func(s string) string {
+		return strings.Replace(s, " ", "", -1)
+	}
+	if joined := noSpace(strings.Join(lines, "") + remainder); joined != noSpace(words) {
+		t.Fatalf("expected the frames and the remainder to hold the whole text, got %q", joined)
+	}
+
+	remainder, err = pdf.FillTextFrame(NewTextFrame(0, 50, 200, 500, 200), "short text")
+	if err != nil || remainder != "" {
+		t.Fatalf("expected the text to fit, got %q, %v", remainder, err)
+	}
+
+	err = pdf.WritePdf("./test/out/text_frame.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,147 @@
+package gopdf
+
+// TextMetrics is the size of a line of text in the current font, in document units.
+type TextMetrics struct {
+	Width      float64 // advance width of the text
+	Ascent     float64 // height of the font above the baseline
+	Descent    float64 // depth of the font below the baseline, positive
+	CapHeight  float64 // height of the capital letters above the baseline
+	XHeight    float64 // height of the lowercase letters above the baseline
+	Leading    float64 // space recommended by the font between the descent of a line and the ascent of the next one
+	LineHeight float64 // Ascent + Descent + Leading
+}
+
+// GlyphMetrics is the position and size of a glyph of a text, in document units.
+type GlyphMetrics struct {
+	Rune    rune
+	GlyphID uint
+	X       float64 // position of the glyph from the start of the text, kerning included
+	Advance float64 // distance to the next glyph, without kerning
+	// BBox is the bounding box of the outline of the glyph relative to the start of the text on the baseline,
+	// with y growing downwards like the page coordinates. It is empty for a glyph without outline,
+	// and for the accented glyphs of CFF fonts made with the seac operator.
+	BBox Box
+}
+
+// MeasureText : measure the width and the vertical metrics of text (use current font)
+//
+//	Usage:
+//	m, _ := pdf.MeasureText("Hello")
+//	pdf.RectFromUpperLeft(x, baseline-m.Ascent, m.Width, m.Ascent+m.Descent)
+func (gp *GoPdf) MeasureText(text string) (TextMetrics, error) {
+	width, err := gp.MeasureTextWidth(text)
+	if err != nil {
+		return TextMetrics{}, err
+	}
+
+	f := gp.curr.FontISubset
+	ttfp := f.ttfp
+	state := gp.currTextState()
+	fontSize := state.fontSize(gp.curr.FontSize)
+	toUnits := func(v int) float64 {
+		return gp.PointsToUnits(float64(v) * fontSize / float64(ttfp.UnitsPerEm()))
+	}
+
+	ascender, descender, lineGap := ttfp.TypoAscender(), ttfp.TypoDescender(), ttfp.TypoLineGap()
+	if ascender == 0 && descender == 0 {
+		ascender, descender, lineGap = ttfp.Ascender(), ttfp.Descender(), ttfp.LineGap()
+	}
+	if descender > 0 {
+		descender = -descender
+	}
+	m := TextMetrics{
+		Width:     width,
+		Ascent:    toUnits(ascender) + gp.PointsToUnits(state.rise),
+		Descent:   toUnits(-descender) - gp.PointsToUnits(state.rise),
+		CapHeight: toUnits(ttfp.CapHeight()),
+		XHeight:   toUnits(ttfp.XHeight()),
+		Leading:   toUnits(lineGap),
+	}
+	m.LineHeight = m.Ascent + m.Descent + m.Leading
+	return m, nil
+}
+
+// MeasureGlyphs : measure the position, advance and bounding box of every glyph of text (use current font)
+func (gp *GoPdf) MeasureGlyphs(text string) ([]GlyphMetrics, error) {
+	text, err := gp.curr.FontISubset.AddChars(text)
+	if err != nil {
+		return nil, err
+	}
+
+	f := gp.curr.FontISubset
+	unitsPerEm := int(f.ttfp.UnitsPerEm())
+	state := gp.currTextState()
+	fontSize := state.fontSize(gp.curr.FontSize)
+	scale := state.scale()
+	pdfUnitToPoints := fontSize / 1000 * scale
+	fontUnitToPoints := fontSize / float64(unitsPerEm)
+
+	var glyphs []GlyphMetrics
+	var leftRune rune
+	var leftRuneIndex uint
+	x := 0.0
+	for i, r := range text {
+		glyphIndex, err := f.CharIndex(r)
+		if err == ErrCharNotFound {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if i > 0 && f.ttfFontOption.UseKerning {
+			pairval := kern(f, leftRune, r, leftRuneIndex, glyphIndex)
+			x += float64(convertTTFUnit2PDFUnit(int(pairval), unitsPerEm)) * pdfUnitToPoints
+		}
+
+		width, err := f.CharWidth(r)
+		if err != nil {
+			return nil, err
+		}
+		advance := float64(width)*pdfUnitToPoints + state.charSpacing*scale
+		if r == ' ' {
+			advance += state.wordSpacing * scale
+		}
+
+		glyph := GlyphMetrics{
+			Rune:    r,
+			GlyphID: glyphIndex,
+			X:       gp.PointsToUnits(x),
+			Advance: gp.PointsToUnits(advance),
+		}
+		if xMin, yMin, xMax, yMax, err := f.glyphBBox(glyphIndex); err == nil && (xMin != xMax || yMin != yMax) {
+			// the stroke of synthetic bold extends the outline by half its width, synthetic italic slants it
+			stroke := state.boldStroke / 2
+			left, right := xMin+state.skew*yMin, xMax+state.skew*yMax
+			glyph.BBox = Box{
+				Left:   gp.PointsToUnits(x + left*fontUnitToPoints*scale - stroke),
+				Top:    gp.PointsToUnits(-yMax*fontUnitToPoints - state.rise - stroke),
+				Right:  gp.PointsToUnits(x + right*fontUnitToPoints*scale + stroke),
+				Bottom: gp.PointsToUnits(-yMin*fontUnitToPoints - state.rise + stroke),
+			}
+		}
+		glyphs = append(glyphs, glyph)
+
+		x += advance
+		leftRune = r
+		leftRuneIndex = glyphIndex
+	}
+	return glyphs, nil
+}
+
+// glyphBBox returns the bounding box of the outline of glyph in font units,
+// from the glyf table or the charstrings of the CFF or CFF2 table.
+func (s *SubsetFontObj) glyphBBox(glyph uint) (xMin, yMin, xMax, yMax float64, err error) {
+	if !s.ttfp.IsCFF() {
+		x0, y0, x1, y1, err := s.ttfp.GlyphBBox(glyph)
+		return float64(x0), float64(y0), float64(x1), float64(y1), err
+	}
+	if s.cff == nil {
+		tag := "CFF "
+		if s.ttfp.TableData("CFF2") != nil {
+			tag = "CFF2"
+		}
+		if s.cff, err = parseCFF(s.ttfp.TableData(tag)); err != nil {
+			return 0, 0, 0, 0, err
+		}
+	}
+	return s.cff.glyphBBox(int(glyph))
+}
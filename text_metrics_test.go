@@ -0,0 +1,68 @@
+package gopdf
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTextMetrics(t *testing.T) {
+	err := initTesting()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pdf := setupDefaultA4PDF(t)
+	pdf.AddPage()
+	err = pdf.SetFont("LiberationSerif-Regular", "", 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := pdf.MeasureText("Hxg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	width, err := pdf.MeasureTextWidth("Hxg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Width != width {
+		t.Fatalf("expected width %f, got %f", width, m.Width)
+	}
+	if !(m.Ascent > m.CapHeight && m.CapHeight > m.XHeight && m.XHeight > 0 && m.Descent > 0) {
+		t.Fatalf("unexpected metrics %+v", m)
+	}
+	if math.Abs(m.LineHeight-(m.Ascent+m.Descent+m.Leading)) > 1e-9 {
+		t.Fatalf("unexpected line height %+v", m)
+	}
+
+	glyphs, err := pdf.MeasureGlyphs("Hx g")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(glyphs) != 4 || glyphs[0].Rune != 'H' || glyphs[0].X != 0 {
+		t.Fatalf("unexpected glyphs %+v", glyphs)
+	}
+	last := glyphs[3]
+	if total := last.X + last.Advance; math.Abs(total-mustMeasure(t, pdf, "Hx g")) > 0.01 {
+		t.Fatalf("expected the glyphs to add up to the width, got %f", total)
+	}
+	h := glyphs[0].BBox
+	if math.Abs(-h.Top-m.CapHeight) > 0.5 || math.Abs(h.Bottom) > 0.5 {
+		t.Fatalf("expected H to stand on the baseline up to the cap height, got %+v", h)
+	}
+	if glyphs[2].BBox != (Box{}) {
+		t.Fatalf("expected an empty box for the space, got %+v", glyphs[2].BBox)
+	}
+	if g := glyphs[3].BBox; g.Bottom <= 0 || g.Left < glyphs[3].X-1 {
+		t.Fatalf("expected g to go below the baseline, got %+v", g)
+	}
+}
+
+func mustMeasure(t *testing.T, pdf *GoPdf, text string) float64 {
+	width, err := pdf.MeasureTextWidth(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return width
+}
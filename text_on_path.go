@@ -0,0 +1,206 @@
+package gopdf
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrEmptyTextPath occurs when text is placed on a path without any length.
+var ErrEmptyTextPath = errors.New("text path is empty")
+
+// TextPath is a path made of lines, Bezier curves and arcs along which text can be drawn with TextOnPath.
+// The glyphs stand on the left side of the path, so text drawn clockwise around a circle is outside of it.
+type TextPath struct {
+	points []Point // the path flattened into lines, in document units
+}
+
+// NewTextPath starts a path at x, y.
+func NewTextPath(x, y float64) *TextPath {
+	return &TextPath{points: []Point{{X: x, Y: y}}}
+}
+
+// ArcTextPath creates a path along an arc of a circle with center cx, cy and radius r.
+// The angles are in degrees, 0 is at 3 o'clock and the angles increase clockwise:
+// an arc with endDeg > startDeg is drawn clockwise and its text is outside of the circle.
+func ArcTextPath(cx, cy, r, startDeg, endDeg float64) *TextPath {
+	p := &TextPath{}
+	p.addArc(cx, cy, r, startDeg, endDeg)
+	return p
+}
+
+// CircleTextPath creates a path around a whole circle, clockwise from startDeg (see ArcTextPath).
+//
+//	Usage:
+//	// text centered at the top of a seal
+//	pdf.TextOnPath("OFFICIAL SEAL", gopdf.CircleTextPath(300, 300, 80, 90), gopdf.TextOnPathOption{Align: gopdf.Center})
+func CircleTextPath(cx, cy, r, startDeg float64) *TextPath {
+	return ArcTextPath(cx, cy, r, startDeg, startDeg+360)
+}
+
+// LineTo adds a straight line to x, y.
+func (p *TextPath) LineTo(x, y float64) *TextPath {
+	p.points = append(p.points, Point{X: x, Y: y})
+	return p
+}
+
+// CurveTo adds a cubic Bezier curve with control points x1, y1 and x2, y2 ending at x3, y3 (like Curve).
+func (p *TextPath) CurveTo(x1, y1, x2, y2, x3, y3 float64) *TextPath {
+	start := p.last()
+	const steps = 64
+	for i := 1; i <= steps; i++ {
+		t := float64(i) / steps
+		u := 1 - t
+		p.points = append(p.points, Point{
+			X: u*u*u*start.X + 3*u*u*t*x1 + 3*u*t*t*x2 + t*t*t*x3,
+			Y: u*u*u*start.Y + 3*u*u*t*y1 + 3*u*t*t*y2 + t*t*t*y3,
+		})
+	}
+	return p
+}
+
+// ArcTo adds an arc of the circle with center cx, cy from startDeg to endDeg (see ArcTextPath),
+// joined to the end of the path with a line.
+func (p *TextPath) ArcTo(cx, cy, r, startDeg, endDeg float64) *TextPath {
+	p.addArc(cx, cy, r, startDeg, endDeg)
+	return p
+}
+
+func (p *TextPath) addArc(cx, cy, r, startDeg, endDeg float64) {
+	steps := int(math.Ceil(math.Abs(endDeg-startDeg) / 2))
+	if steps < 1 {
+		steps = 1
+	}
+	for i := 0; i <= steps; i++ {
+		a := (startDeg + (endDeg-startDeg)*float64(i)/float64(steps)) * math.Pi / 180
+		p.points = append(p.points, Point{X: cx + r*math.Cos(a), Y: cy + r*math.Sin(a)})
+	}
+}
+
+func (p *TextPath) last() Point {
+	if len(p.points) == 0 {
+		return Point{}
+	}
+	return p.points[len(p.points)-1]
+}
+
+// Length returns the length of the path.
+func (p *TextPath) Length() float64 {
+	length := 0.0
+	for i := 1; i < len(p.points); i++ {
+		length += math.Hypot(p.points[i].X-p.points[i-1].X, p.points[i].Y-p.points[i-1].Y)
+	}
+	return length
+}
+
+// pointAt returns the point at distance s from the start of the path and the direction of the path there.
+// Distances before the start or after the end follow the first or last line of the path.
+func (p *TextPath) pointAt(s float64) (Point, float64) {
+	var a, b Point
+	for i := 1; i < len(p.points); i++ {
+		a, b = p.points[i-1], p.points[i]
+		l := math.Hypot(b.X-a.X, b.Y-a.Y)
+		if l == 0 {
+			continue
+		}
+		if s <= l || i == len(p.points)-1 {
+			t := s / l
+			return Point{X: a.X + (b.X-a.X)*t, Y: a.Y + (b.Y-a.Y)*t}, math.Atan2(b.Y-a.Y, b.X-a.X)
+		}
+		s -= l
+	}
+	return a, math.Atan2(b.Y-a.Y, b.X-a.X)
+}
+
+// TextOnPathOption configures how TextOnPath places the text.
+type TextOnPathOption struct {
+	Align         int     // Left, Center or Right, relative to the length of the path
+	StartOffset   float64 // distance along the path before the text starts (or after it ends when aligned right)
+	LetterSpacing float64 // extra space added after each character
+}
+
+// TextOnPath draws text along path with the current font: each glyph is rotated to follow the direction of the path.
+//
+//	Usage:
+//	path := gopdf.NewTextPath(50, 200).CurveTo(150, 100, 250, 300, 350, 200)
+//	pdf.TextOnPath("Text along a curve", path, gopdf.TextOnPathOption{LetterSpacing: 1})
+func (gp *GoPdf) TextOnPath(text string, path *TextPath, opt TextOnPathOption) error {
+	if path == nil || path.Length() == 0 {
+		return ErrEmptyTextPath
+	}
+	text, err := gp.addChars(gp.curr.FontISubset, text, gp.indexOfContent)
+	if err != nil {
+		return err
+	}
+
+	f := gp.curr.FontISubset
+	fontSize := gp.curr.FontSize
+	letterSpacing := gp.UnitsToPoints(opt.LetterSpacing) + gp.curr.CharSpacing
+	unitsPerEm := int(f.ttfp.UnitsPerEm())
+
+	// advances of the glyphs in points, including kerning and letter spacing
+	type glyphAdvance struct {
+		index   uint
+		advance float64
+		kerning float64
+	}
+	var glyphs []glyphAdvance
+	total := 0.0
+	var leftRune rune
+	var leftIndex uint
+	for i, r := range []rune(text) {
+		index, err := f.CharIndex(r)
+		if err == ErrCharNotFound {
+			continue
+		} else if err != nil {
+			return err
+		}
+		width, err := f.CharWidth(r)
+		if err != nil {
+			return err
+		}
+		g := glyphAdvance{index: index, advance: float64(width)*fontSize/1000 + letterSpacing}
+		if i > 0 && f.ttfFontOption.UseKerning {
+			g.kerning = float64(convertTTFUnit2PDFUnit(int(kern(f, leftRune, r, leftIndex, index)), unitsPerEm)) * fontSize / 1000
+		}
+		total += g.kerning + g.advance
+		glyphs = append(glyphs, g)
+		leftRune = r
+		leftIndex = index
+	}
+	if len(glyphs) > 0 {
+		total -= letterSpacing
+	}
+
+	length := gp.UnitsToPoints(path.Length())
+	offset := gp.UnitsToPoints(opt.StartOffset)
+	if opt.Align&Right == Right {
+		offset = length - total - offset
+	} else if opt.Align&Center == Center {
+		offset += (length - total) / 2
+	}
+
+	cache := cacheContentTextOnPath{
+		pageHeight:     gp.curr.pageSize.H,
+		fontCountIndex: gp.curr.FontFontCount + 1,
+		fontSubset:     gp.curr.FontISubset,
+		fontSize:       fontSize,
+		textColor:      gp.curr.textColor(),
+		txtColorMode:   gp.curr.txtColorMode,
+	}
+	s := offset
+	for _, g := range glyphs {
+		s += g.kerning
+		// the glyph is rotated around the middle of its advance so it follows curved paths
+		middle, angle := path.pointAt(gp.PointsToUnits(s + (g.advance-letterSpacing)/2))
+		half := (g.advance - letterSpacing) / 2
+		cache.glyphs = append(cache.glyphs, placedGlyph{
+			index: g.index,
+			x:     gp.UnitsToPoints(middle.X) - half*math.Cos(angle),
+			y:     gp.UnitsToPoints(middle.Y) - half*math.Sin(angle),
+			angle: angle,
+		})
+		s += g.advance
+	}
+	gp.getContent().listCache.append(&cache)
+	return nil
+}
@@ -0,0 +1,66 @@
+package gopdf
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestTextOnPath(t *testing.T) {
+	err := initTesting()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf := setupDefaultA4PDF(t)
+	pdf.AddPage()
+
+	circle := CircleTextPath(300, 300, 100, 90)
+	if l := circle.Length(); math.Abs(l-2*math.Pi*100) > 0.1 {
+		t.Fatalf("unexpected circle length %f", l)
+	}
+	// the middle of a circle starting at 6 o'clock is at the top, where the path goes to the right
+	p, angle := circle.pointAt(circle.Length() / 2)
+	if math.Abs(p.X-300) > 0.01 || math.Abs(p.Y-200) > 0.01 || math.Abs(angle) > 0.05 {
+		t.Fatalf("unexpected point %v and angle %f at the top of the circle", p, angle)
+	}
+
+	err = pdf.TextOnPath("OFFICIAL SEAL", circle, TextOnPathOption{Align: Center, LetterSpacing: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache := pdf.getContent().listCache.last().(*cacheContentTextOnPath)
+	if len(cache.glyphs) != len("OFFICIAL SEAL") {
+		t.Fatalf("expected a glyph per character, got %d", len(cache.glyphs))
+	}
+	// centered text is symmetric around the top of the circle
+	first, last := cache.glyphs[0], cache.glyphs[len(cache.glyphs)-1]
+	if first.x > 300 || last.x < 300 || first.angle >= 0 || last.angle <= 0 || math.Abs(first.angle+last.angle) > 0.1 {
+		t.Fatalf("text is not centered at the top: %+v %+v", first, last)
+	}
+	var buf bytes.Buffer
+	if err := cache.write(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(buf.String(), " Tm <") != len(cache.glyphs) {
+		t.Fatalf("unexpected content:\n%s", buf.String())
+	}
+
+	curve := NewTextPath(50, 600).CurveTo(150, 500, 250, 700, 350, 600).LineTo(450, 600)
+	err = pdf.TextOnPath("Text along a curve", curve, TextOnPathOption{StartOffset: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pdf.TextOnPath("Right", ArcTextPath(300, 300, 60, 180, 360), TextOnPathOption{Align: Right})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pdf.TextOnPath("x", NewTextPath(1, 1), TextOnPathOption{}); err != ErrEmptyTextPath {
+		t.Fatalf("expected ErrEmptyTextPath, got %v", err)
+	}
+
+	err = pdf.WritePdf("./test/out/text_on_path.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
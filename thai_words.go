@@ -0,0 +1,22 @@
+package gopdf
+
+// thaiWords is the built-in word list of DefaultThaiDictionary, it holds common Thai words only.
+// Load a complete word list with LoadWordDictionary for better results.
+const thaiWords = `
+การ ของ ใน ที่ และ เป็น มี ได้ ไม่ จะ ให้ ว่า กับ มา ไป แล้ว คน นี้ นั้น ความ อยู่ หรือ ก็ ทำ จาก
+เรา เขา ผม ฉัน คุณ ท่าน ซึ่ง โดย ต้อง แต่ ยัง เมื่อ ถ้า เพราะ ดังนั้น อย่าง ขึ้น ลง ออก เข้า ถึง กว่า
+มาก น้อย ทุก บาง หลาย หนึ่ง สอง สาม สี่ ห้า หก เจ็ด แปด เก้า สิบ ร้อย พัน หมื่น แสน ล้าน
+วัน เดือน ปี เวลา ชั่วโมง นาที วินาที วันนี้ พรุ่งนี้ เมื่อวาน ตอนนี้ บ้าน เมือง ประเทศ ไทย ภาษา
+คนไทย ประเทศไทย กรุงเทพ รัฐบาล ประชาชน ราชการ โรงเรียน นักเรียน ครู มหาวิทยาลัย หนังสือ
+เรียน สอน อ่าน เขียน พูด ฟัง ดู เห็น รู้ รู้สึก คิด เข้าใจ ชอบ รัก อยาก ต้องการ กิน ดื่ม นอน เดิน วิ่ง
+นั่ง ยืน ทำงาน งาน เงิน ราคา ซื้อ ขาย ตลาด ร้าน อาหาร น้ำ ข้าว ผลไม้ รถ ถนน ทาง เดินทาง ไฟ ฟ้า
+ฝน ลม ดิน ต้นไม้ ดอกไม้ สวย ดี เลว ใหญ่ เล็ก ยาว สั้น สูง ต่ำ ร้อน หนาว ใหม่ เก่า เร็ว ช้า ง่าย ยาก
+สำคัญ ปัญหา เรื่อง ข้อมูล ระบบ คอมพิวเตอร์ โปรแกรม เอกสาร หน้า ข้อความ ตัวอักษร ภาพ สี ขนาด
+แบบ วิธี ใช้ สร้าง เปิด ปิด ส่ง รับ ตอบ ถาม ช่วย ขอบคุณ สวัสดี ครับ ค่ะ คะ นะ ด้วย เลย อีก เท่านั้น
+เช่น ตาม ระหว่าง หลัง ก่อน ใต้ บน ข้าง นอก ใกล้ ไกล ทั้ง ทั้งหมด แต่ละ ตัว อัน สิ่ง เพื่อ สำหรับ
+เกี่ยวกับ เกิด ตาย ชีวิต โลก อื่น ตัวเอง พ่อ แม่ ลูก พี่ น้อง เพื่อน ครอบครัว ผู้ ผู้หญิง ผู้ชาย เด็ก
+ผู้ใหญ่ สุข สุขภาพ โรค หมอ โรงพยาบาล ยา ทะเล ภูเขา แม่น้ำ สัตว์ หมา แมว นก ปลา ช้าง ม้า ไก่ หมู
+วัว ภาษาไทย ภาษาอังกฤษ อังกฤษ ญี่ปุ่น จีน ประวัติศาสตร์ วัฒนธรรม ศาสนา พระ วัด ธรรมชาติ
+สิ่งแวดล้อม เศรษฐกิจ การเมือง สังคม กฎหมาย ตำรวจ ทหาร พิมพ์ ไฟล์ เครื่อง ตัด คำ บรรทัด ย่อหน้า
+ตัดคำ ทดสอบ ตัวอย่าง
+`
@@ -0,0 +1,258 @@
+package gopdf
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrTOCNotReserved is returned by AddTOCEntry when no pages were reserved with ReserveTOC.
+var ErrTOCNotReserved = errors.New("table of contents pages are not reserved")
+
+// ErrTOCOverflow is returned when the entries of the table of contents do not fit in the reserved pages.
+var ErrTOCOverflow = errors.New("table of contents does not fit in the reserved pages")
+
+// TOCOption configures the table of contents drawn on the pages reserved by ReserveTOC.
+type TOCOption struct {
+	Rect       *Rect                 // size of the area of the entries, placed at the top left margin; nil uses the page without its margins
+	LineHeight float64               // height of an entry, 0 uses 1.5 times the font size
+	Indent     float64               // indentation of each level, 0 uses twice the font size
+	Leader     string                // text repeated between the title and the page number, "." if empty
+	PageLabel  func(page int) string // label of a page number, nil writes the page number
+}
+
+// TOCEntry is an entry of the table of contents.
+type TOCEntry struct {
+	Title  string
+	Level  int
+	Anchor string
+}
+
+type tableOfContents struct {
+	option  TOCOption
+	x, y    float64
+	width   float64
+	height  float64
+	pages   []tocPage
+	state   textStateSnapshot
+	entries []TOCEntry
+	filled  bool
+}
+
+// tocPage is a page reserved for the table of contents.
+type tocPage struct {
+	indexOfPageObj int
+	indexOfContent int
+}
+
+// ReserveTOC : add pages for a table of contents that is filled in when the pdf is written.
+// The entries are drawn with the font and colors of the time ReserveTOC is called.
+//
+//	Usage:
+//	pdf.SetFont("LiberationSerif-Regular", "", 12)
+//	pdf.ReserveTOC(1, gopdf.TOCOption{})
+//	pdf.AddPage()
+//	pdf.AddTOCEntry("Introduction", 0, "")
+func (gp *GoPdf) ReserveTOC(pages int, opt TOCOption) error {
+	if gp.curr.FontISubset == nil {
+		return ErrMissingFontFamily
+	}
+	if opt.LineHeight == 0 {
+		opt.LineHeight = gp.curr.FontSize * 1.5
+		gp.PointsToUnitsVar(&opt.LineHeight)
+	}
+	if opt.Indent == 0 {
+		opt.Indent = gp.curr.FontSize * 2
+		gp.PointsToUnitsVar(&opt.Indent)
+	}
+	if opt.Leader == "" {
+		opt.Leader = "."
+	}
+	if opt.PageLabel == nil {
+		opt.PageLabel = strconv.Itoa
+	}
+
+	toc := &tableOfContents{option: opt, state: snapshotTextState(&gp.curr)}
+	for i := 0; i < pages; i++ {
+		gp.AddPage()
+		gp.getContent()
+		toc.pages = append(toc.pages, tocPage{
+			indexOfPageObj: gp.curr.IndexOfPageObj,
+			indexOfContent: gp.indexOfContent,
+		})
+	}
+
+	toc.x, toc.y = gp.MarginLeft(), gp.MarginTop()
+	pageSize := gp.curr.pageSize
+	toc.width = gp.PointsToUnits(pageSize.W) - gp.MarginLeft() - gp.MarginRight()
+	toc.height = gp.PointsToUnits(pageSize.H) - gp.MarginTop() - gp.MarginBottom()
+	if opt.Rect != nil {
+		toc.width, toc.height = opt.Rect.W, opt.Rect.H
+	}
+	gp.toc = toc
+	return nil
+}
+
+// AddTOCEntry : add an entry to the table of contents pointing to anchor.
+// An anchor set before with SetAnchor is kept, otherwise the anchor is created at the current position,
+// with a generated name if anchor is empty.
+// ErrTOCOverflow is returned when the entry does not fit in the reserved pages.
+//
+//	Usage:
+//	pdf.AddOutline("Chapter 1")
+//	pdf.AddTOCEntry("Chapter 1", 0, "chapter-1")
+func (gp *GoPdf) AddTOCEntry(title string, level int, anchor string) error {
+	if gp.toc == nil {
+		return ErrTOCNotReserved
+	}
+	if len(gp.toc.entries) >= gp.toc.linesPerPage()*len(gp.toc.pages) {
+		return ErrTOCOverflow
+	}
+	if anchor == "" {
+		anchor = fmt.Sprintf("gopdf-toc-%d", len(gp.toc.entries))
+	}
+	if _, ok := gp.anchors[anchor]; !ok {
+		gp.SetAnchor(anchor)
+	}
+	gp.toc.entries = append(gp.toc.entries, TOCEntry{Title: title, Level: level, Anchor: anchor})
+	return nil
+}
+
+// TOCEntries returns the entries of the table of contents.
+func (gp *GoPdf) TOCEntries() []TOCEntry {
+	if gp.toc == nil {
+		return nil
+	}
+	return append([]TOCEntry(nil), gp.toc.entries...)
+}
+
+// linesPerPage returns the number of entries drawn on each reserved page.
+func (toc *tableOfContents) linesPerPage() int {
+	lines := int(math.Floor(toc.height/toc.option.LineHeight + 1e-9))
+	if lines <= 0 {
+		return 1
+	}
+	return lines
+}
+
+// pageNumbers returns the page number of each page object index.
+func (gp *GoPdf) pageNumbers() map[int]int {
+	numbers := make(map[int]int)
+	for i, obj := range gp.pdfObjs {
+		if _, ok := obj.(*PageObj); ok {
+			numbers[i] = len(numbers) + 1
+		}
+	}
+	return numbers
+}
+
+// pageSizeOf returns the size of the page object at indexOfPageObj.
+func (gp *GoPdf) pageSizeOf(indexOfPageObj int) *Rect {
+	if opt := gp.pdfObjs[indexOfPageObj].(*PageObj).pageOption; !opt.isEmpty() {
+		return opt.PageSize
+	}
+	return &gp.config.PageSize
+}
+
+// fillTOC draws the entries of the table of contents on the reserved pages.
+func (gp *GoPdf) fillTOC() error {
+	toc := gp.toc
+	if toc == nil || toc.filled {
+		return nil
+	}
+	toc.filled = true
+
+	state := snapshotTextState(&gp.curr)
+	x, y, indexOfPageObj, tabStops := gp.curr.X, gp.curr.Y, gp.curr.IndexOfPageObj, gp.curr.tabStops
+	indexOfContent, pageSize := gp.indexOfContent, gp.curr.pageSize
+	defer func() {
+		state.apply(&gp.curr)
+		gp.curr.X, gp.curr.Y, gp.curr.IndexOfPageObj, gp.curr.tabStops = x, y, indexOfPageObj, tabStops
+		gp.indexOfContent, gp.curr.pageSize = indexOfContent, pageSize
+	}()
+	toc.state.apply(&gp.curr)
+
+	opt := toc.option
+	linesPerPage := toc.linesPerPage()
+	if len(toc.entries) > linesPerPage*len(toc.pages) {
+		return ErrTOCOverflow
+	}
+
+	numbers := gp.pageNumbers()
+	for i, entry := range toc.entries {
+		page := toc.pages[i/linesPerPage]
+		gp.curr.IndexOfPageObj = page.indexOfPageObj
+		gp.curr.pageSize = gp.pageSizeOf(page.indexOfPageObj)
+		gp.indexOfContent = page.indexOfContent
+
+		label := ""
+		if a, ok := gp.anchors[entry.Anchor]; ok {
+			label = opt.PageLabel(numbers[a.page])
+		}
+		indent := float64(entry.Level) * opt.Indent
+		x := toc.x + indent
+		y := toc.y + float64(i%linesPerPage)*opt.LineHeight
+		width := toc.width - indent
+		gp.SetTabStops(TabStop{Position: width, Align: TabRight, Leader: opt.Leader})
+		gp.SetXY(x, y)
+		title := strings.Replace(entry.Title, "\t", " ", -1)
+		if err := gp.CellWithOption(&Rect{W: width, H: opt.LineHeight}, title+"\t"+label, CellOption{Align: Left | Middle}); err != nil {
+			return err
+		}
+		gp.AddInternalLink(entry.Anchor, x, y, width, opt.LineHeight)
+	}
+	return nil
+}
+
+// textStateSnapshot is the part of the current state used to draw text.
+type textStateSnapshot struct {
+	fontSubset        *SubsetFontObj
+	fontSize          float64
+	fontStyle         int
+	fontFontCount     int
+	fontType          int
+	charSpacing       float64
+	wordSpacing       float64
+	horizontalScaling float64
+	textRise          float64
+	textDecoration    *TextDecoration
+	txtColor          ICacheColorText
+	txtColorMode      string
+	grayFill          float64
+}
+
+func snapshotTextState(c *Current) textStateSnapshot {
+	return textStateSnapshot{
+		fontSubset:        c.FontISubset,
+		fontSize:          c.FontSize,
+		fontStyle:         c.FontStyle,
+		fontFontCount:     c.FontFontCount,
+		fontType:          c.FontType,
+		charSpacing:       c.CharSpacing,
+		wordSpacing:       c.wordSpacing,
+		horizontalScaling: c.horizontalScaling,
+		textRise:          c.textRise,
+		textDecoration:    c.textDecoration,
+		txtColor:          c.txtColor,
+		txtColorMode:      c.txtColorMode,
+		grayFill:          c.grayFill,
+	}
+}
+
+func (s textStateSnapshot) apply(c *Current) {
+	c.FontISubset = s.fontSubset
+	c.FontSize = s.fontSize
+	c.FontStyle = s.fontStyle
+	c.FontFontCount = s.fontFontCount
+	c.FontType = s.fontType
+	c.CharSpacing = s.charSpacing
+	c.wordSpacing = s.wordSpacing
+	c.horizontalScaling = s.horizontalScaling
+	c.textRise = s.textRise
+	c.textDecoration = s.textDecoration
+	c.txtColor = s.txtColor
+	c.txtColorMode = s.txtColorMode
+	c.grayFill = s.grayFill
+}
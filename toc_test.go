@@ -0,0 +1,168 @@
+package gopdf
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestTableOfContents(t *testing.T) {
+	err := initTesting()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pdf := setupDefaultA4PDF(t)
+	err = pdf.AddTOCEntry("too early", 0, "")
+	if err != ErrTOCNotReserved {
+		t.Fatalf("expected ErrTOCNotReserved, got %v", err)
+	}
+
+	err = pdf.SetFont("LiberationSerif-Regular", "", 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pdf.ReserveTOC(1, TOCOption{
+		PageLabel: func(page int) string { return fmt.Sprintf("p. %d", page) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tocContent := pdf.indexOfContent
+
+	for i := 1; i <= 3; i++ {
+		pdf.AddPage()
+		title := fmt.Sprintf("Chapter %d", i)
+		pdf.AddOutline(title)
+		if err := pdf.AddTOCEntry(title, 0, ""); err != nil {
+			t.Fatal(err)
+		}
+		if err := pdf.Cell(nil, title); err != nil {
+			t.Fatal(err)
+		}
+		pdf.Br(20)
+		if err := pdf.AddTOCEntry(fmt.Sprintf("Section %d.1", i), 1, fmt.Sprintf("section-%d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if entries := pdf.TOCEntries(); len(entries) != 6 || entries[1].Anchor != "section-1" || entries[1].Level != 1 {
+		t.Fatalf("unexpected entries %v", entries)
+	}
+
+	err = pdf.WritePdf("./test/out/table_of_contents.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var texts []string
+	for _, c := range pdf.pdfObjs[tocContent].(*ContentObj).listCache.caches {
+		if text, ok := c.(*cacheContentText); ok && text.text != "" {
+			texts = append(texts, text.text)
+		}
+	}
+	joined := strings.Join(texts, "|")
+	for _, expected := range []string{"Chapter 1|", "|p. 2", "Section 3.1|", "|p. 4"} {
+		if !strings.Contains(joined, expected) {
+			t.Fatalf("expected %q in the table of contents, got %q", expected, joined)
+		}
+	}
+	if links := pdf.pdfObjs[pageObjIndex(t, pdf, 1)].(*PageObj).LinkObjIds; len(links) != 6 {
+		t.Fatalf("expected 6 links on the table of contents page, got %d", len(links))
+	}
+
+	// an anchor placed before is kept
+	pdf = setupDefaultA4PDF(t)
+	err = pdf.SetFont("LiberationSerif-Regular", "", 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pdf.ReserveTOC(1, TOCOption{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tocContent = pdf.indexOfContent
+	pdf.AddPage()
+	pdf.SetAnchor("intro")
+	pdf.AddPage()
+	if err := pdf.AddTOCEntry("Introduction", 0, "intro"); err != nil {
+		t.Fatal(err)
+	}
+	if page := pdf.pageNumbers()[pdf.anchors["intro"].page]; page != 2 {
+		t.Fatalf("expected the anchor to stay on page 2, got page %d", page)
+	}
+	if _, err = pdf.GetBytesPdfReturnErr(); err != nil {
+		t.Fatal(err)
+	}
+	texts = nil
+	for _, c := range pdf.pdfObjs[tocContent].(*ContentObj).listCache.caches {
+		if text, ok := c.(*cacheContentText); ok && text.text != "" {
+			texts = append(texts, text.text)
+		}
+	}
+	if joined := strings.Join(texts, "|"); !strings.HasSuffix(joined, "|2") {
+		t.Fatalf("expected the entry to point to page 2, got %q", joined)
+	}
+
+	// the entries are drawn with the size of the reserved page, not the one of the last page
+	pdf = setupDefaultA4PDF(t)
+	err = pdf.SetFont("LiberationSerif-Regular", "", 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pdf.ReserveTOC(1, TOCOption{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tocContent = pdf.indexOfContent
+	pdf.AddPageWithOption(PageOption{PageSize: PageSizeA4Landscape})
+	if err := pdf.AddTOCEntry("Landscape", 0, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = pdf.GetBytesPdfReturnErr(); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range pdf.pdfObjs[tocContent].(*ContentObj).listCache.caches {
+		if text, ok := c.(*cacheContentText); ok && text.pageheight != PageSizeA4.H {
+			t.Fatalf("expected the entries to be drawn with the page height %f, got %f", PageSizeA4.H, text.pageheight)
+		}
+	}
+	if pdf.curr.pageSize.H != PageSizeA4Landscape.H {
+		t.Fatal("expected the page size of the last page to be restored")
+	}
+
+	pdf = setupDefaultA4PDF(t)
+	err = pdf.SetFont("LiberationSerif-Regular", "", 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pdf.ReserveTOC(1, TOCOption{Rect: &Rect{W: 300, H: 40}, LineHeight: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf.AddPage()
+	for i := 0; i < 2; i++ {
+		if err := pdf.AddTOCEntry("entry", 0, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = pdf.AddTOCEntry("entry", 0, "")
+	if err != ErrTOCOverflow {
+		t.Fatalf("expected ErrTOCOverflow, got %v", err)
+	}
+	if entries := pdf.TOCEntries(); len(entries) != 2 {
+		t.Fatalf("expected 2 entries after the overflow, got %d", len(entries))
+	}
+	if _, err = pdf.GetBytesPdfReturnErr(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func pageObjIndex(t *testing.T, pdf *GoPdf, page int) int {
+	for index, number := range pdf.pageNumbers() {
+		if number == page {
+			return index
+		}
+	}
+	t.Fatalf("page %d not found", page)
+	return -1
+}
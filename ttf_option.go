@@ -3,9 +3,14 @@ package gopdf
 // TtfOption  font option
 type TtfOption struct {
 	UseKerning                bool
-	Style                     int               //Regular|Bold|Italic
-	OnGlyphNotFound           func(r rune)      //Called when a glyph cannot be found, just for debugging
-	OnGlyphNotFoundSubstitute func(r rune) rune //Called when a glyph cannot be found, we can return a new rune to replace it.
+	Style                     int                //Regular|Bold|Italic
+	OnGlyphNotFound           func(r rune)       //Called when a glyph cannot be found, just for debugging
+	OnGlyphNotFoundSubstitute func(r rune) rune  //Called when a glyph cannot be found, we can return a new rune to replace it.
+	FaceIndex                 int                //Index of the font to use in a font collection (.ttc/.otc), see ListFontFaces
+	FaceName                  string             //PostScript name of the font to use in a font collection, instead of FaceIndex
+	Axes                      map[string]float64 //Coordinates of the axes of a variable font by tag, such as {"wght": 700}, the other axes are at their default
+	SyntheticStyles           bool               //Draw the Bold and Italic styles missing from the family with this font, stroked and slanted
+	EmbedFullFont             bool               //Embed the complete font with the widths and characters of all its glyphs, instead of a subset of the glyphs used
 }
 
 func defaultTtfFontOption() TtfOption {
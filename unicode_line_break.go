@@ -0,0 +1,286 @@
+package gopdf
+
+import (
+	"unicode"
+)
+
+// lineBreakClass is a line breaking class of the Unicode Line Breaking Algorithm (UAX #14).
+type lineBreakClass int
+
+const (
+	lbAL  lineBreakClass = iota // alphabetic, also used for AI, SG and XX
+	lbBK                        // mandatory break
+	lbCR                        // carriage return
+	lbLF                        // line feed
+	lbNL                        // next line
+	lbSP                        // space
+	lbZW                        // zero width space
+	lbWJ                        // word joiner
+	lbGL                        // non-breaking (glue)
+	lbZWJ                       // zero width joiner
+	lbCM                        // combining mark
+	lbBA                        // break after
+	lbBB                        // break before
+	lbB2                        // break opportunity before and after
+	lbHY                        // hyphen
+	lbCB                        // contingent break
+	lbCL                        // close punctuation
+	lbCP                        // close parenthesis
+	lbEX                        // exclamation / interrogation
+	lbIN                        // inseparable
+	lbNS                        // nonstarter, also used for CJ
+	lbOP                        // open punctuation
+	lbQU                        // quotation
+	lbIS                        // infix numeric separator
+	lbNU                        // numeric
+	lbPO                        // postfix numeric
+	lbPR                        // prefix numeric
+	lbSY                        // symbols allowing break after
+	lbHL                        // hebrew letter
+	lbID                        // ideographic
+	lbEB                        // emoji base
+	lbEM                        // emoji modifier
+	lbH2                        // hangul LV syllable
+	lbH3                        // hangul LVT syllable
+	lbJL                        // hangul L jamo
+	lbJV                        // hangul V jamo
+	lbJT                        // hangul T jamo
+	lbRI                        // regional indicator
+	lbSA                        // complex context dependent (south east asian)
+)
+
+// lineBreakClasses holds the classes of the characters which cannot be derived from their general category.
+var lineBreakClasses = map[rune]lineBreakClass{
+	'\t': lbBA, '\n': lbLF, '\v': lbBK, '\f': lbBK, '\r': lbCR, ' ': lbSP,
+	'!': lbEX, '"': lbQU, '$': lbPR, '%': lbPO, '\'': lbQU, '(': lbOP, ')': lbCP,
+	'+': lbPR, ',': lbIS, '-': lbHY, '.': lbIS, '/': lbSY, ':': lbIS, ';': lbIS,
+	'?': lbEX, '[': lbOP, '\\': lbPR, ']': lbCP, '{': lbOP, '|': lbBA, '}': lbCL,
+	'\u0085': lbNL, '\u00A0': lbGL, '\u00A1': lbOP, '\u00A2': lbPO, '\u00A3': lbPR, '\u00A4': lbPR,
+	'\u00A5': lbPR, '\u00AB': lbQU, '\u00AD': lbBA, '\u00B0': lbPO, '\u00B1': lbPR, '\u00B4': lbBB,
+	'\u00BB': lbQU, '\u00BF': lbOP, '\u034F': lbGL, '\u0F0B': lbBA, '\u0F0C': lbGL, '\u180E': lbGL,
+	'\u2007': lbGL, '\u2010': lbBA, '\u2011': lbGL, '\u2012': lbBA, '\u2013': lbBA, '\u2014': lbB2,
+	'\u2018': lbQU, '\u2019': lbQU, '\u201B': lbQU, '\u201C': lbQU, '\u201D': lbQU, '\u201F': lbQU,
+	'\u2024': lbIN, '\u2025': lbIN, '\u2026': lbIN, '\u2027': lbBA, '\u2028': lbBK, '\u2029': lbBK,
+	'\u202F': lbGL, '\u2039': lbQU, '\u203A': lbQU, '\u2044': lbIS, '\u2060': lbWJ, '\u200B': lbZW,
+	'\u200D': lbZWJ, '\u2116': lbPR, '\u2212': lbPR, '\u2213': lbPR, '\u2E3A': lbB2, '\u2E3B': lbB2,
+	'\u3000': lbBA, '\u3001': lbCL, '\u3002': lbCL, '\u3005': lbNS, '\u301C': lbNS, '\u303B': lbNS,
+	'\u309B': lbNS, '\u309C': lbNS, '\u309D': lbNS, '\u309E': lbNS, '\u30A0': lbNS, '\u30FB': lbNS,
+	'\u30FC': lbNS, '\u30FD': lbNS, '\u30FE': lbNS, '\uFE50': lbCL, '\uFE52': lbCL, '\uFEFF': lbWJ,
+	'\uFF01': lbEX, '\uFF05': lbPO, '\uFF08': lbOP, '\uFF09': lbCP, '\uFF0C': lbCL, '\uFF0E': lbCL,
+	'\uFF1A': lbNS, '\uFF1B': lbNS, '\uFF1F': lbEX, '\uFF3B': lbOP, '\uFF3D': lbCP, '\uFF5B': lbOP,
+	'\uFF5D': lbCL, '\uFF61': lbCL, '\uFF64': lbCL, '\uFF65': lbNS, '\uFFE0': lbPO, '\uFFE1': lbPR,
+	'\uFFE5': lbPR, '\uFFE6': lbPR,
+	// small kana (CJ) are nonstarters
+	'\u3041': lbNS, '\u3043': lbNS, '\u3045': lbNS, '\u3047': lbNS, '\u3049': lbNS, '\u3063': lbNS,
+	'\u3083': lbNS, '\u3085': lbNS, '\u3087': lbNS, '\u308E': lbNS, '\u3095': lbNS, '\u3096': lbNS,
+	'\u30A1': lbNS, '\u30A3': lbNS, '\u30A5': lbNS, '\u30A7': lbNS, '\u30A9': lbNS, '\u30C3': lbNS,
+	'\u30E3': lbNS, '\u30E5': lbNS, '\u30E7': lbNS, '\u30EE': lbNS, '\u30F5': lbNS, '\u30F6': lbNS,
+}
+
+// lineBreakClassOf returns the line breaking class of r, resolved as described by rule LB1.
+func lineBreakClassOf(r rune) lineBreakClass {
+	if c, ok := lineBreakClasses[r]; ok {
+		return c
+	}
+	switch {
+	case r >= 0x0590 && r <= 0x05FF && unicode.IsLetter(r):
+		return lbHL
+	case r >= 0x0E00 && r <= 0x0EFF, r >= 0x1000 && r <= 0x109F, r >= 0x1780 && r <= 0x17FF, r >= 0x19E0 && r <= 0x19FF:
+		if unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Mc, r) {
+			return lbCM
+		}
+		return lbSA
+	case r >= 0x1100 && r <= 0x115F, r >= 0xA960 && r <= 0xA97F:
+		return lbJL
+	case r >= 0x1160 && r <= 0x11A7, r >= 0xD7B0 && r <= 0xD7C6:
+		return lbJV
+	case r >= 0x11A8 && r <= 0x11FF, r >= 0xD7CB && r <= 0xD7FB:
+		return lbJT
+	case r >= 0xAC00 && r <= 0xD7A3:
+		if (r-0xAC00)%28 == 0 {
+			return lbH2
+		}
+		return lbH3
+	case r >= 0x1F1E6 && r <= 0x1F1FF:
+		return lbRI
+	case r >= 0x1F3FB && r <= 0x1F3FF:
+		return lbEM
+	case unicode.Is(unicode.Mn, r), unicode.Is(unicode.Mc, r), unicode.Is(unicode.Me, r):
+		return lbCM
+	case unicode.Is(unicode.Han, r), unicode.Is(unicode.Hiragana, r), unicode.Is(unicode.Katakana, r),
+		r >= 0x2E80 && r <= 0x2FFF, r >= 0x3000 && r <= 0x33FF, r >= 0xF900 && r <= 0xFAFF,
+		r >= 0xFF01 && r <= 0xFF60, r >= 0x1F000 && r <= 0x1FAFF:
+		if unicode.Is(unicode.Ps, r) {
+			return lbOP
+		}
+		if unicode.Is(unicode.Pe, r) {
+			return lbCL
+		}
+		return lbID
+	case unicode.Is(unicode.Nd, r):
+		return lbNU
+	case unicode.Is(unicode.Ps, r):
+		return lbOP
+	case unicode.Is(unicode.Pe, r):
+		return lbCL
+	case unicode.Is(unicode.Pi, r), unicode.Is(unicode.Pf, r):
+		return lbQU
+	case unicode.Is(unicode.Pd, r), unicode.Is(unicode.Zs, r):
+		return lbBA
+	case unicode.Is(unicode.Sc, r):
+		return lbPR
+	case unicode.Is(unicode.Cf, r):
+		return lbCM
+	}
+	return lbAL
+}
+
+// unicodeLineBreaks returns the line break opportunities of text according to the Unicode Line Breaking Algorithm.
+// The characters of class SA are treated as alphabetic, so the words of these scripts are not broken.
+func unicodeLineBreaks(text []rune) []lineBreak {
+	n := len(text)
+	if n == 0 {
+		return nil
+	}
+
+	raw := make([]lineBreakClass, n)
+	cls := make([]lineBreakClass, n)
+	attached := make([]bool, n)
+	for i, r := range text {
+		raw[i] = lineBreakClassOf(r)
+		c := raw[i]
+		if c == lbSA {
+			c = lbAL
+		}
+		cls[i] = c
+		// LB9 and LB10: combining marks take the class of their base, or are alphabetic when they have none
+		if c == lbCM || c == lbZWJ {
+			if i > 0 {
+				switch raw[i-1] {
+				case lbBK, lbCR, lbLF, lbNL, lbSP, lbZW:
+				default:
+					cls[i] = cls[i-1]
+					attached[i] = true
+					continue
+				}
+			}
+			cls[i] = lbAL
+		}
+	}
+
+	// beforeSpaces returns the class before the spaces ending at i-1
+	beforeSpaces := func(i int) lineBreakClass {
+		j := i - 1
+		for j >= 0 && cls[j] == lbSP {
+			j--
+		}
+		if j < 0 {
+			return lbSP
+		}
+		return cls[j]
+	}
+	isAlpha := func(c lineBreakClass) bool { return c == lbAL || c == lbHL }
+	isHangul := func(c lineBreakClass) bool {
+		return c == lbJL || c == lbJV || c == lbJT || c == lbH2 || c == lbH3
+	}
+
+	var breaks []lineBreak
+	riCount := 0
+	for i := 1; i <= n; i++ {
+		a := cls[i-1]
+		if raw[i-1] == lbRI {
+			riCount++
+		} else if !attached[i-1] {
+			riCount = 0
+		}
+
+		// LB4, LB5: mandatory breaks
+		if a == lbBK || a == lbLF || a == lbNL || (a == lbCR && (i == n || cls[i] != lbLF)) {
+			breaks = append(breaks, lineBreak{index: i, mandatory: true})
+			continue
+		}
+		if i == n {
+			break
+		}
+		b := cls[i]
+		if a == lbCR || b == lbBK || b == lbCR || b == lbLF || b == lbNL || b == lbSP || b == lbZW {
+			continue // LB5, LB6, LB7
+		}
+		if beforeSpaces(i) == lbZW {
+			breaks = append(breaks, lineBreak{index: i}) // LB8
+			continue
+		}
+		if raw[i-1] == lbZWJ || attached[i] {
+			continue // LB8a, LB9
+		}
+
+		if noLineBreak(a, b, beforeSpaces(i), text[i], isAlpha, isHangul) {
+			continue
+		}
+		// LB30a: break between pairs of regional indicators only
+		if a == lbRI && b == lbRI && riCount%2 == 1 {
+			continue
+		}
+		// a break after a soft hyphen shows the separator
+		breaks = append(breaks, lineBreak{index: i, hyphen: text[i-1] == softHyphen}) // LB18, LB31
+	}
+	return breaks
+}
+
+// noLineBreak applies the rules LB11 to LB30b to the pair of classes a and b.
+// s is the class before any spaces in front of b, and r is the character of class b.
+func noLineBreak(a, b, s lineBreakClass, r rune, isAlpha, isHangul func(lineBreakClass) bool) bool {
+	switch {
+	case a == lbWJ || b == lbWJ: // LB11
+		return true
+	case a == lbGL: // LB12
+		return true
+	case b == lbGL && a != lbSP && a != lbBA && a != lbHY: // LB12a
+		return true
+	case b == lbCL || b == lbCP || b == lbEX || b == lbIS || b == lbSY: // LB13
+		return true
+	case s == lbOP: // LB14
+		return true
+	case s == lbQU && b == lbOP: // LB15
+		return true
+	case (s == lbCL || s == lbCP) && b == lbNS: // LB16
+		return true
+	case s == lbB2 && b == lbB2: // LB17
+		return true
+	case a == lbSP: // LB18
+		return false
+	case a == lbQU || b == lbQU: // LB19
+		return true
+	case a == lbCB || b == lbCB: // LB20
+		return false
+	case b == lbBA || b == lbHY || b == lbNS || a == lbBB: // LB21
+		return true
+	case b == lbIN: // LB22
+		return true
+	case isAlpha(a) && b == lbNU, a == lbNU && isAlpha(b): // LB23
+		return true
+	case a == lbPR && (b == lbID || b == lbEB || b == lbEM), (a == lbID || a == lbEB || a == lbEM) && b == lbPO: // LB23a
+		return true
+	case (a == lbPR || a == lbPO) && isAlpha(b), isAlpha(a) && (b == lbPR || b == lbPO): // LB24
+		return true
+	case (a == lbCL || a == lbCP || a == lbNU) && (b == lbPO || b == lbPR),
+		(a == lbPO || a == lbPR) && (b == lbOP || b == lbNU),
+		(a == lbHY || a == lbIS || a == lbNU || a == lbSY) && b == lbNU: // LB25
+		return true
+	case a == lbJL && (b == lbJL || b == lbJV || b == lbH2 || b == lbH3),
+		(a == lbJV || a == lbH2) && (b == lbJV || b == lbJT),
+		(a == lbJT || a == lbH3) && b == lbJT: // LB26
+		return true
+	case isHangul(a) && b == lbPO, a == lbPR && isHangul(b): // LB27
+		return true
+	case isAlpha(a) && isAlpha(b): // LB28
+		return true
+	case a == lbIS && isAlpha(b): // LB29
+		return true
+	case (isAlpha(a) || a == lbNU) && b == lbOP && r < 0x2E80, a == lbCP && (isAlpha(b) || b == lbNU): // LB30
+		return true
+	case (a == lbEB || a == lbID) && b == lbEM: // LB30b
+		return true
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package gopdf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnicodeLineBreaks(t *testing.T) {
+	var tests = []struct {
+		text   string
+		breaks []int
+	}{
+		{"hello world", []int{6}},
+		{"and/or", []int{4}},
+		{"10\u00A0kg is", []int{6}},
+		{"a\u2060b c", []int{4}},
+		{"wait—what", []int{4, 5}},
+		{"(hi) there.", []int{5}},
+		{"x\u200By", []int{2}},
+		{"one\ntwo", []int{4}},
+		{"漢字。かな", []int{1, 3, 4}},
+		{"$12.50 each", []int{7}},
+	}
+	for _, tt := range tests {
+		var got []int
+		for _, b := range unicodeLineBreaks([]rune(tt.text)) {
+			if b.index < len([]rune(tt.text)) {
+				got = append(got, b.index)
+			}
+		}
+		if !reflect.DeepEqual(got, tt.breaks) {
+			t.Errorf("%q: expected breaks %v, got %v", tt.text, tt.breaks, got)
+		}
+	}
+}
+
+func TestSplitTextUnicodeMode(t *testing.T) {
+	err := initTesting()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf := setupDefaultA4PDF(t)
+	opt := &BreakOption{Mode: BreakModeUnicode}
+
+	width, err := pdf.MeasureTextWidth("price 10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines, err := pdf.SplitTextWithOption("price 10\u00A0kg", width, opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := []string{"price", "10\u00A0kg"}; !reflect.DeepEqual(lines, exp) {
+		t.Fatalf("expected %q, got %q", exp, lines)
+	}
+
+	ok, height, err := pdf.IsFitMultiCellWithOption(&Rect{W: width, H: 100}, "price 10\u00A0kg", opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || height <= 0 {
+		t.Fatalf("expected the text to fit, got %v %f", ok, height)
+	}
+	ok, _, err = pdf.IsFitMultiCellWithOption(&Rect{W: width, H: height / 2}, "price 10\u00A0kg", opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("expected the text not to fit")
+	}
+
+	pdf.AddPage()
+	err = pdf.MultiCellWithOption(&Rect{W: width, H: 100}, "price 10\u00A0kg and/or (more)", CellOption{BreakOption: opt})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSplitTextUnicodeModeSoftHyphen(t *testing.T) {
+	err := initTesting()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf := setupDefaultA4PDF(t)
+
+	lines, err := pdf.SplitTextWithOption("extra\u00ADordinarily", 60, &BreakOption{Mode: BreakModeUnicode})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := []string{"extra-", "ordinarily"}; !reflect.DeepEqual(lines, exp) {
+		t.Fatalf("expected %q, got %q", exp, lines)
+	}
+
+	lines, err = pdf.SplitTextWithOption("extra\u00ADordinarily", 60, &BreakOption{Mode: BreakModeUnicode, Separator: "~"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := []string{"extra~", "ordinarily"}; !reflect.DeepEqual(lines, exp) {
+		t.Fatalf("expected %q, got %q", exp, lines)
+	}
+}
@@ -3,6 +3,8 @@ package gopdf
 import (
 	"fmt"
 	"io"
+	"strings"
+	"unicode/utf16"
 )
 
 // UnicodeMap unicode map
@@ -48,10 +50,12 @@ func (u *UnicodeMap) write(w io.Writer, objID int) error {
 	lowIndex := 65536
 	hiIndex := -1
 
-	keys := u.PtrToSubsetFontObj.CharacterToGlyphIndex.AllKeys()
-	for _, k := range keys {
-		v, _ := u.PtrToSubsetFontObj.CharacterToGlyphIndex.Val(k)
-		index := int(v)
+	keys, vals := u.PtrToSubsetFontObj.CharacterToGlyphIndex.AllKeys(), u.PtrToSubsetFontObj.CharacterToGlyphIndex.AllVals()
+	if u.PtrToSubsetFontObj.ttfFontOption.EmbedFullFont {
+		keys, vals = u.PtrToSubsetFontObj.fontCharacters()
+	}
+	for i, k := range keys {
+		index := int(vals[i])
 		if index < lowIndex {
 			lowIndex = index
 		}
@@ -69,13 +73,17 @@ func (u *UnicodeMap) write(w io.Writer, objID int) error {
 	buff.WriteString("1 begincodespacerange\n")
 	fmt.Fprintf(buff, "<%04X><%04X>\n", lowIndex, hiIndex)
 	buff.WriteString("endcodespacerange\n")
-	fmt.Fprintf(buff, "%d beginbfrange\n", glyphIndexToCharacter.size())
+	// a CMap has at most 100 ranges by block
 	indexs := glyphIndexToCharacter.allIndexs()
-	for _, k := range indexs {
-		v, _ := glyphIndexToCharacter.runeByIndex(k)
-		fmt.Fprintf(buff, "<%04X><%04X><%04X>\n", k, k, v)
+	for start := 0; start < len(indexs); start += 100 {
+		end := minInt(start+100, len(indexs))
+		fmt.Fprintf(buff, "%d beginbfrange\n", end-start)
+		for _, k := range indexs[start:end] {
+			v, _ := glyphIndexToCharacter.runeByIndex(k)
+			fmt.Fprintf(buff, "<%04X><%04X><%s>\n", k, k, utf16Hex(v))
+		}
+		buff.WriteString("endbfrange\n")
 	}
-	buff.WriteString("endbfrange\n")
 	buff.WriteString(suffix)
 	buff.WriteString("\n")
 
@@ -98,19 +106,33 @@ func (u *UnicodeMap) write(w io.Writer, objID int) error {
 	return nil
 }
 
+// utf16Hex returns the UTF-16BE code units of r in hexadecimal, a surrogate pair for the characters beyond U+FFFF such as emoji.
+func utf16Hex(r rune) string {
+	var b strings.Builder
+	for _, u := range utf16.Encode([]rune{r}) {
+		fmt.Fprintf(&b, "%04X", u)
+	}
+	return b.String()
+}
+
 type mapGlyphIndexToCharacter struct {
 	runes  []rune
 	indexs []int
+	first  map[int]rune // first rune of each index
 }
 
 func newMapGlyphIndexToCharacter() *mapGlyphIndexToCharacter {
 	var m mapGlyphIndexToCharacter
+	m.first = make(map[int]rune)
 	return &m
 }
 
 func (m *mapGlyphIndexToCharacter) set(index int, r rune) {
 	m.runes = append(m.runes, r)
 	m.indexs = append(m.indexs, index)
+	if _, ok := m.first[index]; !ok {
+		m.first[index] = r
+	}
 }
 
 func (m *mapGlyphIndexToCharacter) size() int {
@@ -122,14 +144,6 @@ func (m *mapGlyphIndexToCharacter) allIndexs() []int {
 }
 
 func (m *mapGlyphIndexToCharacter) runeByIndex(index int) (rune, bool) {
-	var r rune
-	ok := false
-	for i, idx := range m.indexs {
-		if idx == index {
-			r = m.runes[i]
-			ok = true
-			break
-		}
-	}
+	r, ok := m.first[index]
 	return r, ok
 }
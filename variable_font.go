@@ -0,0 +1,1158 @@
+package gopdf
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrNotVariableFont is returned when TtfOption.Axes is set for a font without variation axes.
+var ErrNotVariableFont = errors.New("font is not a variable font")
+
+// ErrUnknownAxis is returned when TtfOption.Axes has an axis the variable font does not have.
+var ErrUnknownAxis = errors.New("unknown variation axis")
+
+// ErrInvalidVariableFont invalid variation tables
+var ErrInvalidVariableFont = errors.New("invalid variable font data")
+
+// variationTables are the tables of a variable font left out of its instances.
+var variationTables = []string{"fvar", "avar", "gvar", "cvar", "HVAR", "VVAR", "MVAR", "STAT"}
+
+// mvarTargets are the tables and offsets of the values varied by the tags of a MVAR table.
+var mvarTargets = map[string]struct {
+	table  string
+	offset int
+}{
+	"hasc": {"OS/2", 68}, "hdsc": {"OS/2", 70}, "hlgp": {"OS/2", 72}, "hcla": {"OS/2", 74}, "hcld": {"OS/2", 76},
+	"strs": {"OS/2", 26}, "stro": {"OS/2", 28}, "xhgt": {"OS/2", 86}, "cpht": {"OS/2", 88},
+	"undo": {"post", 8}, "unds": {"post", 10},
+}
+
+// instanceVariableFont returns the instance of a variable font at axes, the coordinates of its axes by tag in user units
+// (for instance {"wght": 700}). The outlines, advance widths and metrics are set to their values at these coordinates,
+// the axes left out being at their default value, and the variation tables are removed.
+func instanceVariableFont(fontData []byte, axes map[string]float64) ([]byte, error) {
+	flavor, tables, err := sfntTables(fontData)
+	if err != nil {
+		return nil, err
+	}
+	fvar, ok := tables["fvar"]
+	if !ok {
+		return nil, ErrNotVariableFont
+	}
+	coords, err := normalizedCoordinates(fvar, tables["avar"], axes)
+	if err != nil {
+		return nil, err
+	}
+	// the tables changed are copied, fontData can be the data given by the caller
+	for _, tag := range []string{"head", "hhea", "OS/2", "post"} {
+		if t, ok := tables[tag]; ok {
+			tables[tag] = append([]byte(nil), t...)
+		}
+	}
+	head, hhea, maxp := tables["head"], tables["hhea"], tables["maxp"]
+	if len(head) < 54 || len(hhea) < 36 || len(maxp) < 6 {
+		return nil, ErrInvalidVariableFont
+	}
+	numGlyphs := int(binary.BigEndian.Uint16(maxp[4:]))
+	advances, lsbs, err := parseHmtx(tables["hmtx"], int(binary.BigEndian.Uint16(hhea[34:])), numGlyphs)
+	if err != nil {
+		return nil, err
+	}
+
+	var advanceDeltas []float64
+	if cff2, ok := tables["CFF2"]; ok {
+		if tables["CFF2"], err = instanceCFF2(cff2, coords); err != nil {
+			return nil, err
+		}
+	} else if _, ok := tables["gvar"]; ok {
+		if tables["glyf"], tables["loca"], advanceDeltas, err = instanceGlyf(tables, coords, lsbs); err != nil {
+			return nil, err
+		}
+		binary.BigEndian.PutUint16(head[50:], 1) // long loca offsets
+	}
+	if hvar, ok := tables["HVAR"]; ok {
+		// the advance widths of HVAR are preferred to the phantom points of gvar
+		if advanceDeltas, err = hvarAdvanceDeltas(hvar, coords, numGlyphs); err != nil {
+			return nil, err
+		}
+	}
+	for g, delta := range advanceDeltas {
+		advances[g] = int(math.Round(float64(advances[g]) + delta))
+	}
+	if mvar, ok := tables["MVAR"]; ok {
+		if err := applyMVAR(mvar, coords, tables); err != nil {
+			return nil, err
+		}
+	}
+	if weight, ok := axes["wght"]; ok && len(tables["OS/2"]) >= 6 {
+		binary.BigEndian.PutUint16(tables["OS/2"][4:], uint16(math.Max(1, math.Min(1000, math.Round(weight)))))
+	}
+
+	var hmtx []byte
+	advanceWidthMax := 0
+	for g := range advances {
+		hmtx = appendInt16s(hmtx, advances[g], lsbs[g])
+		advanceWidthMax = maxInt(advanceWidthMax, advances[g])
+	}
+	tables["hmtx"] = hmtx
+	binary.BigEndian.PutUint16(hhea[10:], uint16(advanceWidthMax))
+	binary.BigEndian.PutUint16(hhea[34:], uint16(numGlyphs))
+	for _, tag := range variationTables {
+		delete(tables, tag)
+	}
+	return sfntBytes(flavor, tables), nil
+}
+
+// sfntTables returns the flavor and the tables of TrueType or OpenType font data.
+func sfntTables(fontData []byte) (uint32, map[string][]byte, error) {
+	if len(fontData) < 12 {
+		return 0, nil, ErrInvalidVariableFont
+	}
+	numTables := int(binary.BigEndian.Uint16(fontData[4:]))
+	if len(fontData) < 12+numTables*16 {
+		return 0, nil, ErrInvalidVariableFont
+	}
+	tables := make(map[string][]byte, numTables)
+	for i := 0; i < numTables; i++ {
+		entry := fontData[12+i*16:]
+		offset, length := int(binary.BigEndian.Uint32(entry[8:])), int(binary.BigEndian.Uint32(entry[12:]))
+		if offset+length > len(fontData) {
+			return 0, nil, ErrInvalidVariableFont
+		}
+		tables[string(entry[:4])] = fontData[offset : offset+length]
+	}
+	return binary.BigEndian.Uint32(fontData), tables, nil
+}
+
+// parseHmtx returns the advance width and left side bearing of each glyph.
+func parseHmtx(hmtx []byte, numHMetrics, numGlyphs int) ([]int, []int, error) {
+	if numHMetrics < 1 || numHMetrics > numGlyphs {
+		return nil, nil, ErrInvalidVariableFont
+	}
+	r := &fontReader{b: hmtx}
+	advances, lsbs := make([]int, numGlyphs), make([]int, numGlyphs)
+	for g := 0; g < numGlyphs; g++ {
+		if g < numHMetrics {
+			advances[g] = r.u16()
+		} else {
+			advances[g] = advances[numHMetrics-1]
+		}
+		lsbs[g] = r.i16()
+	}
+	if r.err != nil {
+		return nil, nil, ErrInvalidVariableFont
+	}
+	return advances, lsbs, nil
+}
+
+// normalizedCoordinates converts axes in user units to the normalized coordinates of each axis of the fvar table,
+// from -1 at the minimum to 1 at the maximum with the default at 0, mapped by the avar table.
+func normalizedCoordinates(fvar, avar []byte, axes map[string]float64) ([]float64, error) {
+	r := &fontReader{b: fvar}
+	r.u32() // version
+	axesOffset := r.u16()
+	r.u16() // reserved
+	axisCount, axisSize := r.u16(), r.u16()
+	if r.err != nil || axisSize < 16 || axesOffset+axisCount*axisSize > len(fvar) {
+		return nil, ErrInvalidVariableFont
+	}
+	fixed := func(b []byte) float64 {
+		return float64(int32(binary.BigEndian.Uint32(b))) / 65536
+	}
+	coords := make([]float64, axisCount)
+	known := map[string]bool{}
+	for i := range coords {
+		axis := fvar[axesOffset+i*axisSize:]
+		tag := string(axis[:4])
+		known[tag] = true
+		v, ok := axes[tag]
+		if !ok {
+			continue
+		}
+		min, def, max := fixed(axis[4:]), fixed(axis[8:]), fixed(axis[12:])
+		v = math.Max(min, math.Min(max, v))
+		if v < def && def > min {
+			coords[i] = (v - def) / (def - min)
+		} else if v > def && max > def {
+			coords[i] = (v - def) / (max - def)
+		}
+	}
+	for tag := range axes {
+		if !known[tag] {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownAxis, tag)
+		}
+	}
+
+	if len(avar) >= 8 {
+		r := &fontReader{b: avar[8:]}
+		for i := 0; i < int(binary.BigEndian.Uint16(avar[6:])) && i < len(coords); i++ {
+			count := r.u16()
+			from, to := make([]float64, count), make([]float64, count)
+			for j := range from {
+				from[j], to[j] = f2dot14(r.i16()), f2dot14(r.i16())
+			}
+			if r.err != nil {
+				return nil, ErrInvalidVariableFont
+			}
+			coords[i] = avarMap(coords[i], from, to)
+		}
+	}
+	for i, c := range coords {
+		coords[i] = math.Round(c*16384) / 16384
+	}
+	return coords, nil
+}
+
+// avarMap maps a normalized coordinate with the segments of an axis of the avar table.
+func avarMap(v float64, from, to []float64) float64 {
+	if len(from) == 0 {
+		return v
+	}
+	if v <= from[0] {
+		return to[0]
+	}
+	for j := 1; j < len(from); j++ {
+		if v <= from[j] {
+			if from[j] == from[j-1] {
+				return to[j]
+			}
+			return to[j-1] + (v-from[j-1])*(to[j]-to[j-1])/(from[j]-from[j-1])
+		}
+	}
+	return to[len(to)-1]
+}
+
+func f2dot14(v int) float64 {
+	return float64(v) / 16384
+}
+
+// axisScalar returns the scalar of the deltas of a region of an axis going from start to end with its peak at peak.
+func axisScalar(c, start, peak, end float64) float64 {
+	switch {
+	case peak == 0 || start > peak || peak > end || (start < 0 && end > 0) || c == peak:
+		return 1
+	case c <= start || c >= end:
+		return 0
+	case c < peak:
+		return (c - start) / (peak - start)
+	}
+	return (end - c) / (end - peak)
+}
+
+// itemVariationStore holds the deltas of the values varied by the HVAR, MVAR and CFF2 tables.
+type itemVariationStore struct {
+	regions [][][3]float64 // start, peak and end of each axis of each region
+	data    []itemVariationData
+}
+
+// itemVariationData holds the deltas of the items of an item variation store for some of its regions.
+type itemVariationData struct {
+	regionIndexes []int
+	deltas        [][]int // deltas of each item for each region
+}
+
+// parseItemVariationStore parses an item variation store.
+func parseItemVariationStore(b []byte) (*itemVariationStore, error) {
+	r := &fontReader{b: b}
+	r.u16() // format
+	regionListOffset := r.u32()
+	offsets := make([]int, r.u16())
+	for i := range offsets {
+		offsets[i] = r.u32()
+	}
+	if r.err != nil || regionListOffset > len(b) {
+		return nil, ErrInvalidVariableFont
+	}
+	store := &itemVariationStore{}
+	regions := &fontReader{b: b[regionListOffset:]}
+	axisCount, regionCount := regions.u16(), regions.u16()
+	if axisCount*regionCount*6 > len(b) {
+		return nil, ErrInvalidVariableFont
+	}
+	for i := 0; i < regionCount; i++ {
+		region := make([][3]float64, axisCount)
+		for j := range region {
+			region[j] = [3]float64{f2dot14(regions.i16()), f2dot14(regions.i16()), f2dot14(regions.i16())}
+		}
+		store.regions = append(store.regions, region)
+	}
+	if regions.err != nil {
+		return nil, ErrInvalidVariableFont
+	}
+
+	for _, offset := range offsets {
+		if offset > len(b) {
+			return nil, ErrInvalidVariableFont
+		}
+		r := &fontReader{b: b[offset:]}
+		itemCount, wordDeltaCount, regionIndexCount := r.u16(), r.u16(), r.u16()
+		if itemCount*regionIndexCount > len(b) {
+			return nil, ErrInvalidVariableFont
+		}
+		var data itemVariationData
+		for i := 0; i < regionIndexCount; i++ {
+			index := r.u16()
+			if index >= regionCount {
+				return nil, ErrInvalidVariableFont
+			}
+			data.regionIndexes = append(data.regionIndexes, index)
+		}
+		// the first deltas of each item are words, or 32-bit values with long words, the others bytes or words
+		words, long := wordDeltaCount&0x7fff, wordDeltaCount&0x8000 != 0
+		for i := 0; i < itemCount; i++ {
+			deltas := make([]int, regionIndexCount)
+			for j := range deltas {
+				switch {
+				case j < words && long:
+					deltas[j] = int(int32(r.u32()))
+				case j < words || long:
+					deltas[j] = r.i16()
+				default:
+					deltas[j] = int(int8(r.u8()))
+				}
+			}
+			data.deltas = append(data.deltas, deltas)
+		}
+		if r.err != nil {
+			return nil, ErrInvalidVariableFont
+		}
+		store.data = append(store.data, data)
+	}
+	return store, nil
+}
+
+// scalars returns the scalars of the regions of each item variation data at coords.
+func (s *itemVariationStore) scalars(coords []float64) [][]float64 {
+	regionScalars := make([]float64, len(s.regions))
+	for i, region := range s.regions {
+		scalar := 1.0
+		for axis, r := range region {
+			c := 0.0
+			if axis < len(coords) {
+				c = coords[axis]
+			}
+			scalar *= axisScalar(c, r[0], r[1], r[2])
+		}
+		regionScalars[i] = scalar
+	}
+	scalars := make([][]float64, len(s.data))
+	for i, data := range s.data {
+		for _, region := range data.regionIndexes {
+			scalars[i] = append(scalars[i], regionScalars[region])
+		}
+	}
+	return scalars
+}
+
+// delta returns the delta of the item inner of the item variation data outer, 0 when there is no such item.
+func (s *itemVariationStore) delta(scalars [][]float64, outer, inner int) float64 {
+	if outer >= len(s.data) || inner >= len(s.data[outer].deltas) {
+		return 0
+	}
+	delta := 0.0
+	for j, d := range s.data[outer].deltas[inner] {
+		delta += float64(d) * scalars[outer][j]
+	}
+	return delta
+}
+
+// parseDeltaSetIndexMap returns the outer and inner indexes of the deltas of each item of a delta set index map.
+func parseDeltaSetIndexMap(b []byte) ([][2]int, error) {
+	r := &fontReader{b: b}
+	format, entryFormat := r.u8(), r.u8()
+	count := 0
+	if format == 0 {
+		count = r.u16()
+	} else {
+		count = r.u32()
+	}
+	entrySize, innerBits := uint(entryFormat>>4&3+1), uint(entryFormat&0x0f+1)
+	if r.err != nil || count*int(entrySize) > len(b) {
+		return nil, ErrInvalidVariableFont
+	}
+	entries := make([][2]int, count)
+	for i := range entries {
+		v := 0
+		for _, c := range r.read(int(entrySize)) {
+			v = v<<8 | int(c)
+		}
+		entries[i] = [2]int{v >> innerBits, v & (1<<innerBits - 1)}
+	}
+	return entries, nil
+}
+
+// deltaSetIndex returns the outer and inner indexes of the deltas of item i,
+// the items past the end of the map using its last entry and the items of a missing map being in the first data.
+func deltaSetIndex(entries [][2]int, i int) (int, int) {
+	switch {
+	case entries == nil:
+		return 0, i
+	case len(entries) == 0:
+		return 0xffff, 0xffff
+	case i >= len(entries):
+		i = len(entries) - 1
+	}
+	return entries[i][0], entries[i][1]
+}
+
+// hvarAdvanceDeltas returns the deltas of the advance width of each glyph at coords.
+func hvarAdvanceDeltas(hvar []byte, coords []float64, numGlyphs int) ([]float64, error) {
+	if len(hvar) < 12 {
+		return nil, ErrInvalidVariableFont
+	}
+	storeOffset, mapOffset := int(binary.BigEndian.Uint32(hvar[4:])), int(binary.BigEndian.Uint32(hvar[8:]))
+	if storeOffset > len(hvar) || mapOffset > len(hvar) {
+		return nil, ErrInvalidVariableFont
+	}
+	store, err := parseItemVariationStore(hvar[storeOffset:])
+	if err != nil {
+		return nil, err
+	}
+	var entries [][2]int
+	if mapOffset != 0 {
+		if entries, err = parseDeltaSetIndexMap(hvar[mapOffset:]); err != nil {
+			return nil, err
+		}
+	}
+	scalars := store.scalars(coords)
+	deltas := make([]float64, numGlyphs)
+	for g := range deltas {
+		outer, inner := deltaSetIndex(entries, g)
+		deltas[g] = store.delta(scalars, outer, inner)
+	}
+	return deltas, nil
+}
+
+// applyMVAR adds the deltas of the MVAR table at coords to the metrics of the OS/2 and post tables.
+func applyMVAR(mvar []byte, coords []float64, tables map[string][]byte) error {
+	if len(mvar) < 12 {
+		return ErrInvalidVariableFont
+	}
+	recordSize, count := int(binary.BigEndian.Uint16(mvar[6:])), int(binary.BigEndian.Uint16(mvar[8:]))
+	storeOffset := int(binary.BigEndian.Uint16(mvar[10:]))
+	if count == 0 || storeOffset == 0 {
+		return nil
+	}
+	if recordSize < 8 || 12+count*recordSize > len(mvar) || storeOffset > len(mvar) {
+		return ErrInvalidVariableFont
+	}
+	store, err := parseItemVariationStore(mvar[storeOffset:])
+	if err != nil {
+		return err
+	}
+	scalars := store.scalars(coords)
+	for i := 0; i < count; i++ {
+		record := mvar[12+i*recordSize:]
+		target, ok := mvarTargets[string(record[:4])]
+		table := tables[target.table]
+		if !ok || target.offset+2 > len(table) {
+			continue
+		}
+		delta := store.delta(scalars, int(binary.BigEndian.Uint16(record[4:])), int(binary.BigEndian.Uint16(record[6:])))
+		v := int(int16(binary.BigEndian.Uint16(table[target.offset:])))
+		binary.BigEndian.PutUint16(table[target.offset:], uint16(v+int(math.Round(delta))))
+	}
+	return nil
+}
+
+// gvarTable holds the variations of the glyphs of a gvar table.
+type gvarTable struct {
+	axisCount    int
+	sharedTuples [][]float64
+	glyphs       [][]byte // glyph variation data of each glyph
+}
+
+// parseGvar parses a gvar table.
+func parseGvar(b []byte) (*gvarTable, error) {
+	r := &fontReader{b: b}
+	r.u32() // version
+	gvar := &gvarTable{axisCount: r.u16()}
+	sharedCount, sharedOffset := r.u16(), r.u32()
+	glyphCount, flags, dataOffset := r.u16(), r.u16(), r.u32()
+	offsets := make([]int, glyphCount+1)
+	for i := range offsets {
+		if flags&1 != 0 {
+			offsets[i] = r.u32()
+		} else {
+			offsets[i] = r.u16() * 2
+		}
+	}
+	if r.err != nil || sharedOffset > len(b) {
+		return nil, ErrInvalidVariableFont
+	}
+	shared := &fontReader{b: b[sharedOffset:]}
+	for i := 0; i < sharedCount; i++ {
+		gvar.sharedTuples = append(gvar.sharedTuples, readTuple(shared, gvar.axisCount))
+	}
+	if shared.err != nil {
+		return nil, ErrInvalidVariableFont
+	}
+	for g := 0; g < glyphCount; g++ {
+		start, end := dataOffset+offsets[g], dataOffset+offsets[g+1]
+		if start > end || end > len(b) {
+			return nil, ErrInvalidVariableFont
+		}
+		gvar.glyphs = append(gvar.glyphs, b[start:end])
+	}
+	return gvar, nil
+}
+
+// readTuple reads the coordinates of a tuple.
+func readTuple(r *fontReader, axisCount int) []float64 {
+	tuple := make([]float64, axisCount)
+	for i := range tuple {
+		tuple[i] = f2dot14(r.i16())
+	}
+	return tuple
+}
+
+// glyphDeltas returns the deltas of the points of glyph g at coords, its points followed by its 4 phantom points.
+// endPts are the last points of the contours of a simple glyph, to infer the deltas of the points a tuple leaves out.
+func (v *gvarTable) glyphDeltas(g int, coords []float64, points []glyphPoint, endPts []int) ([]float64, []float64, error) {
+	n := len(points)
+	dx, dy := make([]float64, n), make([]float64, n)
+	if g >= len(v.glyphs) || len(v.glyphs[g]) == 0 {
+		return dx, dy, nil
+	}
+	data := v.glyphs[g]
+	headers := &fontReader{b: data}
+	count, dataOffset := headers.u16(), headers.u16()
+	if dataOffset > len(data) {
+		return nil, nil, ErrInvalidVariableFont
+	}
+	serialized := &fontReader{b: data[dataOffset:]}
+	var sharedPoints []int
+	if count&0x8000 != 0 {
+		sharedPoints = readPackedPoints(serialized)
+	}
+	for i := 0; i < count&0x0fff; i++ {
+		size, index := headers.u16(), headers.u16()
+		var peak, start, end []float64
+		if index&0x8000 != 0 {
+			peak = readTuple(headers, v.axisCount)
+		} else if index&0x0fff < len(v.sharedTuples) {
+			peak = v.sharedTuples[index&0x0fff]
+		} else {
+			return nil, nil, ErrInvalidVariableFont
+		}
+		if index&0x4000 != 0 {
+			start, end = readTuple(headers, v.axisCount), readTuple(headers, v.axisCount)
+		}
+		tuple := serialized.sub(size)
+		if headers.err != nil || serialized.err != nil {
+			return nil, nil, ErrInvalidVariableFont
+		}
+
+		scalar := 1.0
+		for axis, p := range peak {
+			c := 0.0
+			if axis < len(coords) {
+				c = coords[axis]
+			}
+			// without intermediate region, the region goes from 0 to the peak
+			lo, hi := math.Min(0, p), math.Max(0, p)
+			if start != nil {
+				lo, hi = start[axis], end[axis]
+			}
+			scalar *= axisScalar(c, lo, p, hi)
+		}
+		if scalar == 0 {
+			continue
+		}
+
+		tuplePoints := sharedPoints
+		if index&0x2000 != 0 {
+			tuplePoints = readPackedPoints(tuple)
+		}
+		m := len(tuplePoints)
+		if tuplePoints == nil {
+			m = n
+		}
+		xs, ys := readPackedDeltas(tuple, m), readPackedDeltas(tuple, m)
+		if tuple.err != nil || len(xs) < m || len(ys) < m {
+			return nil, nil, ErrInvalidVariableFont
+		}
+		if tuplePoints == nil {
+			for j := range dx {
+				dx[j] += scalar * xs[j]
+				dy[j] += scalar * ys[j]
+			}
+			continue
+		}
+		tdx, tdy, touched := make([]float64, n), make([]float64, n), make([]bool, n)
+		for j, p := range tuplePoints {
+			if p < n {
+				tdx[p], tdy[p], touched[p] = xs[j], ys[j], true
+			}
+		}
+		interpolateUntouched(points, endPts, tdx, tdy, touched)
+		for j := range dx {
+			dx[j] += scalar * tdx[j]
+			dy[j] += scalar * tdy[j]
+		}
+	}
+	return dx, dy, nil
+}
+
+// readPackedPoints reads packed point numbers, nil meaning all the points of the glyph.
+func readPackedPoints(r *fontReader) []int {
+	count := r.u8()
+	if count&0x80 != 0 {
+		count = (count&0x7f)<<8 | r.u8()
+	}
+	if count == 0 {
+		return nil
+	}
+	points := make([]int, 0, count)
+	p := 0
+	for len(points) < count && r.err == nil {
+		control := r.u8()
+		for i := 0; i <= control&0x7f && len(points) < count; i++ {
+			if control&0x80 != 0 {
+				p += r.u16()
+			} else {
+				p += r.u8()
+			}
+			points = append(points, p)
+		}
+	}
+	return points
+}
+
+// readPackedDeltas reads count packed deltas.
+func readPackedDeltas(r *fontReader, count int) []float64 {
+	deltas := make([]float64, 0, count)
+	for len(deltas) < count && r.err == nil {
+		control := r.u8()
+		for i := 0; i <= control&0x3f && len(deltas) < count; i++ {
+			switch {
+			case control&0x80 != 0:
+				deltas = append(deltas, 0)
+			case control&0x40 != 0:
+				deltas = append(deltas, float64(r.i16()))
+			default:
+				deltas = append(deltas, float64(int8(r.u8())))
+			}
+		}
+	}
+	return deltas
+}
+
+// interpolateUntouched infers the deltas of the points of each contour left out of a tuple
+// from the deltas of the touched points before and after them.
+func interpolateUntouched(points []glyphPoint, endPts []int, dx, dy []float64, touched []bool) {
+	start := 0
+	for _, end := range endPts {
+		if end < start || end >= len(points) {
+			return
+		}
+		var refs []int
+		for i := start; i <= end; i++ {
+			if touched[i] {
+				refs = append(refs, i)
+			}
+		}
+		if len(refs) > 0 && len(refs) <= end-start {
+			for k, ref := range refs {
+				next := refs[(k+1)%len(refs)]
+				for i := ref + 1; ; i++ {
+					if i > end {
+						i = start
+					}
+					if i == next {
+						break
+					}
+					dx[i] = iupDelta(points[i].x, points[ref].x, points[next].x, dx[ref], dx[next])
+					dy[i] = iupDelta(points[i].y, points[ref].y, points[next].y, dy[ref], dy[next])
+				}
+			}
+		}
+		start = end + 1
+	}
+}
+
+// iupDelta returns the delta of coordinate c between the reference coordinates c1 and c2 with the deltas d1 and d2.
+func iupDelta(c, c1, c2 int, d1, d2 float64) float64 {
+	if c1 > c2 {
+		c1, c2, d1, d2 = c2, c1, d2, d1
+	}
+	switch {
+	case c1 == c2:
+		if d1 == d2 {
+			return d1
+		}
+		return 0
+	case c <= c1:
+		return d1
+	case c >= c2:
+		return d2
+	}
+	return d1 + float64(c-c1)*(d2-d1)/float64(c2-c1)
+}
+
+// glyfGlyph is a glyph of the glyf table.
+type glyfGlyph struct {
+	contours     int // -1 for a composite glyph
+	bbox         [4]int
+	endPts       []int
+	points       []glyphPoint
+	instructions []byte
+	overlap      bool
+	components   []glyfComponent
+}
+
+// glyfComponent is a component of a composite glyph.
+type glyfComponent struct {
+	flags     int
+	glyph     int
+	dx, dy    int    // offset, or the numbers of the matched points without argsAreXYValues
+	transform []byte // scale, x and y scales or 2x2 matrix
+}
+
+// parseGlyfGlyph parses a glyph of the glyf table.
+func parseGlyfGlyph(data []byte) (*glyfGlyph, error) {
+	r := &fontReader{b: data}
+	glyph := &glyfGlyph{contours: r.i16()}
+	for i := range glyph.bbox {
+		glyph.bbox[i] = r.i16()
+	}
+	if glyph.contours >= 0 {
+		glyph.endPts = make([]int, glyph.contours)
+		for i := range glyph.endPts {
+			glyph.endPts[i] = r.u16()
+		}
+		n := 0
+		if glyph.contours > 0 {
+			n = glyph.endPts[glyph.contours-1] + 1
+		}
+		glyph.instructions = r.read(r.u16())
+		flags := make([]int, 0, n)
+		for len(flags) < n && r.err == nil {
+			flag := r.u8()
+			flags = append(flags, flag)
+			if flag&0x08 != 0 {
+				for repeat := r.u8(); repeat > 0 && len(flags) < n; repeat-- {
+					flags = append(flags, flag)
+				}
+			}
+		}
+		glyph.overlap = len(flags) > 0 && flags[0]&0x40 != 0
+		glyph.points = make([]glyphPoint, len(flags))
+		// a coordinate is a byte with its sign in the flag, or the same as the previous one, or a short
+		coordinate := func(flag int, short, same int) int {
+			switch {
+			case flag&short != 0 && flag&same != 0:
+				return r.u8()
+			case flag&short != 0:
+				return -r.u8()
+			case flag&same != 0:
+				return 0
+			}
+			return r.i16()
+		}
+		x, y := 0, 0
+		for i, flag := range flags {
+			x += coordinate(flag, 0x02, 0x10)
+			glyph.points[i] = glyphPoint{x: x, onCurve: flag&0x01 != 0}
+		}
+		for i, flag := range flags {
+			y += coordinate(flag, 0x04, 0x20)
+			glyph.points[i].y = y
+		}
+	} else {
+		haveInstructions := false
+		for more := true; more && r.err == nil; {
+			c := glyfComponent{flags: r.u16(), glyph: r.u16()}
+			more = c.flags&moreComponents != 0
+			haveInstructions = haveInstructions || c.flags&weHaveInstructions != 0
+			words, xy := c.flags&arg1and2areWords != 0, c.flags&argsAreXYValues != 0
+			switch {
+			case words && xy:
+				c.dx, c.dy = r.i16(), r.i16()
+			case words:
+				c.dx, c.dy = r.u16(), r.u16()
+			case xy:
+				c.dx, c.dy = int(int8(r.u8())), int(int8(r.u8()))
+			default:
+				c.dx, c.dy = r.u8(), r.u8()
+			}
+			switch {
+			case c.flags&hasScale != 0:
+				c.transform = r.read(2)
+			case c.flags&xAndYScale != 0:
+				c.transform = r.read(4)
+			case c.flags&twoByTwo != 0:
+				c.transform = r.read(8)
+			}
+			glyph.components = append(glyph.components, c)
+		}
+		if haveInstructions {
+			glyph.instructions = r.read(r.u16())
+		}
+	}
+	if r.err != nil {
+		return nil, ErrInvalidVariableFont
+	}
+	return glyph, nil
+}
+
+// apply returns the position of the point x, y of the component glyph in the composite glyph.
+func (c glyfComponent) apply(x, y int) (int, int) {
+	a, b, cc, d := 1.0, 0.0, 0.0, 1.0
+	f := func(i int) float64 {
+		return f2dot14(int(int16(binary.BigEndian.Uint16(c.transform[i:]))))
+	}
+	switch {
+	case c.flags&hasScale != 0:
+		a, d = f(0), f(0)
+	case c.flags&xAndYScale != 0:
+		a, d = f(0), f(2)
+	case c.flags&twoByTwo != 0:
+		a, b, cc, d = f(0), f(2), f(4), f(6)
+	}
+	fx, fy := float64(x), float64(y)
+	x, y = int(math.Round(a*fx+cc*fy)), int(math.Round(b*fx+d*fy))
+	if c.flags&argsAreXYValues != 0 {
+		// the offset of a component positioned by matching points is left out, such components are rare
+		x, y = x+c.dx, y+c.dy
+	}
+	return x, y
+}
+
+// bytes encodes the glyph.
+func (glyph *glyfGlyph) bytes() []byte {
+	if glyph.contours >= 0 {
+		return simpleGlyphBytes(glyph.contours, glyph.bbox, glyph.endPts, glyph.instructions, glyph.points, glyph.overlap)
+	}
+	b := appendInt16s(nil, -1, glyph.bbox[0], glyph.bbox[1], glyph.bbox[2], glyph.bbox[3])
+	for i, c := range glyph.components {
+		flags := c.flags &^ (moreComponents | weHaveInstructions)
+		if i < len(glyph.components)-1 {
+			flags |= moreComponents
+		} else if len(glyph.instructions) > 0 {
+			flags |= weHaveInstructions
+		}
+		// the arguments stay bytes unless they no longer fit
+		min, max := 0, 255
+		if flags&argsAreXYValues != 0 {
+			min, max = -128, 127
+		}
+		if c.dx < min || c.dx > max || c.dy < min || c.dy > max {
+			flags |= arg1and2areWords
+		}
+		if flags&arg1and2areWords != 0 {
+			b = appendInt16s(b, flags, c.glyph, c.dx, c.dy)
+		} else {
+			b = append(appendInt16s(b, flags, c.glyph), byte(c.dx), byte(c.dy))
+		}
+		b = append(b, c.transform...)
+	}
+	if len(glyph.instructions) > 0 {
+		b = appendInt16s(b, len(glyph.instructions))
+		b = append(b, glyph.instructions...)
+	}
+	return b
+}
+
+// instanceGlyf applies the deltas of the gvar table at coords to the glyphs of the glyf table.
+// It returns the glyf and loca tables, the loca table with long offsets, and the delta of the advance width
+// of each glyph given by its phantom points. The left side bearings lsbs are updated.
+func instanceGlyf(tables map[string][]byte, coords []float64, lsbs []int) ([]byte, []byte, []float64, error) {
+	gvar, err := parseGvar(tables["gvar"])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	glyf, head := tables["glyf"], tables["head"]
+	offsets := glyfOffsets(tables["loca"], binary.BigEndian.Uint16(head[50:]) == 1)
+	numGlyphs := minInt(len(lsbs), len(offsets)-1)
+	if numGlyphs < 0 {
+		return nil, nil, nil, ErrInvalidVariableFont
+	}
+
+	glyphs := make([]*glyfGlyph, numGlyphs)
+	advanceDeltas := make([]float64, len(lsbs))
+	for g := range glyphs {
+		start, end := offsets[g], offsets[g+1]
+		if start > end || end > len(glyf) {
+			return nil, nil, nil, ErrInvalidVariableFont
+		}
+		var glyph *glyfGlyph
+		var points []glyphPoint
+		if start < end {
+			if glyph, err = parseGlyfGlyph(glyf[start:end]); err != nil {
+				return nil, nil, nil, err
+			}
+			points = append(points, glyph.points...)
+			// the points of a composite glyph are the offsets of its components
+			for _, c := range glyph.components {
+				points = append(points, glyphPoint{x: c.dx, y: c.dy})
+			}
+		}
+		n := len(points)
+		points = append(points, make([]glyphPoint, 4)...)
+		var endPts []int
+		if glyph != nil {
+			endPts = glyph.endPts
+		}
+		dx, dy, err := gvar.glyphDeltas(g, coords, points, endPts)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if glyph != nil {
+			for i := range glyph.points {
+				glyph.points[i].x += int(math.Round(dx[i]))
+				glyph.points[i].y += int(math.Round(dy[i]))
+			}
+			for i, c := range glyph.components {
+				if c.flags&argsAreXYValues != 0 {
+					glyph.components[i].dx += int(math.Round(dx[i]))
+					glyph.components[i].dy += int(math.Round(dy[i]))
+				}
+			}
+		}
+		// the left and right phantom points are at the origin and at the advance width
+		advanceDeltas[g] = dx[n+1] - dx[n]
+		lsbs[g] -= int(math.Round(dx[n]))
+		glyphs[g] = glyph
+	}
+
+	// the bounding boxes of composite glyphs are those of their components, computed first
+	done := make([]bool, numGlyphs)
+	var bbox func(g, depth int) ([4]int, bool)
+	bbox = func(g, depth int) ([4]int, bool) {
+		if g >= numGlyphs || glyphs[g] == nil || depth > 16 {
+			return [4]int{}, false
+		}
+		glyph := glyphs[g]
+		if done[g] {
+			return glyph.bbox, true
+		}
+		box, found := [4]int{}, false
+		extend := func(x, y int) {
+			if !found {
+				box, found = [4]int{x, y, x, y}, true
+			}
+			box = [4]int{minInt(box[0], x), minInt(box[1], y), maxInt(box[2], x), maxInt(box[3], y)}
+		}
+		for _, p := range glyph.points {
+			extend(p.x, p.y)
+		}
+		for _, c := range glyph.components {
+			if child, ok := bbox(c.glyph, depth+1); ok {
+				for _, corner := range [][2]int{{child[0], child[1]}, {child[0], child[3]}, {child[2], child[1]}, {child[2], child[3]}} {
+					extend(c.apply(corner[0], corner[1]))
+				}
+			}
+		}
+		lsbs[g] += box[0] - glyph.bbox[0]
+		glyph.bbox, done[g] = box, true
+		return box, true
+	}
+
+	var newGlyf, loca []byte
+	for g, glyph := range glyphs {
+		loca = append(loca, byte(len(newGlyf)>>24), byte(len(newGlyf)>>16), byte(len(newGlyf)>>8), byte(len(newGlyf)))
+		if glyph == nil {
+			continue
+		}
+		bbox(g, 0)
+		newGlyf = append(newGlyf, glyph.bytes()...)
+		for len(newGlyf)%4 != 0 {
+			newGlyf = append(newGlyf, 0)
+		}
+	}
+	loca = append(loca, byte(len(newGlyf)>>24), byte(len(newGlyf)>>16), byte(len(newGlyf)>>8), byte(len(newGlyf)))
+	return newGlyf, loca, advanceDeltas, nil
+}
+
+// instanceCFF2 resolves the blend operators of the charstrings and Private DICTs of a CFF2 table at coords,
+// the subroutines being inlined.
+func instanceCFF2(data []byte, coords []float64) ([]byte, error) {
+	c, err := parseCFF(data)
+	if err != nil {
+		return nil, err
+	}
+	if !c.cff2 {
+		return nil, ErrInvalidCFF
+	}
+	var scalars [][]float64
+	if c.varStore != nil {
+		store, err := parseItemVariationStore(c.varStore[2:])
+		if err != nil {
+			return nil, err
+		}
+		scalars = store.scalars(coords)
+	}
+	charStrings := make([][]byte, len(c.charStrings))
+	for g, code := range c.charStrings {
+		s := &charStringInstancer{font: c, scalars: scalars, fd: c.fontOf(g)}
+		if err := s.run(code); err != nil {
+			return nil, err
+		}
+		charStrings[g] = s.out
+	}
+	fonts := make([]cffPrivateFont, len(c.fonts))
+	for i, font := range c.fonts {
+		private, err := instanceCFFPrivate(font.private, scalars)
+		if err != nil {
+			return nil, err
+		}
+		fonts[i] = cffPrivateFont{dict: font.dict, private: private}
+	}
+	c.varStore, c.regionCounts = nil, nil
+	return c.writeCFF2(charStrings, nil, fonts), nil
+}
+
+// blend replaces the n default values of stack followed by their deltas for each region by their blended values.
+func blend(stack []float64, n int, scalars []float64) ([]float64, error) {
+	k := len(scalars)
+	base := len(stack) - n*(k+1)
+	if n < 0 || base < 0 {
+		return nil, ErrInvalidCFF
+	}
+	for i := 0; i < n; i++ {
+		for j, scalar := range scalars {
+			stack[base+i] += stack[base+n+i*k+j] * scalar
+		}
+	}
+	return stack[:base+n], nil
+}
+
+// instanceCFFPrivate resolves the blend operators of a CFF2 Private DICT, its vsindex and Subrs being left out.
+func instanceCFFPrivate(dict cffDict, scalars [][]float64) (cffDict, error) {
+	vsindex := 0
+	if v := dict.get(cffOpVSIndex); len(v) == 1 {
+		vsindex = int(v[0])
+	}
+	var private cffDict
+	var pending []float64 // blended values, operands of the next operator
+	for _, e := range dict {
+		switch e.op {
+		case cffOpVSIndex, cffOpSubrs:
+			continue
+		case cffOpBlend:
+			operands := append(pending, e.operands...)
+			if len(operands) == 0 || vsindex >= len(scalars) {
+				return nil, ErrInvalidCFF
+			}
+			var err error
+			if pending, err = blend(operands[:len(operands)-1], int(operands[len(operands)-1]), scalars[vsindex]); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if pending == nil {
+			private = append(private, e)
+			continue
+		}
+		entry := cffDictEntry{op: e.op, operands: append(pending, e.operands...)}
+		for _, v := range entry.operands {
+			entry.raw = append(entry.raw, cffNumberBytes(v)...)
+		}
+		private = append(private, entry)
+		pending = nil
+	}
+	return private, nil
+}
+
+// charStringInstancer rewrites a CFF2 charstring without subroutine calls, vsindex and blend operators.
+type charStringInstancer struct {
+	font    *cffFont
+	scalars [][]float64 // scalars of the regions of each item variation data
+	fd      int
+	vsindex int
+	stems   int
+	depth   int
+	stack   []float64
+	out     []byte
+}
+
+// run rewrites code, a charstring or a subroutine.
+func (s *charStringInstancer) run(code []byte) error {
+	font := s.font
+	for i := 0; i < len(code); {
+		v, size, err := readCharStringNumber(code, i)
+		if err != nil {
+			return err
+		} else if size > 0 {
+			s.stack = append(s.stack, v)
+			i += size
+			continue
+		}
+
+		b := code[i]
+		i++
+		switch b {
+		case 1, 3, 18, 23: // hstem, vstem, hstemhm, vstemhm
+			s.stems += len(s.stack) / 2
+		case 19, 20: // hintmask, cntrmask, preceded by the operands of an implicit vstemhm
+			s.stems += len(s.stack) / 2
+			mask := (s.stems + 7) / 8
+			if i+mask > len(code) {
+				return ErrInvalidCFF
+			}
+			s.write(b)
+			s.out = append(s.out, code[i:i+mask]...)
+			i += mask
+			continue
+		case 10, 29: // callsubr, callgsubr
+			if len(s.stack) == 0 || s.depth >= cffMaxSubrDepth || s.fd >= len(font.fonts) {
+				return errCFFUnsupportedCharString
+			}
+			subrs := font.globalSubrs
+			if b == 10 {
+				subrs = font.fonts[s.fd].subrs
+			}
+			index := int(s.stack[len(s.stack)-1]) + cffSubrBias(len(subrs))
+			s.stack = s.stack[:len(s.stack)-1]
+			if index < 0 || index >= len(subrs) {
+				return errCFFUnsupportedCharString
+			}
+			s.depth++
+			err := s.run(subrs[index])
+			s.depth--
+			if err != nil {
+				return err
+			}
+			continue
+		case 11: // return
+			return nil
+		case 15: // vsindex
+			if len(s.stack) > 0 {
+				s.vsindex = int(s.stack[len(s.stack)-1])
+			}
+			s.stack = s.stack[:0]
+			continue
+		case 16: // blend
+			if len(s.stack) == 0 || s.vsindex >= len(s.scalars) {
+				return errCFFUnsupportedCharString
+			}
+			n := int(s.stack[len(s.stack)-1])
+			if s.stack, err = blend(s.stack[:len(s.stack)-1], n, s.scalars[s.vsindex]); err != nil {
+				return err
+			}
+			continue
+		case 12:
+			if i >= len(code) {
+				return ErrInvalidCFF
+			}
+			s.write(b)
+			s.out = append(s.out, code[i])
+			i++
+			continue
+		}
+		s.write(b)
+	}
+	return nil
+}
+
+// write writes the operands of the stack followed by the operator op.
+func (s *charStringInstancer) write(op byte) {
+	for _, v := range s.stack {
+		s.out = append(s.out, charStringNumberBytes(v)...)
+	}
+	s.out = append(s.out, op)
+	s.stack = s.stack[:0]
+}
@@ -0,0 +1,242 @@
+package gopdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/signintech/gopdf/fontmaker/core"
+)
+
+func TestVariableFont(t *testing.T) {
+	err := initTesting()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ttfp := parseTestFont(t, "./test/res/LiberationSerif-Regular.ttf")
+	h, o := int(ttfp.Chars()['H']), int(ttfp.Chars()['o'])
+
+	for _, test := range []struct {
+		name    string
+		hvar    bool
+		wght    float64
+		shift   int // shift of the points of H and of the first contour of o
+		advance int // delta of the advance width of H
+	}{
+		{"default", false, 400, 0, 0},
+		{"light", false, 100, 0, 0},
+		{"bold", false, 900, 10, 20},
+		{"semibold", false, 650, 5, 10},
+		{"hvar", true, 900, 10, 50},
+	} {
+		data, err := instanceVariableFont(buildTestVariableFont(t, ttfp, test.hvar), map[string]float64{"wght": test.wght})
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		var result core.TTFParser
+		if err := result.ParseFontData(data); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		for _, tag := range variationTables {
+			if result.TableData(tag) != nil {
+				t.Fatalf("%s: unexpected %s table", test.name, tag)
+			}
+		}
+		for g := 0; g < int(ttfp.NumGlyphs()); g++ {
+			want := parseTestGlyph(t, ttfp, g)
+			for i := range want.points {
+				if g == h || (g == o && i <= want.endPts[0]) {
+					want.points[i].x += test.shift
+				}
+			}
+			if g == h {
+				want.bbox[0] += test.shift
+				want.bbox[2] += test.shift
+			}
+			got := parseTestGlyph(t, &result, g)
+			if want.contours < 0 {
+				// the bounding box of a composite glyph made of H moves with it
+				want.bbox = got.bbox
+			}
+			if !reflect.DeepEqual(got, want) && g != o {
+				t.Fatalf("%s: unexpected glyph %d: %+v, expected %+v", test.name, g, got, want)
+			} else if !reflect.DeepEqual(got.points, want.points) {
+				t.Fatalf("%s: unexpected points of glyph %d: %+v, expected %+v", test.name, g, got.points, want.points)
+			}
+			if width, want := result.Widths()[g], ttfp.Widths()[g]; g == h && int(width) != int(want)+test.advance {
+				t.Fatalf("%s: unexpected advance width %d of H, expected %d", test.name, width, int(want)+test.advance)
+			} else if g != h && width != want {
+				t.Fatalf("%s: unexpected advance width %d of glyph %d", test.name, width, g)
+			}
+		}
+	}
+
+	_, err = instanceVariableFont(buildTestVariableFont(t, ttfp, false), map[string]float64{"wdth": 75})
+	if !errors.Is(err, ErrUnknownAxis) {
+		t.Fatalf("expected ErrUnknownAxis, got %v", err)
+	}
+	_, err = instanceVariableFont(ttfp.FontData(), map[string]float64{"wght": 700})
+	if err != ErrNotVariableFont {
+		t.Fatalf("expected ErrNotVariableFont, got %v", err)
+	}
+
+	pdf := GoPdf{}
+	pdf.Start(Config{PageSize: *PageSizeA4})
+	pdf.AddPage()
+	font := buildTestVariableFont(t, ttfp, true)
+	for _, family := range []struct {
+		name string
+		wght float64
+	}{{"regular", 400}, {"bold", 700}} {
+		err = pdf.AddTTFFontDataWithOption(family.name, font, TtfOption{Axes: map[string]float64{"wght": family.wght}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	var widths []float64
+	for _, family := range []string{"regular", "bold"} {
+		err = pdf.SetFont(family, "", 14)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = pdf.Cell(nil, "Hello "+family)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pdf.Br(20)
+		w, err := pdf.MeasureTextWidth("HHH")
+		if err != nil {
+			t.Fatal(err)
+		}
+		widths = append(widths, w)
+	}
+	if widths[1] <= widths[0] {
+		t.Fatalf("expected bold H to be wider, got %v", widths)
+	}
+	err = pdf.WritePdf("./test/out/variable_font.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVariableCFF2(t *testing.T) {
+	num := func(v int) []byte { return []byte{28, byte(v >> 8), byte(v)} }
+	c, err := parseCFF(buildTestCFF(20, true, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := instanceCFF2(buildTestCFF(20, true, false), []float64{0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	instance, err := parseCFF(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if instance.varStore != nil || len(instance.globalSubrs) != 0 || instance.top.get(cffOpVariationStore) != nil {
+		t.Fatal("expected no variation store and no subroutines")
+	}
+	for g := range instance.charStrings {
+		// the hint mask followed by the global subroutine 0 and the local subroutine g % 4 inlined
+		want := bytes.Join([][]byte{{139, 149, 159, 149, 18, 19, 0xc0, 139, 139, 21}, charStringNumberBytes(float64(100 + g%4)),
+			{139, 5, 139, 239, 5}}, nil)
+		if !bytes.Equal(instance.charStrings[g], want) {
+			t.Fatalf("unexpected charstring %d %v, expected %v", g, instance.charStrings[g], want)
+		}
+	}
+	for _, font := range instance.fonts {
+		if font.subrs != nil || font.private.get(cffOpSubrs) != nil {
+			t.Fatal("unexpected local subroutines")
+		}
+	}
+
+	// 10 blended with a delta of 20 for the single region of the font
+	c.charStrings, c.fdSelect = [][]byte{bytes.Join([][]byte{num(10), num(20), num(1), {16}, num(0), {21}}, nil)}, c.fdSelect[:1]
+	data, err = instanceCFF2(c.writeCFF2(c.charStrings, c.globalSubrs, c.fonts), []float64{0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if instance, err = parseCFF(data); err != nil {
+		t.Fatal(err)
+	}
+	if want := []byte{20 + 139, 139, 21}; !bytes.Equal(instance.charStrings[0], want) {
+		t.Fatalf("unexpected charstring %v, expected %v", instance.charStrings[0], want)
+	}
+}
+
+// buildTestVariableFont adds a weight axis from 100 to 900 to LiberationSerif-Regular.
+// At the maximum weight, the points of H move right by 10 and its advance width grows by 20, 50 with a HVAR table,
+// and two points of the first contour of o move right by 10, moving the contour.
+func buildTestVariableFont(t *testing.T, ttfp *core.TTFParser, hvar bool) []byte {
+	tables := map[string][]byte{}
+	for tag := range ttfp.GetTables() {
+		tables[tag] = ttfp.TableData(tag)
+	}
+	numGlyphs := int(ttfp.NumGlyphs())
+	h, o := int(ttfp.Chars()['H']), int(ttfp.Chars()['o'])
+
+	var fvar bytes.Buffer
+	binary.Write(&fvar, binary.BigEndian, []uint16{1, 0, 16, 2, 1, 20, 0, 8})
+	fvar.WriteString("wght")
+	binary.Write(&fvar, binary.BigEndian, []int32{100 << 16, 400 << 16, 900 << 16})
+	binary.Write(&fvar, binary.BigEndian, []uint16{0, 256})
+	tables["fvar"] = fvar.Bytes()
+
+	// packed deltas, by runs of 64 at most
+	deltas := func(values ...int) []byte {
+		var b []byte
+		for len(values) > 0 {
+			run := values[:minInt(len(values), 64)]
+			values = values[len(run):]
+			b = append(b, byte(len(run)-1))
+			for _, v := range run {
+				b = append(b, byte(int8(v)))
+			}
+		}
+		return b
+	}
+	variations := make([][]byte, numGlyphs)
+	// all the points of H and its 4 phantom points, the right one at the advance width
+	points := len(parseTestGlyph(t, ttfp, h).points) + 4
+	xs, ys := make([]int, points), make([]int, points)
+	for i := range xs {
+		xs[i] = 10
+	}
+	xs[points-3] = 30
+	variations[h] = bytes.Join([][]byte{{0, 1, 0, 10}, {0, 0, 0xa0, 0, 0x40, 0}, {0}, deltas(xs...), deltas(ys...)}, nil)
+	// the points 0 and 1 of o
+	variations[o] = bytes.Join([][]byte{{0, 1, 0, 10}, {0, 0, 0xa0, 0, 0x40, 0}, {2, 1, 0, 1}, deltas(10, 10), deltas(0, 0)}, nil)
+	for _, g := range []int{h, o} {
+		binary.BigEndian.PutUint16(variations[g][4:], uint16(len(variations[g])-10))
+	}
+	var gvar bytes.Buffer
+	binary.Write(&gvar, binary.BigEndian, []uint16{1, 0, 1, 0})
+	binary.Write(&gvar, binary.BigEndian, []uint32{uint32(20 + 4*(numGlyphs+1))})
+	binary.Write(&gvar, binary.BigEndian, []uint16{uint16(numGlyphs), 1})
+	binary.Write(&gvar, binary.BigEndian, []uint32{uint32(20 + 4*(numGlyphs+1))})
+	offset := 0
+	for _, v := range variations {
+		binary.Write(&gvar, binary.BigEndian, uint32(offset))
+		offset += len(v)
+	}
+	binary.Write(&gvar, binary.BigEndian, uint32(offset))
+	gvar.Write(bytes.Join(variations, nil))
+	tables["gvar"] = gvar.Bytes()
+
+	if hvar {
+		var b bytes.Buffer
+		binary.Write(&b, binary.BigEndian, []uint16{1, 0})
+		binary.Write(&b, binary.BigEndian, []uint32{20, 0, 0, 0})
+		// an item variation store with a region peaking at the maximum weight and the advance delta of each glyph
+		binary.Write(&b, binary.BigEndian, []uint16{1, 0, 12, 1, 0, 22})
+		binary.Write(&b, binary.BigEndian, []uint16{1, 1, 0, 0x4000, 0x4000})
+		binary.Write(&b, binary.BigEndian, []uint16{uint16(numGlyphs), 0, 1, 0})
+		advances := make([]byte, numGlyphs)
+		advances[h] = 50
+		b.Write(advances)
+		tables["HVAR"] = b.Bytes()
+	}
+	return sfntBytes(0x00010000, tables)
+}
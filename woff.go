@@ -0,0 +1,103 @@
+package gopdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sort"
+)
+
+// ErrInvalidWOFF invalid WOFF or WOFF2 font data
+var ErrInvalidWOFF = errors.New("invalid WOFF font data")
+
+// maxSFNTSize is the largest size of the tables of a web font, larger fonts are rejected before they are decompressed.
+const maxSFNTSize = 1 << 28
+
+// isWOFF returns true when fontData is a WOFF or WOFF2 web font.
+func isWOFF(fontData []byte) bool {
+	return len(fontData) >= 4 && (string(fontData[:4]) == "wOFF" || string(fontData[:4]) == "wOF2")
+}
+
+// decodeWOFF decodes a WOFF or WOFF2 web font into the TrueType or OpenType font data it was made of.
+func decodeWOFF(fontData []byte) ([]byte, error) {
+	if string(fontData[:4]) == "wOF2" {
+		return decodeWOFF2(fontData)
+	}
+	if len(fontData) < 44 {
+		return nil, ErrInvalidWOFF
+	}
+	flavor := binary.BigEndian.Uint32(fontData[4:])
+	numTables := int(binary.BigEndian.Uint16(fontData[12:]))
+	totalSfntSize := int(binary.BigEndian.Uint32(fontData[16:]))
+	if len(fontData) < 44+numTables*20 || totalSfntSize > maxSFNTSize {
+		return nil, ErrInvalidWOFF
+	}
+	tables := make(map[string][]byte, numTables)
+	sfntSize := 0
+	for i := 0; i < numTables; i++ {
+		entry := fontData[44+i*20:]
+		tag := string(entry[:4])
+		offset := int(binary.BigEndian.Uint32(entry[4:]))
+		compLength := int(binary.BigEndian.Uint32(entry[8:]))
+		origLength := int(binary.BigEndian.Uint32(entry[12:]))
+		sfntSize += origLength
+		if offset+compLength > len(fontData) || compLength > origLength || sfntSize > totalSfntSize {
+			return nil, ErrInvalidWOFF
+		}
+		data := fontData[offset : offset+compLength]
+		if compLength < origLength {
+			// compressed with zlib, the tables that do not get smaller are stored as is
+			zr, err := zlib.NewReader(bytes.NewReader(data))
+			if err != nil {
+				return nil, err
+			}
+			data = make([]byte, origLength)
+			_, err = io.ReadFull(zr, data)
+			zr.Close()
+			if err != nil {
+				return nil, err
+			}
+		}
+		tables[tag] = data
+	}
+	return sfntBytes(flavor, tables), nil
+}
+
+// sfntBytes writes the tables as TrueType or OpenType font data, flavor being 0x00010000 or "OTTO".
+func sfntBytes(flavor uint32, tables map[string][]byte) []byte {
+	var tags []string
+	for tag := range tables {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	b := make([]byte, 12+16*len(tags))
+	binary.BigEndian.PutUint32(b, flavor)
+	writeSFNTDirectoryHeader(b[4:], len(tags))
+	for i, tag := range tags {
+		data := tables[tag]
+		padded := make([]byte, (len(data)+3)&^3)
+		copy(padded, data)
+		entry := b[12+16*i:]
+		copy(entry, tag)
+		binary.BigEndian.PutUint32(entry[4:], uint32(CheckSum(padded)))
+		binary.BigEndian.PutUint32(entry[8:], uint32(len(b)))
+		binary.BigEndian.PutUint32(entry[12:], uint32(len(data)))
+		b = append(b, padded...)
+	}
+	return b
+}
+
+// writeSFNTDirectoryHeader writes the number of tables and the binary search fields of a table directory.
+func writeSFNTDirectoryHeader(b []byte, numTables int) {
+	selector := 0
+	for (2 << uint(selector)) <= numTables {
+		selector++
+	}
+	binary.BigEndian.PutUint16(b, uint16(numTables))
+	binary.BigEndian.PutUint16(b[2:], uint16((1<<uint(selector))*16))
+	binary.BigEndian.PutUint16(b[4:], uint16(selector))
+	binary.BigEndian.PutUint16(b[6:], uint16(numTables*16-(1<<uint(selector))*16))
+}
@@ -0,0 +1,597 @@
+package gopdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"sort"
+
+	"github.com/andybalholm/brotli"
+)
+
+// woff2KnownTags are the tags encoded by their index in the table directory of a WOFF2 font.
+var woff2KnownTags = [...]string{
+	"cmap", "head", "hhea", "hmtx", "maxp", "name", "OS/2", "post", "cvt ", "fpgm", "glyf", "loca", "prep", "CFF ",
+	"VORG", "EBDT", "EBLC", "gasp", "hdmx", "kern", "LTSH", "PCLT", "VDMX", "vhea", "vmtx", "BASE", "GDEF", "GPOS",
+	"GSUB", "EBSC", "JSTF", "MATH", "CBDT", "CBLC", "COLR", "CPAL", "SVG ", "sbix", "acnt", "avar", "bdat", "bloc",
+	"bsln", "cvar", "fdsc", "feat", "fmtx", "fvar", "gvar", "hsty", "just", "lcar", "mort", "morx", "opbd", "prop",
+	"trak", "Zapf", "Silf", "Glat", "Gloc", "Feat", "Sill",
+}
+
+// woff2Table is a table of a WOFF2 font.
+type woff2Table struct {
+	tag         string
+	transformed bool
+	length      int // length in the decompressed stream, the transformed length of a transformed table
+	data        []byte
+	done        bool // the transform is reversed
+}
+
+// woff2Font is a font of a WOFF2 font, one of the fonts of a collection or the single font.
+type woff2Font struct {
+	flavor uint32
+	tables []int // indexes of the tables of the font
+}
+
+// fontReader reads the big endian values of font data, the first error is kept and the next reads return zeros.
+type fontReader struct {
+	b   []byte
+	err error
+}
+
+func (r *fontReader) read(n int) []byte {
+	if r.err != nil || n < 0 || n > len(r.b) {
+		r.err = ErrInvalidWOFF
+		return make([]byte, 0)
+	}
+	v := r.b[:n]
+	r.b = r.b[n:]
+	return v
+}
+
+func (r *fontReader) u8() int {
+	b := r.read(1)
+	if len(b) < 1 {
+		return 0
+	}
+	return int(b[0])
+}
+
+func (r *fontReader) u16() int {
+	b := r.read(2)
+	if len(b) < 2 {
+		return 0
+	}
+	return int(binary.BigEndian.Uint16(b))
+}
+
+func (r *fontReader) i16() int {
+	return int(int16(r.u16()))
+}
+
+func (r *fontReader) u32() int {
+	b := r.read(4)
+	if len(b) < 4 {
+		return 0
+	}
+	return int(binary.BigEndian.Uint32(b))
+}
+
+// u255 reads a 255UInt16.
+func (r *fontReader) u255() int {
+	switch code := r.u8(); code {
+	case 253:
+		return r.u16()
+	case 254:
+		return r.u8() + 506
+	case 255:
+		return r.u8() + 253
+	default:
+		return code
+	}
+}
+
+// base128 reads a UIntBase128.
+func (r *fontReader) base128() int {
+	v := 0
+	for i := 0; i < 5; i++ {
+		b := r.u8()
+		if (i == 0 && b == 0x80) || v&0xfe000000 != 0 {
+			r.err = ErrInvalidWOFF
+			return 0
+		}
+		v = v<<7 | b&0x7f
+		if b&0x80 == 0 {
+			return v
+		}
+	}
+	r.err = ErrInvalidWOFF
+	return 0
+}
+
+// sub returns a reader of the next n bytes.
+func (r *fontReader) sub(n int) *fontReader {
+	b := r.read(n)
+	return &fontReader{b: b, err: r.err}
+}
+
+// decodeWOFF2 decodes a WOFF2 web font, reversing the transforms of the glyf, loca and hmtx tables.
+func decodeWOFF2(fontData []byte) ([]byte, error) {
+	r := &fontReader{b: fontData}
+	r.read(4) // signature
+	flavor := uint32(r.u32())
+	r.read(4) // length
+	numTables := r.u16()
+	r.read(2 + 4) // reserved, totalSfntSize
+	totalCompressedSize := r.u32()
+	r.read(2 + 2 + 4*5) // version, metadata and private data
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	tables := make([]woff2Table, numTables)
+	streamLength := 0
+	for i := range tables {
+		flags := r.u8()
+		t := &tables[i]
+		if index := flags & 0x3f; index == 63 {
+			t.tag = string(r.read(4))
+		} else if index < len(woff2KnownTags) {
+			t.tag = woff2KnownTags[index]
+		} else {
+			return nil, ErrInvalidWOFF
+		}
+		version := flags >> 6
+		// version 0 is the transform of glyf and loca, and no transform for the other tables
+		t.transformed = version != 0
+		if t.tag == "glyf" || t.tag == "loca" {
+			t.transformed = version != 3
+		}
+		t.length = r.base128()
+		if t.transformed {
+			t.length = r.base128()
+		}
+		streamLength += t.length
+		if streamLength > maxSFNTSize {
+			return nil, ErrInvalidWOFF
+		}
+	}
+
+	fonts := []woff2Font{{flavor: flavor}}
+	for i := range tables {
+		fonts[0].tables = append(fonts[0].tables, i)
+	}
+	if flavor == 0x74746366 { // ttcf
+		r.read(4) // version
+		fonts = make([]woff2Font, r.u255())
+		for i := range fonts {
+			count := r.u255()
+			fonts[i].flavor = uint32(r.u32())
+			for j := 0; j < count; j++ {
+				index := r.u255()
+				if index >= len(tables) {
+					return nil, ErrInvalidWOFF
+				}
+				fonts[i].tables = append(fonts[i].tables, index)
+			}
+		}
+	}
+	compressed := r.read(totalCompressedSize)
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	// the tables are read from the stream, a longer stream is not decompressed
+	stream, err := io.ReadAll(io.LimitReader(brotli.NewReader(bytes.NewReader(compressed)), int64(streamLength)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(stream) > streamLength {
+		return nil, ErrInvalidWOFF
+	}
+	sr := &fontReader{b: stream}
+	for i := range tables {
+		tables[i].data = sr.read(tables[i].length)
+		tables[i].done = !tables[i].transformed
+	}
+	if sr.err != nil {
+		return nil, sr.err
+	}
+
+	for _, font := range fonts {
+		if err := reconstructWOFF2Font(tables, font); err != nil {
+			return nil, err
+		}
+	}
+	if len(fonts) == 1 && flavor != 0x74746366 {
+		data := make(map[string][]byte)
+		for _, t := range tables {
+			data[t.tag] = t.data
+		}
+		return sfntBytes(flavor, data), nil
+	}
+	return woff2CollectionBytes(tables, fonts), nil
+}
+
+// reconstructWOFF2Font reverses the transforms of the tables of font.
+func reconstructWOFF2Font(tables []woff2Table, font woff2Font) error {
+	find := func(tag string) *woff2Table {
+		for _, i := range font.tables {
+			if tables[i].tag == tag {
+				return &tables[i]
+			}
+		}
+		return nil
+	}
+
+	var xMins []int
+	glyf, loca, head := find("glyf"), find("loca"), find("head")
+	if glyf != nil && glyf.transformed && !glyf.done {
+		if loca == nil {
+			return ErrInvalidWOFF
+		}
+		var err error
+		if glyf.data, loca.data, xMins, err = reconstructWOFF2Glyf(glyf.data); err != nil {
+			return err
+		}
+		glyf.done, loca.done = true, true
+	} else if glyf != nil && loca != nil && head != nil && len(head.data) >= 52 {
+		// glyf is not transformed or was reconstructed for another font of the collection
+		xMins = glyfXMins(glyf.data, loca.data, binary.BigEndian.Uint16(head.data[50:]) != 0)
+	}
+
+	hmtx, hhea, maxp := find("hmtx"), find("hhea"), find("maxp")
+	if hmtx != nil && !hmtx.done {
+		if xMins == nil || hhea == nil || maxp == nil || len(hhea.data) < 36 || len(maxp.data) < 6 {
+			return ErrInvalidWOFF
+		}
+		numHMetrics := int(binary.BigEndian.Uint16(hhea.data[34:]))
+		numGlyphs := int(binary.BigEndian.Uint16(maxp.data[4:]))
+		data, err := reconstructWOFF2Hmtx(hmtx.data, numGlyphs, numHMetrics, xMins)
+		if err != nil {
+			return err
+		}
+		hmtx.data, hmtx.done = data, true
+	}
+	for _, i := range font.tables {
+		if !tables[i].done {
+			return ErrInvalidWOFF
+		}
+	}
+	return nil
+}
+
+// reconstructWOFF2Glyf rebuilds the glyf and loca tables from a transformed glyf table.
+// It also returns the xMin of each glyph to rebuild the left side bearings of a transformed hmtx table.
+func reconstructWOFF2Glyf(data []byte) ([]byte, []byte, []int, error) {
+	r := &fontReader{b: data}
+	r.u16() // reserved
+	optionFlags := r.u16()
+	numGlyphs := r.u16()
+	indexFormat := r.u16()
+	var sizes [7]int
+	for i := range sizes {
+		sizes[i] = r.u32()
+	}
+	nContours, nPoints, flags, glyphs := r.sub(sizes[0]), r.sub(sizes[1]), r.sub(sizes[2]), r.sub(sizes[3])
+	composites, bboxes, instructions := r.sub(sizes[4]), r.sub(sizes[5]), r.sub(sizes[6])
+	bboxBitmap := bboxes.read((numGlyphs + 31) / 32 * 4)
+	var overlapBitmap []byte
+	if optionFlags&1 != 0 {
+		overlapBitmap = r.read((numGlyphs + 7) / 8)
+	}
+	if r.err != nil {
+		return nil, nil, nil, r.err
+	}
+	bit := func(bitmap []byte, g int) bool {
+		return g>>3 < len(bitmap) && bitmap[g>>3]&(0x80>>uint(g&7)) != 0
+	}
+
+	var glyf []byte
+	offsets := make([]int, numGlyphs+1)
+	xMins := make([]int, numGlyphs)
+	for g := 0; g < numGlyphs; g++ {
+		offsets[g] = len(glyf)
+		contours := int(int16(nContours.u16()))
+		var glyph []byte
+		var bbox [4]int
+		hasBBox := bit(bboxBitmap, g)
+		if hasBBox {
+			for i := range bbox {
+				bbox[i] = bboxes.i16()
+			}
+		}
+
+		switch {
+		case contours == 0:
+			if hasBBox {
+				return nil, nil, nil, ErrInvalidWOFF
+			}
+		case contours > 0:
+			endPts := make([]int, contours)
+			total := 0
+			for i := range endPts {
+				total += nPoints.u255()
+				endPts[i] = total - 1
+			}
+			var points []glyphPoint
+			x, y := 0, 0
+			for i := 0; i < total; i++ {
+				flag := flags.u8()
+				dx, dy := woff2Triplet(flag&0x7f, glyphs)
+				x, y = x+dx, y+dy
+				points = append(points, glyphPoint{x: x, y: y, onCurve: flag&0x80 == 0})
+			}
+			instructionLength := glyphs.u255()
+			instr := instructions.read(instructionLength)
+			if !hasBBox && len(points) > 0 {
+				bbox = [4]int{points[0].x, points[0].y, points[0].x, points[0].y}
+				for _, p := range points {
+					bbox = [4]int{minInt(bbox[0], p.x), minInt(bbox[1], p.y), maxInt(bbox[2], p.x), maxInt(bbox[3], p.y)}
+				}
+			}
+			glyph = simpleGlyphBytes(contours, bbox, endPts, instr, points, bit(overlapBitmap, g))
+		case contours == -1:
+			if !hasBBox {
+				return nil, nil, nil, ErrInvalidWOFF
+			}
+			glyph = appendInt16s(nil, -1, bbox[0], bbox[1], bbox[2], bbox[3])
+			haveInstructions := false
+			for more := true; more; {
+				componentFlags := composites.u16()
+				more = componentFlags&moreComponents != 0
+				haveInstructions = haveInstructions || componentFlags&0x100 != 0
+				size := 2 + 2 // glyph index and byte arguments
+				if componentFlags&arg1and2areWords != 0 {
+					size += 2
+				}
+				if componentFlags&hasScale != 0 {
+					size += 2
+				} else if componentFlags&xAndYScale != 0 {
+					size += 4
+				} else if componentFlags&twoByTwo != 0 {
+					size += 8
+				}
+				glyph = appendInt16s(glyph, componentFlags)
+				glyph = append(glyph, composites.read(size)...)
+				if composites.err != nil {
+					return nil, nil, nil, composites.err
+				}
+			}
+			if haveInstructions {
+				instructionLength := glyphs.u255()
+				glyph = appendInt16s(glyph, instructionLength)
+				glyph = append(glyph, instructions.read(instructionLength)...)
+			}
+		default:
+			return nil, nil, nil, ErrInvalidWOFF
+		}
+		xMins[g] = bbox[0]
+		glyf = append(glyf, glyph...)
+		for len(glyf)%4 != 0 {
+			glyf = append(glyf, 0)
+		}
+	}
+	offsets[numGlyphs] = len(glyf)
+	for _, s := range []*fontReader{nContours, nPoints, flags, glyphs, composites, bboxes, instructions} {
+		if s.err != nil {
+			return nil, nil, nil, s.err
+		}
+	}
+
+	var loca []byte
+	for _, offset := range offsets {
+		if indexFormat == 0 {
+			loca = appendInt16s(loca, offset/2)
+		} else {
+			loca = append(loca, byte(offset>>24), byte(offset>>16), byte(offset>>8), byte(offset))
+		}
+	}
+	return glyf, loca, xMins, nil
+}
+
+// glyphPoint is a point of a simple glyph.
+type glyphPoint struct {
+	x, y    int
+	onCurve bool
+}
+
+// woff2Triplet reads the coordinates of a point relative to the previous one, encoded according to flag.
+func woff2Triplet(flag int, r *fontReader) (int, int) {
+	withSign := func(flag int, v int) int {
+		if flag&1 != 0 {
+			return v
+		}
+		return -v
+	}
+	switch {
+	case flag < 10:
+		return 0, withSign(flag, (flag&14)<<7+r.u8())
+	case flag < 20:
+		return withSign(flag, ((flag-10)&14)<<7+r.u8()), 0
+	case flag < 84:
+		b0, b1 := flag-20, r.u8()
+		return withSign(flag, 1+(b0&0x30)+b1>>4), withSign(flag>>1, 1+(b0&0x0c)<<2+b1&0x0f)
+	case flag < 120:
+		b0 := flag - 84
+		dx := withSign(flag, 1+(b0/12)<<8+r.u8())
+		return dx, withSign(flag>>1, 1+((b0%12)>>2)<<8+r.u8())
+	case flag < 124:
+		b0, b1, b2 := r.u8(), r.u8(), r.u8()
+		return withSign(flag, b0<<4+b1>>4), withSign(flag>>1, (b1&0x0f)<<8+b2)
+	default:
+		b := r.read(4)
+		if len(b) < 4 {
+			return 0, 0
+		}
+		return withSign(flag, int(b[0])<<8+int(b[1])), withSign(flag>>1, int(b[2])<<8+int(b[3]))
+	}
+}
+
+// simpleGlyphBytes encodes a simple glyph of the glyf table.
+func simpleGlyphBytes(contours int, bbox [4]int, endPts []int, instructions []byte, points []glyphPoint, overlap bool) []byte {
+	b := appendInt16s(nil, contours, bbox[0], bbox[1], bbox[2], bbox[3])
+	b = appendInt16s(b, endPts...)
+	b = appendInt16s(b, len(instructions))
+	b = append(b, instructions...)
+
+	var flags, xs, ys []byte
+	// a coordinate is a byte with its sign in the flag, or the same as the previous one, or a short
+	coordinate := func(v int, short, same byte, values []byte) (byte, []byte) {
+		switch {
+		case v == 0:
+			return same, values
+		case v > -256 && v < 256:
+			if v > 0 {
+				return short | same, append(values, byte(v))
+			}
+			return short, append(values, byte(-v))
+		}
+		return 0, appendInt16s(values, v)
+	}
+	prevX, prevY := 0, 0
+	for i, p := range points {
+		var flag, f byte
+		if p.onCurve {
+			flag |= 0x01
+		}
+		if i == 0 && overlap {
+			flag |= 0x40
+		}
+		f, xs = coordinate(p.x-prevX, 0x02, 0x10, xs)
+		flag |= f
+		f, ys = coordinate(p.y-prevY, 0x04, 0x20, ys)
+		flag |= f
+		flags = append(flags, flag)
+		prevX, prevY = p.x, p.y
+	}
+	b = append(b, flags...)
+	b = append(b, xs...)
+	return append(b, ys...)
+}
+
+// glyfOffsets returns the offsets of the glyphs in the glyf table followed by its end,
+// longOffsets being the format of the loca table.
+func glyfOffsets(loca []byte, longOffsets bool) []int {
+	var offsets []int
+	if longOffsets {
+		for i := 0; i+4 <= len(loca); i += 4 {
+			offsets = append(offsets, int(binary.BigEndian.Uint32(loca[i:])))
+		}
+	} else {
+		for i := 0; i+2 <= len(loca); i += 2 {
+			offsets = append(offsets, int(binary.BigEndian.Uint16(loca[i:]))*2)
+		}
+	}
+	return offsets
+}
+
+// glyfXMins returns the xMin of each glyph of a glyf table, longOffsets being the format of the loca table.
+func glyfXMins(glyf []byte, loca []byte, longOffsets bool) []int {
+	offsets := glyfOffsets(loca, longOffsets)
+	var xMins []int
+	for g := 0; g+1 < len(offsets); g++ {
+		xMin := 0
+		if offsets[g] < offsets[g+1] && offsets[g]+4 <= len(glyf) {
+			xMin = int(int16(binary.BigEndian.Uint16(glyf[offsets[g]+2:])))
+		}
+		xMins = append(xMins, xMin)
+	}
+	return xMins
+}
+
+// reconstructWOFF2Hmtx rebuilds the hmtx table from a transformed hmtx table,
+// the left side bearings left out being the xMin of the glyphs.
+func reconstructWOFF2Hmtx(data []byte, numGlyphs, numHMetrics int, xMins []int) ([]byte, error) {
+	if numHMetrics > numGlyphs || len(xMins) < numGlyphs {
+		return nil, ErrInvalidWOFF
+	}
+	r := &fontReader{b: data}
+	flags := r.u8()
+	advances := make([]int, numHMetrics)
+	for i := range advances {
+		advances[i] = r.u16()
+	}
+	lsbs := make([]int, numGlyphs)
+	for i := range lsbs {
+		proportional := i < numHMetrics
+		if (proportional && flags&1 != 0) || (!proportional && flags&2 != 0) {
+			lsbs[i] = xMins[i]
+		} else {
+			lsbs[i] = r.i16()
+		}
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	var b []byte
+	for i := range lsbs {
+		if i < numHMetrics {
+			b = appendInt16s(b, advances[i])
+		}
+		b = appendInt16s(b, lsbs[i])
+	}
+	return b, nil
+}
+
+// woff2CollectionBytes writes the fonts of a WOFF2 collection as a TrueType collection sharing their tables.
+func woff2CollectionBytes(tables []woff2Table, fonts []woff2Font) []byte {
+	headerSize := 12 + 4*len(fonts)
+	size := headerSize
+	for _, font := range fonts {
+		size += 12 + 16*len(font.tables)
+	}
+	b := make([]byte, size)
+	copy(b, "ttcf")
+	binary.BigEndian.PutUint32(b[4:], 0x00010000)
+	binary.BigEndian.PutUint32(b[8:], uint32(len(fonts)))
+
+	offsets := make([]int, len(tables))
+	for i, t := range tables {
+		offsets[i] = len(b)
+		padded := make([]byte, (len(t.data)+3)&^3)
+		copy(padded, t.data)
+		b = append(b, padded...)
+	}
+
+	directory := headerSize
+	for i, font := range fonts {
+		binary.BigEndian.PutUint32(b[12+4*i:], uint32(directory))
+		binary.BigEndian.PutUint32(b[directory:], font.flavor)
+		writeSFNTDirectoryHeader(b[directory+4:], len(font.tables))
+		indexes := append([]int(nil), font.tables...)
+		sort.Slice(indexes, func(i, j int) bool { return tables[indexes[i]].tag < tables[indexes[j]].tag })
+		for j, index := range indexes {
+			t := tables[index]
+			entry := b[directory+12+16*j:]
+			copy(entry, t.tag)
+			binary.BigEndian.PutUint32(entry[4:], uint32(CheckSum(b[offsets[index]:offsets[index]+(len(t.data)+3)&^3])))
+			binary.BigEndian.PutUint32(entry[8:], uint32(offsets[index]))
+			binary.BigEndian.PutUint32(entry[12:], uint32(len(t.data)))
+		}
+		directory += 12 + 16*len(font.tables)
+	}
+	return b
+}
+
+// appendInt16s appends values as 16-bit big endian integers.
+func appendInt16s(b []byte, values ...int) []byte {
+	for _, v := range values {
+		b = append(b, byte(v>>8), byte(v))
+	}
+	return b
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
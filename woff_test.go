@@ -0,0 +1,422 @@
+package gopdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/signintech/gopdf/fontmaker/core"
+)
+
+func TestWOFF(t *testing.T) {
+	err := initTesting()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ttfp := parseTestFont(t, "./test/res/LiberationSerif-Regular.ttf")
+	tags := sortedTags(ttfp)
+
+	totalSfntSize := 12 + 16*len(tags)
+	for _, tag := range tags {
+		totalSfntSize += (len(ttfp.TableData(tag)) + 3) &^ 3
+	}
+	var directory, tables bytes.Buffer
+	directory.WriteString("wOFF")
+	binary.Write(&directory, binary.BigEndian, []uint32{0x00010000, 0})
+	binary.Write(&directory, binary.BigEndian, []uint16{uint16(len(tags)), 0})
+	binary.Write(&directory, binary.BigEndian, uint32(totalSfntSize))
+	directory.Write(make([]byte, 44-directory.Len()))
+	for _, tag := range tags {
+		data := ttfp.TableData(tag)
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		zw.Write(data)
+		zw.Close()
+		if compressed.Len() < len(data) {
+			data = compressed.Bytes()
+		}
+		directory.WriteString(tag)
+		offset := 44 + 20*len(tags) + tables.Len()
+		binary.Write(&directory, binary.BigEndian, []uint32{uint32(offset), uint32(len(data)), uint32(len(ttfp.TableData(tag))), 0})
+		tables.Write(data)
+		tables.Write(make([]byte, (4-len(data)%4)%4))
+	}
+	woff := append(directory.Bytes(), tables.Bytes()...)
+
+	// a table larger than the font is rejected before it is decompressed
+	tooLarge := append([]byte(nil), woff...)
+	binary.BigEndian.PutUint32(tooLarge[44+12:], uint32(totalSfntSize+1))
+	if _, err := decodeWOFF(tooLarge); err != ErrInvalidWOFF {
+		t.Fatalf("expected ErrInvalidWOFF, got %v", err)
+	}
+
+	decoded, err := decodeWOFF(woff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result core.TTFParser
+	err = result.ParseFontData(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tag := range tags {
+		if !bytes.Equal(result.TableData(tag), ttfp.TableData(tag)) {
+			t.Fatalf("unexpected %s table", tag)
+		}
+	}
+
+	pdf := GoPdf{}
+	pdf.Start(Config{PageSize: *PageSizeA4})
+	pdf.AddPage()
+	err = pdf.AddTTFFontData("woff", woff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pdf.SetFont("woff", "", 14)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pdf.Cell(nil, "Hello WOFF")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pdf.WritePdf("./test/out/woff.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWOFF2(t *testing.T) {
+	ttfp := parseTestFont(t, "./test/res/LiberationSerif-Regular.ttf")
+	woff2 := buildTestWOFF2(t, ttfp)
+
+	decoded, err := decodeWOFF(woff2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result core.TTFParser
+	err = result.ParseFontData(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tag := range sortedTags(ttfp) {
+		if tag != "glyf" && tag != "loca" && !bytes.Equal(result.TableData(tag), ttfp.TableData(tag)) {
+			t.Fatalf("unexpected %s table", tag)
+		}
+	}
+	for g := 0; g < int(ttfp.NumGlyphs()); g++ {
+		want := parseTestGlyph(t, ttfp, g)
+		if got := parseTestGlyph(t, &result, g); !reflect.DeepEqual(got, want) {
+			t.Fatalf("unexpected glyph %d: %+v, expected %+v", g, got, want)
+		}
+	}
+
+	pdf := GoPdf{}
+	pdf.Start(Config{PageSize: *PageSizeA4})
+	pdf.AddPage()
+	err = pdf.AddTTFFontData("woff2", woff2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pdf.SetFont("woff2", "", 14)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pdf.Cell(nil, "Hello WOFF2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = pdf.GetBytesPdfReturnErr()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func parseTestFont(t *testing.T, path string) *core.TTFParser {
+	var ttfp core.TTFParser
+	if err := ttfp.Parse(path); err != nil {
+		t.Fatal(err)
+	}
+	return &ttfp
+}
+
+func sortedTags(ttfp *core.TTFParser) []string {
+	var tags []string
+	for tag := range ttfp.GetTables() {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// testGlyph is a glyph of a glyf table with its coordinates decoded.
+type testGlyph struct {
+	contours     int
+	bbox         [4]int
+	endPts       []int
+	instructions []byte
+	points       []glyphPoint
+	components   []byte
+}
+
+func parseTestGlyph(t *testing.T, ttfp *core.TTFParser, g int) testGlyph {
+	glyf := ttfp.TableData("glyf")
+	r := &fontReader{b: glyf[ttfp.LocaTable[g]:ttfp.LocaTable[g+1]]}
+	var glyph testGlyph
+	if len(r.b) == 0 {
+		return glyph
+	}
+	glyph.contours = r.i16()
+	for i := range glyph.bbox {
+		glyph.bbox[i] = r.i16()
+	}
+	if glyph.contours < 0 {
+		haveInstructions := false
+		for more := true; more; {
+			flags := r.u16()
+			more = flags&moreComponents != 0
+			haveInstructions = haveInstructions || flags&0x100 != 0
+			size := 4
+			if flags&arg1and2areWords != 0 {
+				size += 2
+			}
+			if flags&hasScale != 0 {
+				size += 2
+			} else if flags&xAndYScale != 0 {
+				size += 4
+			} else if flags&twoByTwo != 0 {
+				size += 8
+			}
+			glyph.components = appendInt16s(glyph.components, flags)
+			glyph.components = append(glyph.components, r.read(size)...)
+		}
+		if haveInstructions {
+			glyph.instructions = r.read(r.u16())
+		}
+	} else {
+		for i := 0; i < glyph.contours; i++ {
+			glyph.endPts = append(glyph.endPts, r.u16())
+		}
+		glyph.instructions = r.read(r.u16())
+		count := glyph.endPts[len(glyph.endPts)-1] + 1
+		var flags []int
+		for len(flags) < count {
+			flag := r.u8()
+			flags = append(flags, flag)
+			if flag&0x08 != 0 {
+				for repeat := r.u8(); repeat > 0; repeat-- {
+					flags = append(flags, flag)
+				}
+			}
+		}
+		coordinate := func(flag int, short, same int, v int) int {
+			if flag&short != 0 {
+				if flag&same != 0 {
+					return v + r.u8()
+				}
+				return v - r.u8()
+			} else if flag&same != 0 {
+				return v
+			}
+			return v + r.i16()
+		}
+		glyph.points = make([]glyphPoint, count)
+		x, y := 0, 0
+		for i, flag := range flags[:count] {
+			x = coordinate(flag, 0x02, 0x10, x)
+			glyph.points[i] = glyphPoint{x: x, onCurve: flag&0x01 != 0}
+		}
+		for i, flag := range flags[:count] {
+			y = coordinate(flag, 0x04, 0x20, y)
+			glyph.points[i].y = y
+		}
+	}
+	if r.err != nil {
+		t.Fatalf("glyph %d: %v", g, r.err)
+	}
+	return glyph
+}
+
+// buildTestWOFF2 builds a WOFF2 font with transformed glyf, loca and hmtx tables.
+func buildTestWOFF2(t *testing.T, ttfp *core.TTFParser) []byte {
+	u255 := func(b *bytes.Buffer, v int) {
+		if v < 253 {
+			b.WriteByte(byte(v))
+		} else {
+			b.Write([]byte{253, byte(v >> 8), byte(v)})
+		}
+	}
+	base128 := func(b *bytes.Buffer, v int) {
+		var bytes []byte
+		for bytes = []byte{byte(v & 0x7f)}; v >= 0x80; {
+			v >>= 7
+			bytes = append([]byte{byte(v&0x7f | 0x80)}, bytes...)
+		}
+		b.Write(bytes)
+	}
+
+	numGlyphs := int(ttfp.NumGlyphs())
+	var nContours, nPoints, flags, glyphs, composites, bboxes, instructions bytes.Buffer
+	bboxBitmap := make([]byte, (numGlyphs+31)/32*4)
+	var xMins []int
+	for g := 0; g < numGlyphs; g++ {
+		glyph := parseTestGlyph(t, ttfp, g)
+		binary.Write(&nContours, binary.BigEndian, int16(glyph.contours))
+		xMins = append(xMins, glyph.bbox[0])
+		if glyph.contours == 0 {
+			continue
+		}
+		explicitBBox := glyph.contours < 0
+		if glyph.contours > 0 {
+			previous := -1
+			for _, end := range glyph.endPts {
+				u255(&nPoints, end-previous)
+				previous = end
+			}
+			x, y := 0, 0
+			bbox := [4]int{glyph.points[0].x, glyph.points[0].y, glyph.points[0].x, glyph.points[0].y}
+			for _, p := range glyph.points {
+				dx, dy := p.x-x, p.y-y
+				flag := 124
+				if dx >= 0 {
+					flag |= 1
+				} else {
+					dx = -dx
+				}
+				if dy >= 0 {
+					flag |= 2
+				} else {
+					dy = -dy
+				}
+				if !p.onCurve {
+					flag |= 0x80
+				}
+				flags.WriteByte(byte(flag))
+				glyphs.Write(appendInt16s(nil, dx, dy))
+				x, y = p.x, p.y
+				bbox = [4]int{minInt(bbox[0], x), minInt(bbox[1], y), maxInt(bbox[2], x), maxInt(bbox[3], y)}
+			}
+			u255(&glyphs, len(glyph.instructions))
+			explicitBBox = bbox != glyph.bbox
+		} else {
+			composites.Write(glyph.components)
+			if glyph.instructions != nil {
+				u255(&glyphs, len(glyph.instructions))
+			}
+		}
+		instructions.Write(glyph.instructions)
+		if explicitBBox {
+			bboxBitmap[g>>3] |= 0x80 >> uint(g&7)
+			bboxes.Write(appendInt16s(nil, glyph.bbox[:]...))
+		}
+	}
+	var glyf bytes.Buffer
+	indexFormat := 1
+	if ttfp.IsShortIndex {
+		indexFormat = 0
+	}
+	glyf.Write(appendInt16s(nil, 0, 0, numGlyphs, indexFormat))
+	streams := []*bytes.Buffer{&nContours, &nPoints, &flags, &glyphs, &composites, &bboxes, &instructions}
+	for _, s := range streams {
+		size := s.Len()
+		if s == &bboxes {
+			size += len(bboxBitmap)
+		}
+		binary.Write(&glyf, binary.BigEndian, uint32(size))
+	}
+	for _, s := range streams {
+		if s == &bboxes {
+			glyf.Write(bboxBitmap)
+		}
+		glyf.Write(s.Bytes())
+	}
+
+	// the left side bearings equal to the xMin of the glyphs are left out
+	hmtx := ttfp.TableData("hmtx")
+	numHMetrics := int(ttfp.NumberOfHMetrics())
+	lsb := func(g int) int {
+		if g < numHMetrics {
+			return int(int16(binary.BigEndian.Uint16(hmtx[g*4+2:])))
+		}
+		return int(int16(binary.BigEndian.Uint16(hmtx[numHMetrics*4+(g-numHMetrics)*2:])))
+	}
+	hmtxFlags := 3
+	for g := 0; g < numGlyphs; g++ {
+		if lsb(g) != xMins[g] {
+			hmtxFlags &^= 1
+			if g >= numHMetrics {
+				hmtxFlags &^= 2
+			}
+		}
+	}
+	transformedHmtx := []byte{byte(hmtxFlags)}
+	for g := 0; g < numHMetrics; g++ {
+		transformedHmtx = append(transformedHmtx, hmtx[g*4:g*4+2]...)
+	}
+	for g := 0; g < numGlyphs; g++ {
+		if (g < numHMetrics && hmtxFlags&1 == 0) || (g >= numHMetrics && hmtxFlags&2 == 0) {
+			transformedHmtx = appendInt16s(transformedHmtx, lsb(g))
+		}
+	}
+
+	// loca follows glyf
+	var tags []string
+	for _, tag := range sortedTags(ttfp) {
+		if tag != "loca" {
+			tags = append(tags, tag)
+		}
+		if tag == "glyf" {
+			tags = append(tags, "loca")
+		}
+	}
+	var directory, stream bytes.Buffer
+	for _, tag := range tags {
+		index := 63
+		for i, known := range woff2KnownTags {
+			if known == tag {
+				index = i
+			}
+		}
+		data, transformed := ttfp.TableData(tag), []byte(nil)
+		switch tag {
+		case "glyf":
+			transformed = glyf.Bytes()
+		case "loca":
+			transformed = []byte{}
+		case "hmtx":
+			transformed = transformedHmtx
+			index |= 1 << 6
+		}
+		directory.WriteByte(byte(index))
+		if index&0x3f == 63 {
+			directory.WriteString(tag)
+		}
+		base128(&directory, len(data))
+		if transformed != nil {
+			base128(&directory, len(transformed))
+			data = transformed
+		}
+		stream.Write(data)
+	}
+
+	var compressed bytes.Buffer
+	bw := brotli.NewWriter(&compressed)
+	bw.Write(stream.Bytes())
+	if err := bw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	b.WriteString("wOF2")
+	binary.Write(&b, binary.BigEndian, []uint32{0x00010000, 0})
+	binary.Write(&b, binary.BigEndian, []uint16{uint16(len(tags)), 0})
+	binary.Write(&b, binary.BigEndian, []uint32{0, uint32(compressed.Len())})
+	b.Write(make([]byte, 48-b.Len()))
+	b.Write(directory.Bytes())
+	b.Write(compressed.Bytes())
+	return b.Bytes()
+}
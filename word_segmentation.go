@@ -0,0 +1,211 @@
+package gopdf
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// WordDictionary is a list of words used to find the word boundaries of scripts written without spaces,
+// such as Thai, Lao, Khmer and Myanmar.
+type WordDictionary struct {
+	words     map[string]bool
+	maxLength int // length of the longest word in runes
+}
+
+// NewWordDictionary creates a WordDictionary holding words.
+func NewWordDictionary(words ...string) *WordDictionary {
+	d := &WordDictionary{
+		words: make(map[string]bool),
+	}
+	d.AddWords(words...)
+	return d
+}
+
+// LoadWordDictionary creates a WordDictionary from a word list with one word per line.
+// Empty lines and lines starting with # are ignored.
+//
+//	Usage:
+//	f, _ := os.Open("lao-words.txt")
+//	dict, err := gopdf.LoadWordDictionary(f)
+//	pdf.MultiCellWithOption(&rect, text, gopdf.CellOption{
+//		BreakOption: &gopdf.BreakOption{Mode: gopdf.BreakModeWordSegmentation, Dictionary: dict},
+//	})
+func LoadWordDictionary(r io.Reader) (*WordDictionary, error) {
+	d := NewWordDictionary()
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		d.AddWords(line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// AddWords adds words to the dictionary.
+func (d *WordDictionary) AddWords(words ...string) {
+	for _, w := range words {
+		if w == "" {
+			continue
+		}
+		d.words[w] = true
+		if l := len([]rune(w)); l > d.maxLength {
+			d.maxLength = l
+		}
+	}
+}
+
+// Contains reports whether word is in the dictionary.
+func (d *WordDictionary) Contains(word string) bool {
+	return d.words[word]
+}
+
+var defaultThaiDictionary *WordDictionary
+var defaultThaiDictionaryOnce sync.Once
+
+// DefaultThaiDictionary returns the built-in dictionary of common Thai words,
+// used by BreakModeWordSegmentation when no Dictionary is set.
+func DefaultThaiDictionary() *WordDictionary {
+	defaultThaiDictionaryOnce.Do(func() {
+		defaultThaiDictionary = NewWordDictionary(strings.Fields(thaiWords)...)
+	})
+	return defaultThaiDictionary
+}
+
+// Segment splits text, which should not contain spaces, into words by maximal matching:
+// the segmentation with the fewest characters outside of dictionary words, then with the fewest words, is chosen.
+// Characters that are not part of a dictionary word are kept together.
+func (d *WordDictionary) Segment(text string) []string {
+	runes := []rune(text)
+	var words []string
+	start := 0
+	for _, end := range d.segmentBoundaries(runes) {
+		words = append(words, string(runes[start:end]))
+		start = end
+	}
+	return words
+}
+
+// segmentBoundaries returns the end index of each segment of text.
+func (d *WordDictionary) segmentBoundaries(text []rune) []int {
+	n := len(text)
+	if n == 0 {
+		return nil
+	}
+	type state struct {
+		unknown int  // characters not matched by a word
+		words   int  // number of segments
+		prev    int  // start of the last segment
+		known   bool // the last segment is a dictionary word
+	}
+	const unreachable = -1
+	best := make([]state, n+1)
+	for i := 1; i <= n; i++ {
+		best[i].prev = unreachable
+	}
+	better := func(a state, b state) bool {
+		return b.prev == unreachable || a.unknown < b.unknown || (a.unknown == b.unknown && a.words < b.words)
+	}
+
+	for i := 0; i < n; i++ {
+		if i > 0 && best[i].prev == unreachable {
+			continue
+		}
+		if i > 0 && !isClusterBoundary(text, i) {
+			continue
+		}
+		cur := best[i]
+		for l := 1; l <= d.maxLength && i+l <= n; l++ {
+			j := i + l
+			if j < n && !isClusterBoundary(text, j) {
+				continue
+			}
+			if !d.words[string(text[i:j])] {
+				continue
+			}
+			s := state{unknown: cur.unknown, words: cur.words + 1, prev: i, known: true}
+			if better(s, best[j]) {
+				best[j] = s
+			}
+		}
+		// skip one cluster that is not in the dictionary
+		j := i + 1
+		for j < n && !isClusterBoundary(text, j) {
+			j++
+		}
+		s := state{unknown: cur.unknown + j - i, words: cur.words + 1, prev: i}
+		if better(s, best[j]) {
+			best[j] = s
+		}
+	}
+
+	var boundaries []int
+	for j := n; j > 0; j = best[j].prev {
+		// consecutive unknown clusters form a single segment
+		if len(boundaries) > 0 && !best[j].known && !best[boundaries[len(boundaries)-1]].known {
+			continue
+		}
+		boundaries = append(boundaries, j)
+	}
+	for i, k := 0, len(boundaries)-1; i < k; i, k = i+1, k-1 {
+		boundaries[i], boundaries[k] = boundaries[k], boundaries[i]
+	}
+	return boundaries
+}
+
+// isClusterBoundary reports whether a word may start at text[i],
+// i.e. text[i] does not belong to the grapheme cluster or syllable of the previous character.
+func isClusterBoundary(text []rune, i int) bool {
+	r, prev := text[i], text[i-1]
+	if unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Mc, r) {
+		return false
+	}
+	switch {
+	case prev >= 0x0E40 && prev <= 0x0E44, prev >= 0x0EC0 && prev <= 0x0EC4: // Thai and Lao leading vowels
+		return false
+	case prev == 0x17D2 || prev == 0x1039 || prev == 0x103A: // Khmer coeng and Myanmar virama/asat
+		return false
+	case r == 0x0E30 || r == 0x0E32 || r == 0x0E33 || r == 0x0E45 || r == 0x0E46: // Thai following vowels and mai yamok
+		return false
+	case r == 0x0EB0 || r == 0x0EB2 || r == 0x0EB3 || r == 0x0EC6: // Lao following vowels and ko la
+		return false
+	}
+	return true
+}
+
+// wordSegmentationBreaks returns the breaks of the Unicode Line Breaking Algorithm,
+// with additional breaks between the words of the runs of Thai, Lao, Khmer and Myanmar text.
+func wordSegmentationBreaks(text []rune, d *WordDictionary) []lineBreak {
+	if d == nil {
+		d = DefaultThaiDictionary()
+	}
+	breaks := unicodeLineBreaks(text)
+	for i := 0; i < len(text); {
+		if lineBreakClassOf(text[i]) != lbSA {
+			i++
+			continue
+		}
+		start := i
+		for i < len(text) {
+			c := lineBreakClassOf(text[i])
+			if c != lbSA && c != lbCM {
+				break
+			}
+			i++
+		}
+		for _, end := range d.segmentBoundaries(text[start:i]) {
+			if start+end < i {
+				breaks = append(breaks, lineBreak{index: start + end})
+			}
+		}
+	}
+	sortLineBreaks(breaks)
+	return breaks
+}
@@ -0,0 +1,53 @@
+package gopdf
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWordDictionarySegment(t *testing.T) {
+	d := DefaultThaiDictionary()
+	var tests = []struct {
+		text  string
+		words []string
+	}{
+		{"ภาษาไทยง่ายนิดเดียว", []string{"ภาษาไทย", "ง่าย", "นิดเดียว"}},
+		{"ฉันรักเมืองไทย", []string{"ฉัน", "รัก", "เมือง", "ไทย"}},
+		{"ตัดคำภาษาไทย", []string{"ตัดคำ", "ภาษาไทย"}},
+	}
+	for _, tt := range tests {
+		if got := d.Segment(tt.text); !reflect.DeepEqual(got, tt.words) {
+			t.Errorf("%s: expected %q, got %q", tt.text, tt.words, got)
+		}
+	}
+
+	lao, err := LoadWordDictionary(strings.NewReader("# lao words\nສະບາຍດີ\nປະເທດ\nລາວ\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := lao.Segment("ສະບາຍດີປະເທດລາວ"); !reflect.DeepEqual(got, []string{"ສະບາຍດີ", "ປະເທດ", "ລາວ"}) {
+		t.Errorf("unexpected lao segmentation %q", got)
+	}
+}
+
+func TestSplitTextWordSegmentation(t *testing.T) {
+	err := initTesting()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf := setupDefaultA4PDF(t)
+
+	text := "ฉันรักเมืองไทย"
+	width, err := pdf.MeasureTextWidth("ฉันรัก")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines, err := pdf.SplitTextWithOption(text, width, &BreakOption{Mode: BreakModeWordSegmentation})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(lines, "") != text || lines[0] != "ฉันรัก" {
+		t.Fatalf("expected the lines to break between words, got %q", lines)
+	}
+}